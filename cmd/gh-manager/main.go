@@ -3,16 +3,21 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"gh-manager/internal/app"
@@ -31,11 +36,11 @@ import (
 
 func main() {
 	ctx := context.Background()
-	runner := app.ExecRunner{}
+	runner := newCommandRunner()
 	gh := github.NewClient(runner)
 
-	if len(os.Args) < 2 {
-		if err := runApp(ctx, gh, runner); err != nil {
+	if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-") {
+		if err := runApp(ctx, gh, runner, os.Args[1:]); err != nil {
 			fatal(err)
 		}
 		return
@@ -43,26 +48,53 @@ func main() {
 
 	switch os.Args[1] {
 	case "doctor":
-		if err := doctor.Check(ctx, runner); err != nil {
+		if err := runDoctor(ctx, runner, os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			fatal(err)
+		}
+	case "whoami":
+		if err := runWhoami(ctx, runner, os.Args[2:], os.Stdout); err != nil {
 			fatal(err)
 		}
-		fmt.Println("doctor: ok")
 	case "version":
-		fmt.Println(version.Value)
+		if err := runVersion(os.Args[2:], os.Stdout); err != nil {
+			fatal(err)
+		}
 	case "plan":
 		if err := runPlan(ctx, gh, runner, os.Args[2:]); err != nil {
 			fatal(err)
 		}
+	case "import":
+		if err := runImport(ctx, gh, runner, os.Args[2:], os.Stdout); err != nil {
+			fatal(err)
+		}
+	case "list":
+		if err := runList(ctx, gh, runner, os.Args[2:], os.Stdout); err != nil {
+			fatal(err)
+		}
+	case "stats":
+		if err := runStats(ctx, runner, os.Args[2:], os.Stdout); err != nil {
+			fatal(err)
+		}
 	case "inspect":
 		if err := runInspect(os.Args[2:]); err != nil {
 			fatal(err)
 		}
+	case "status":
+		if err := runStatus(os.Args[2:], os.Stdout); err != nil {
+			fatal(err)
+		}
 	case "execute":
-		if err := runExecute(ctx, gh, runner, os.Args[2:]); err != nil {
+		runCtx, stop := withInterrupt(ctx)
+		err := runExecute(runCtx, gh, runner, os.Args[2:], os.Stdout)
+		stop()
+		if err != nil {
 			fatal(err)
 		}
 	case "backup":
-		if err := runBackup(ctx, gh, runner, os.Args[2:]); err != nil {
+		runCtx, stop := withInterrupt(ctx)
+		err := runBackup(runCtx, gh, runner, os.Args[2:])
+		stop()
+		if err != nil {
 			fatal(err)
 		}
 	case "restore":
@@ -77,13 +109,35 @@ func main() {
 		if err := runTheme(ctx, os.Args[2:], os.Stdout); err != nil {
 			fatal(err)
 		}
+	case "config":
+		if err := runConfig(os.Args[2:], os.Stdout); err != nil {
+			fatal(err)
+		}
+	case "prune-archives":
+		if err := runPruneArchives(os.Args[2:], os.Stdin, os.Stdout, time.Now()); err != nil {
+			fatal(err)
+		}
 	default:
 		usage()
 		os.Exit(2)
 	}
 }
 
-func runApp(ctx context.Context, gh github.Client, runner app.CommandRunner) error {
+func runApp(ctx context.Context, gh github.Client, runner app.CommandRunner, args []string) error {
+	fs := flag.NewFlagSet("gh-manager", flag.ContinueOnError)
+	restoreSelection := fs.Bool("restore-selection", false, "Reselect repos saved with the last plan")
+	scanArchives := fs.Bool("scan-archives", false, "Mark repos found in local archives (also backup.scan_archives)")
+	refreshRepos := fs.Bool("refresh", false, "Refetch the repo list instead of using the cache (cache.repos_ttl_minutes)")
+	checkUpdate := fs.Bool("check-update", false, "Check for a new release now instead of reusing the last check (cache.update_check_hours)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	keybindings := loadKeybindings(os.Stderr)
+	host := app.ResolveHost("")
+	// Releases live on github.com, so update checks ignore GH_HOST.
+	releaseRunner := app.WithHost(runner, app.DefaultHost)
+	runner = app.WithHost(runner, host)
+	gh = github.NewClient(runner)
 	if err := doctor.Check(ctx, runner); err != nil {
 		return err
 	}
@@ -91,14 +145,36 @@ func runApp(ctx context.Context, gh github.Client, runner app.CommandRunner) err
 	if err != nil {
 		return err
 	}
-	repos, err := gh.ListUserRepos(ctx, actor)
+	repos, err := withRepoCache(gh, host, *refreshRepos).ListUserRepos(ctx, actor)
 	if err != nil {
 		return err
 	}
 	uiTheme := resolveUITheme(os.Stderr)
-	return tui.RunApp(repos, tui.AppCallbacks{
+	var initialSelection []string
+	if *restoreSelection {
+		initialSelection = loadSavedSelection(os.Stderr)
+	}
+	var archiveIndex func() (map[string]bool, error)
+	if *scanArchives || configuredScanArchives() {
+		archiveIndex = func() (map[string]bool, error) {
+			base, err := configuredBackupBase()
+			if err != nil {
+				return nil, err
+			}
+			home, _ := os.UserHomeDir()
+			return backedUpRepos([]string{preferredRestoreArchiveDir(), base, home})
+		}
+	}
+	autoPlan := func(selected []planfile.RepoRecord) (string, error) {
+		p, _, err := createSignedPlan(actor, host, "", selected, "", planGuards{}, time.Now())
+		return p, err
+	}
+	callbacks := tui.AppCallbacks{
 		Version:                  version.Value,
 		Theme:                    uiTheme,
+		Host:                     host,
+		InitialSelection:         initialSelection,
+		Keybindings:              keybindings,
 		RestoreDefaultOwner:      actor,
 		RestoreDefaultArchiveDir: preferredRestoreArchiveDir(),
 		ThemeCurrent: func() (string, error) {
@@ -116,103 +192,164 @@ func runApp(ctx context.Context, gh github.Client, runner app.CommandRunner) err
 		ThemeApply: func(id string) (tui.UITheme, string, error) {
 			return themeApply(id)
 		},
+		ThemePreview: func(id string) (tui.UITheme, error) {
+			return themePreview(id)
+		},
 		ThemeUninstall: func(id string) (tui.UITheme, string, error) {
 			return themeUninstall(id)
 		},
-		UpdateCheck: func() (tui.UpdateInfo, error) {
-			return checkLatestRelease(ctx, runner)
+		ArchiveIndex: archiveIndex,
+		FindBackup: func(repo planfile.RepoRecord) (string, bool, error) {
+			base, err := configuredBackupBase()
+			if err != nil {
+				return "", false, err
+			}
+			home, _ := os.UserHomeDir()
+			return findRepoBackup(repo.FullName, []string{preferredRestoreArchiveDir(), base, home})
 		},
-		UpdateRun: func() (string, error) {
-			return runSelfUpdate(ctx, runner)
+		OpenInBrowser: func(fullName string) error {
+			_, err := runner.Run(ctx, "gh", "repo", "view", fullName, "--web")
+			return err
 		},
 		RefreshRepos: func() ([]planfile.RepoRecord, error) {
-			return gh.ListUserRepos(ctx, actor)
+			return withRepoCache(gh, host, true).ListUserRepos(ctx, actor)
 		},
 		Plan: func(selected []planfile.RepoRecord, outPath string) (string, error) {
-			planPath, count, err := createSignedPlan(actor, selected, outPath, time.Now())
+			planPath, count, err := createSignedPlan(actor, host, "", selected, outPath, planGuards{}, time.Now())
 			if err != nil {
 				return "", err
 			}
+			saveSelection(selected, io.Discard)
 			return fmt.Sprintf("plan saved: %s (%d repos)", planPath, count), nil
 		},
 		Inspect: func(planPath string) (string, error) {
-			return inspectToString(planPath, "")
+			return inspectToString(planPath, "", "")
 		},
-		Backup: func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord) (string, error) {
-			var out bytes.Buffer
-			resolvedPlanPath := strings.TrimSpace(planPath)
-			if resolvedPlanPath == "" {
-				p, _, err := createSignedPlan(actor, selected, "", time.Now())
-				if err != nil {
-					return "", err
-				}
-				resolvedPlanPath = p
-				fmt.Fprintf(&out, "auto-generated plan: %s (%d repos)\n", resolvedPlanPath, len(selected))
+		ExportSelection: func(selected []planfile.RepoRecord, outPath string) (string, error) {
+			return exportSelection(selected, outPath, time.Now())
+		},
+		Backup: func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord, progress func(string)) (string, error) {
+			var buf bytes.Buffer
+			out := io.MultiWriter(&buf, &lineWriter{fn: progress})
+			resolvedPlanPath, err := taskPlanPath(planPath, selected, autoPlan, out)
+			if err != nil {
+				return "", err
 			}
-			err := runBackupTask(ctx, gh, runner, backupConfig{
+			err = runBackupTask(ctx, gh, runner, backupConfig{
 				PlanPath:       resolvedPlanPath,
+				Host:           host,
 				BackupLocation: backupLocation,
 				Resume:         true,
 				DryRun:         dryRun,
 				Confirmation:   confirmation,
-			}, strings.NewReader(confirmation+"\n"), &out)
-			return out.String(), err
+			}, strings.NewReader(confirmation+"\n"), out)
+			return buf.String(), err
 		},
-		Execute: func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord) (string, error) {
-			var out bytes.Buffer
-			resolvedPlanPath := strings.TrimSpace(planPath)
-			if resolvedPlanPath == "" {
-				p, _, err := createSignedPlan(actor, selected, "", time.Now())
-				if err != nil {
-					return "", err
-				}
-				resolvedPlanPath = p
-				fmt.Fprintf(&out, "auto-generated plan: %s (%d repos)\n", resolvedPlanPath, len(selected))
+		Execute: func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord, progress func(string)) (string, error) {
+			var buf bytes.Buffer
+			out := io.MultiWriter(&buf, &lineWriter{fn: progress})
+			resolvedPlanPath, err := taskPlanPath(planPath, selected, autoPlan, out)
+			if err != nil {
+				return "", err
 			}
-			err := runExecuteTask(ctx, gh, runner, executeConfig{
+			err = runExecuteTask(ctx, gh, runner, executeConfig{
 				PlanPath:       resolvedPlanPath,
+				Host:           host,
 				BackupLocation: backupLocation,
 				Resume:         true,
 				DryRun:         dryRun,
 				Confirmation:   confirmation,
-			}, strings.NewReader(confirmation+"\n"), &out)
-			return out.String(), err
+			}, strings.NewReader(confirmation+"\n"), out)
+			return buf.String(), err
 		},
 		Restore: func(req tui.RestoreRequest) (string, error) {
-			svc := restore.NewService(runner)
+			svc := restore.NewService(runner, host)
 			res, err := svc.Restore(ctx, restore.Request{
-				ArchiveRoot:      req.ArchiveRoot,
-				RepoFullName:     req.RepoFullName,
-				SourceKind:       req.SourceKind,
-				SourcePath:       req.SourcePath,
-				TargetOwner:      req.TargetOwner,
-				TargetName:       req.TargetName,
-				TargetVisibility: req.TargetVisibility,
+				ArchiveRoot:           req.ArchiveRoot,
+				RepoFullName:          req.RepoFullName,
+				SourceKind:            req.SourceKind,
+				SourcePath:            req.SourcePath,
+				SourceSHA256:          req.SourceSHA256,
+				TargetOwner:           req.TargetOwner,
+				TargetName:            req.TargetName,
+				TargetVisibility:      req.TargetVisibility,
+				Host:                  host,
+				SourceVisibility:      req.SourceVisibility,
+				AllowVisibilityChange: req.AllowVisibilityChange,
 			})
 			if err != nil {
 				return "", err
 			}
-			return fmt.Sprintf("restore complete: %s from %s (%s)\nworkdir: %s", res.TargetFullName, res.SourcePath, res.SourceKind, res.WorkDir), nil
+			var warnings bytes.Buffer
+			clearRepoCache(&warnings)
+			return warnings.String() + fmt.Sprintf("restore complete: %s from %s (%s)", res.TargetFullName, res.SourcePath, res.SourceKind), nil
 		},
 		Delete: func(repo planfile.RepoRecord) (string, error) {
 			if strings.TrimSpace(repo.FullName) == "" {
 				return "", errors.New("repository full name is empty")
 			}
+			if err := refuseProtected(repo.FullName); err != nil {
+				return "", err
+			}
 			if err := gh.DeleteRepo(ctx, repo.FullName); err != nil {
 				return "", err
 			}
-			return fmt.Sprintf("delete complete: %s", repo.FullName), nil
+			var warnings bytes.Buffer
+			clearRepoCache(&warnings)
+			return warnings.String() + fmt.Sprintf("delete complete: %s", repo.FullName), nil
 		},
-	})
+	}
+	if updateChecksEnabled() {
+		callbacks.UpdateCheck = updateChecker(ctx, releaseRunner, *checkUpdate)
+		callbacks.UpdateRun = func() (string, error) {
+			return runSelfUpdate(ctx, releaseRunner)
+		}
+	}
+	return tui.RunApp(repos, callbacks)
 }
 
 func runPlan(ctx context.Context, gh github.Client, runner app.CommandRunner, args []string) error {
 	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
 	owner := fs.String("owner", "", "GitHub owner (defaults to authenticated user)")
 	out := fs.String("out", "", "Output plan file path")
+	format := fs.String("format", "", "Plan file format: json|yaml (defaults to the --out extension, else json)")
+	restoreSelection := fs.Bool("restore-selection", false, "Reselect repos saved with the last plan")
+	fromFile := fs.String("from-file", "", "Select the repos listed in this file (one owner/name, glob or /regex/ per line) instead of opening the picker")
+	var rf repoFilterFlags
+	rf.register(fs)
+	var src repoSourceFlags
+	src.register(fs)
+	captureHead := fs.Bool("capture-head", false, "Record each selected repo's HEAD sha so execute can warn about new commits")
+	allowLarge := fs.Bool("allow-large", false, "Allow plans with more repos than max_plan_size")
+	allowProtected := fs.Bool("allow-protected", false, "Allow repos matching protected_repos into the plan (execute still never deletes them)")
+	secretFile := fs.String("secret-file", "", "Hex plan-signing secret to use instead of the config dir's secret.hex")
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST or github.com)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if *fromFile != "" && *restoreSelection {
+		return errors.New("--from-file and --restore-selection cannot be combined")
+	}
+	planOut, err := planOutPath(*out, *format, time.Now())
+	if err != nil {
+		return err
+	}
+	var fromNames []string
+	if *fromFile != "" {
+		if fromNames, err = planfile.ReadSelectionFile(*fromFile); err != nil {
+			return fmt.Errorf("read --from-file: %w", err)
+		}
+		if len(fromNames) == 0 {
+			return fmt.Errorf("--from-file %s lists no repos", *fromFile)
+		}
+	}
+	listOpts, err := src.options(*owner)
+	if err != nil {
+		return err
+	}
+	resolvedHost := app.ResolveHost(*host)
+	runner = app.WithHost(runner, resolvedHost)
+	gh = withRepoCache(github.NewClient(runner), resolvedHost, src.refresh)
 	if err := doctor.Check(ctx, runner); err != nil {
 		return err
 	}
@@ -220,33 +357,607 @@ func runPlan(ctx context.Context, gh github.Client, runner app.CommandRunner, ar
 	if err != nil {
 		return fmt.Errorf("fetch current user: %w", err)
 	}
-	repos, err := gh.ListUserRepos(ctx, *owner)
+	repos, err := gh.ListRepos(ctx, *owner, listOpts)
 	if err != nil {
 		return fmt.Errorf("list repositories: %w", err)
 	}
-	selected, err := tui.SelectReposWithTheme(repos, resolveUITheme(os.Stderr))
+	filters, err := rf.filters()
+	if err != nil {
+		return err
+	}
+	if len(filters) > 0 {
+		var dropped int
+		repos, dropped = planfile.FilterRepos(repos, filters...)
+		fmt.Fprintf(os.Stderr, "filtered out %d repos (%d remaining)\n", dropped, len(repos))
+	}
+	var selected []planfile.RepoRecord
+	if fromNames != nil {
+		selected, err = planfile.ExpandSelection(repos, fromNames)
+	} else {
+		var preselected []string
+		if *restoreSelection {
+			preselected = loadSavedSelection(os.Stderr)
+		}
+		selected, err = tui.SelectReposPreselected(repos, resolveUITheme(os.Stderr), loadKeybindings(os.Stderr), preselected)
+	}
 	if err != nil {
 		return err
 	}
-	planPath, count, err := createSignedPlan(actor, selected, *out, time.Now())
+	if *captureHead {
+		selected = captureHeads(ctx, gh, selected, os.Stderr)
+	}
+	planPath, count, err := createSignedPlan(actor, resolvedHost, *secretFile, selected, planOut, planGuards{AllowProtected: *allowProtected, AllowLarge: *allowLarge}, time.Now())
 	if err != nil {
 		return err
 	}
+	saveSelection(selected, os.Stderr)
 	fmt.Printf("plan saved: %s (%d repos)\n", planPath, count)
 	return nil
 }
 
+// lineWriter passes each complete line written to it to fn, so TUI callbacks
+// can stream executor output while it is still running.
+type lineWriter struct {
+	fn      func(string)
+	pending []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if w.fn == nil {
+		return len(p), nil
+	}
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.fn(string(w.pending[:i]))
+		w.pending = w.pending[i+1:]
+	}
+}
+
+// exportSelection writes the selected full names for a later plan --from-file.
+func exportSelection(selected []planfile.RepoRecord, outPath string, now time.Time) (string, error) {
+	if outPath == "" {
+		outPath = filepath.Join(".", "selection-"+now.Format("20060102-150405")+".txt")
+	}
+	names := make([]string, 0, len(selected))
+	for _, r := range selected {
+		names = append(names, r.FullName)
+	}
+	if err := planfile.WriteSelectionFile(outPath, names); err != nil {
+		return "", fmt.Errorf("write selection: %w", err)
+	}
+	return fmt.Sprintf("selection saved: %s (%d repos)\nreuse with: gh-manager plan --from-file %s", outPath, len(names), outPath), nil
+}
+
+// runImport builds a signed plan from a repo list exported by another tool,
+// reporting entries that match no repo.
+func runImport(ctx context.Context, gh github.Client, runner app.CommandRunner, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := fs.String("file", "", "Repo list exported from another tool")
+	format := fs.String("format", "", "Input format: list|csv (defaults to csv for .csv files, list otherwise)")
+	column := fs.String("column", "", "CSV column holding the repo name (defaults to fullName, full_name, nameWithOwner, repo, repository or name)")
+	owner := fs.String("owner", "", "Owner whose repos entries are matched against, and the owner of entries without one (defaults to authenticated user)")
+	planOut := fs.String("out", "", "Plan output path (.json or .yaml)")
+	allowLarge := fs.Bool("allow-large", false, "Allow plans with more repos than max_plan_size")
+	allowProtected := fs.Bool("allow-protected", false, "Allow repos matching protected_repos into the plan (execute still never deletes them)")
+	refreshRepos := fs.Bool("refresh", false, "Refetch the repo list instead of using the cache (cache.repos_ttl_minutes)")
+	secretFile := fs.String("secret-file", "", "Hex plan-signing secret to use instead of the config dir's secret.hex")
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST or github.com)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*file) == "" {
+		return errors.New("--file is required")
+	}
+	names, err := readImportList(*file, *format, *column)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%s lists no repos", *file)
+	}
+	resolvedHost := app.ResolveHost(*host)
+	runner = app.WithHost(runner, resolvedHost)
+	gh = withRepoCache(github.NewClient(runner), resolvedHost, *refreshRepos)
+	if err := doctor.Check(ctx, runner); err != nil {
+		return err
+	}
+	actor, err := gh.CurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch current user: %w", err)
+	}
+	listOwner := strings.TrimSpace(*owner)
+	if listOwner == "" {
+		listOwner = actor
+	}
+	repos, err := gh.ListUserRepos(ctx, listOwner)
+	if err != nil {
+		return fmt.Errorf("list repositories: %w", err)
+	}
+	matched, unmatched := matchImported(repos, names, listOwner)
+	for _, name := range unmatched {
+		fmt.Fprintf(out, "not found: %s\n", name)
+	}
+	if len(matched) == 0 {
+		return fmt.Errorf("none of the %d entries in %s matched a repo of %s", len(names), *file, listOwner)
+	}
+	planPath, count, err := createSignedPlan(actor, resolvedHost, *secretFile, matched, *planOut, planGuards{AllowProtected: *allowProtected, AllowLarge: *allowLarge}, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "imported %d of %d entries (%d not found)\n", len(names)-len(unmatched), len(names), len(unmatched))
+	fmt.Fprintf(out, "plan saved: %s (%d repos)\n", planPath, count)
+	return nil
+}
+
+// importColumns are the CSV headers import looks for, in order, when
+// --column is not given.
+var importColumns = []string{"fullName", "full_name", "nameWithOwner", "repo", "repository", "name"}
+
+// readImportList reads repo names from a plain list (one per line, # comments)
+// or from one column of a CSV file with a header row.
+func readImportList(path, format, column string) ([]string, error) {
+	if format == "" {
+		format = "list"
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			format = "csv"
+		}
+	}
+	switch format {
+	case "list":
+		if column != "" {
+			return nil, errors.New("--column only applies to --format csv")
+		}
+		return planfile.ReadSelectionFile(path)
+	case "csv":
+	default:
+		return nil, fmt.Errorf("--format must be list or csv, got %q", format)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	col := -1
+	wanted := importColumns
+	if column != "" {
+		wanted = []string{column}
+	}
+	for _, w := range wanted {
+		for i, h := range rows[0] {
+			if strings.EqualFold(strings.TrimSpace(h), w) {
+				col = i
+				break
+			}
+		}
+		if col >= 0 {
+			break
+		}
+	}
+	if col < 0 {
+		if column != "" {
+			return nil, fmt.Errorf("%s has no %q column", path, column)
+		}
+		return nil, fmt.Errorf("%s has no repo column (%s); pass --column", path, strings.Join(importColumns, ", "))
+	}
+	var names []string
+	for _, row := range rows[1:] {
+		if col < len(row) {
+			if v := strings.TrimSpace(row[col]); v != "" {
+				names = append(names, v)
+			}
+		}
+	}
+	return names, nil
+}
+
+// matchImported resolves imported names against repos, case-insensitively.
+// Entries may be owner/name, a bare name (taken as owner/name) or a clone
+// URL. Matches keep the order of names without duplicates; unmatched holds
+// the entries as written.
+func matchImported(repos []planfile.RepoRecord, names []string, owner string) (matched []planfile.RepoRecord, unmatched []string) {
+	byName := make(map[string]planfile.RepoRecord, len(repos))
+	for _, r := range repos {
+		byName[strings.ToLower(r.FullName)] = r
+	}
+	seen := map[string]bool{}
+	for _, name := range names {
+		key := strings.ToLower(importedFullName(name, owner))
+		r, ok := byName[key]
+		if !ok {
+			unmatched = append(unmatched, name)
+			continue
+		}
+		if !seen[key] {
+			seen[key] = true
+			matched = append(matched, r)
+		}
+	}
+	return matched, unmatched
+}
+
+func importedFullName(name, owner string) string {
+	name = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(name), "/"), ".git")
+	if _, rest, ok := strings.Cut(name, "://"); ok {
+		_, name, _ = strings.Cut(rest, "/")
+	} else if _, rest, ok := strings.Cut(name, "@"); ok {
+		_, name, _ = strings.Cut(rest, ":")
+	}
+	if !strings.Contains(name, "/") {
+		return owner + "/" + name
+	}
+	return name
+}
+
+// planOutPath reconciles --out and --format. planfile.Write picks the format
+// from the extension, so a YAML plan needs a .yaml/.yml path.
+func planOutPath(out, format string, now time.Time) (string, error) {
+	switch format {
+	case "":
+		return out, nil
+	case planfile.FormatJSON, planfile.FormatYAML:
+	default:
+		return "", fmt.Errorf("--format must be json or yaml, got %q", format)
+	}
+	if out == "" {
+		return filepath.Join(".", "deletion-plan-"+now.Format("20060102-150405")+"."+format), nil
+	}
+	if planfile.FormatForPath(out) != format {
+		return "", fmt.Errorf("--format %s does not match --out %s (use a .yaml/.yml extension for YAML)", format, out)
+	}
+	return out, nil
+}
+
+// repoSourceFlags controls which repos are fetched, as opposed to
+// repoFilterFlags which narrow them afterwards.
+type repoSourceFlags struct {
+	limit   int
+	source  string
+	refresh bool
+}
+
+func (f *repoSourceFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&f.limit, "limit", github.DefaultListLimit, "Maximum number of repos to fetch")
+	fs.StringVar(&f.source, "source", github.SourceOwner, "Repos to list: owner (owned by --owner) | member (collaborator/org member) | all")
+	fs.BoolVar(&f.refresh, "refresh", false, "Refetch the repo list instead of using the cache (cache.repos_ttl_minutes)")
+}
+
+func (f repoSourceFlags) options(owner string) (github.ListOptions, error) {
+	if f.limit < 1 {
+		return github.ListOptions{}, fmt.Errorf("--limit must be positive, got %d", f.limit)
+	}
+	source, err := github.ParseSource(f.source)
+	if err != nil {
+		return github.ListOptions{}, fmt.Errorf("--source: %w", err)
+	}
+	if owner != "" && source != github.SourceOwner {
+		return github.ListOptions{}, fmt.Errorf("--owner cannot be combined with --source %s (it lists the authenticated user's repos)", source)
+	}
+	return github.ListOptions{Limit: f.limit, Source: source}, nil
+}
+
+type repoFilterFlags struct {
+	excludeArchived bool
+	excludeForks    bool
+	updatedBefore   string
+	updatedAfter    string
+	unknownUpdated  string
+	visibility      string
+}
+
+func (f *repoFilterFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.excludeArchived, "exclude-archived", false, "Hide archived repos")
+	fs.BoolVar(&f.excludeForks, "exclude-forks", false, "Hide forks")
+	fs.StringVar(&f.updatedBefore, "updated-before", "", "Only repos last updated before this date (YYYY, YYYY-MM, YYYY-MM-DD or RFC3339)")
+	fs.StringVar(&f.updatedAfter, "updated-after", "", "Only repos last updated on or after this date")
+	fs.StringVar(&f.unknownUpdated, "unknown-updated", "exclude", "Repos with no usable updatedAt when a date filter is set: include|exclude")
+	fs.StringVar(&f.visibility, "visibility", "all", "Only private or only public repos: private|public|all")
+}
+
+func (f repoFilterFlags) filters() ([]planfile.RepoFilter, error) {
+	var out []planfile.RepoFilter
+	if f.excludeArchived {
+		out = append(out, planfile.ExcludeArchived)
+	}
+	if f.excludeForks {
+		out = append(out, planfile.ExcludeForks)
+	}
+	vis, err := planfile.VisibilityFilter(f.visibility)
+	if err != nil {
+		return nil, fmt.Errorf("--visibility: %w", err)
+	}
+	if vis != nil {
+		out = append(out, vis)
+	}
+	if f.updatedBefore == "" && f.updatedAfter == "" {
+		return out, nil
+	}
+	var before, after time.Time
+	if f.updatedBefore != "" {
+		if before, err = planfile.ParseDate(f.updatedBefore); err != nil {
+			return nil, fmt.Errorf("--updated-before: %w", err)
+		}
+	}
+	if f.updatedAfter != "" {
+		if after, err = planfile.ParseDate(f.updatedAfter); err != nil {
+			return nil, fmt.Errorf("--updated-after: %w", err)
+		}
+	}
+	if !before.IsZero() && !after.IsZero() && !after.Before(before) {
+		return nil, errors.New("--updated-after must be earlier than --updated-before")
+	}
+	switch f.unknownUpdated {
+	case "include", "exclude":
+	default:
+		return nil, fmt.Errorf("unsupported --unknown-updated value: %s", f.unknownUpdated)
+	}
+	return append(out, planfile.UpdatedBetween(after, before, f.unknownUpdated == "include")), nil
+}
+
+func runVersion(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	output := fs.String("output", outputText, "Output format: text|json (json adds Go version, OS/arch, commit and build date)")
+	jsonOut := fs.Bool("json", false, "Shorthand for --output json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *jsonOut {
+		if *output != outputText && *output != outputJSON {
+			return fmt.Errorf("--json cannot be combined with --output %s", *output)
+		}
+		*output = outputJSON
+	}
+	switch *output {
+	case outputText:
+		fmt.Fprintln(out, version.Value)
+		return nil
+	case outputJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(version.Current())
+	}
+	return fmt.Errorf("unsupported output format: %s", *output)
+}
+
+func runWhoami(ctx context.Context, runner app.CommandRunner, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("whoami", flag.ContinueOnError)
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST or github.com)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	resolvedHost := app.ResolveHost(*host)
+	runner = app.WithHost(runner, resolvedHost)
+	if err := doctor.Check(ctx, runner); err != nil {
+		return err
+	}
+	return printWhoami(ctx, github.NewClient(runner), resolvedHost, out)
+}
+
+func printWhoami(ctx context.Context, gh github.Client, host string, out io.Writer) error {
+	user, err := gh.CurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch current user: %w", err)
+	}
+	fmt.Fprintf(out, "logged in as %s on %s\n", user, host)
+	return nil
+}
+
+func runDoctor(ctx context.Context, runner app.CommandRunner, args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	output := fs.String("output", outputText, "Output format: text|json")
+	fix := fs.Bool("fix", false, "Offer to run the remediation for each failing check, then re-check")
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST or github.com)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *fix && *output != outputText {
+		return errors.New("--fix requires --output text")
+	}
+	resolvedHost := app.ResolveHost(*host)
+	runner = app.WithHost(runner, resolvedHost)
+	results := doctor.Diagnose(ctx, runner)
+	printed := false
+	if *fix && !allDoctorOK(results) {
+		printDoctorResults(out, results)
+		printed = true
+		if doctor.Fix(ctx, app.WithHost(app.InteractiveRunner{}, resolvedHost), results, in, out) > 0 {
+			fmt.Fprintln(out, "re-checking...")
+			results = doctor.Diagnose(ctx, runner)
+			printed = false
+		}
+	}
+	switch *output {
+	case outputText:
+		if !printed {
+			printDoctorResults(out, results)
+		}
+	case outputJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", *output)
+	}
+	if doctor.Failed(results) {
+		return errors.New("doctor: one or more checks failed")
+	}
+	if *output == outputText {
+		fmt.Fprintln(out, "doctor: ok")
+	}
+	return nil
+}
+
+func allDoctorOK(results []doctor.Result) bool {
+	for _, r := range results {
+		if r.Status != doctor.StatusOK {
+			return false
+		}
+	}
+	return true
+}
+
+func printDoctorResults(out io.Writer, results []doctor.Result) {
+	for _, r := range results {
+		fmt.Fprintf(out, "[%s] %s: %s\n", r.Status, r.Name, r.Message)
+		if r.Hint != "" {
+			fmt.Fprintf(out, "       fix: %s\n", r.Hint)
+		}
+	}
+}
+
+func runList(ctx context.Context, gh github.Client, runner app.CommandRunner, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	owner := fs.String("owner", "", "GitHub owner (defaults to authenticated user)")
+	var rf repoFilterFlags
+	rf.register(fs)
+	var src repoSourceFlags
+	src.register(fs)
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST or github.com)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	filters, err := rf.filters()
+	if err != nil {
+		return err
+	}
+	listOpts, err := src.options(*owner)
+	if err != nil {
+		return err
+	}
+	resolvedHost := app.ResolveHost(*host)
+	runner = app.WithHost(runner, resolvedHost)
+	gh = withRepoCache(github.NewClient(runner), resolvedHost, src.refresh)
+	repos, err := gh.ListRepos(ctx, *owner, listOpts)
+	if err != nil {
+		return fmt.Errorf("list repositories: %w", err)
+	}
+	repos, dropped := planfile.FilterRepos(repos, filters...)
+	fmt.Fprint(out, listToString(repos))
+	fmt.Fprintf(out, "%d repos (%d filtered out)\n", len(repos), dropped)
+	return nil
+}
+
+func runStats(ctx context.Context, runner app.CommandRunner, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	owner := fs.String("owner", "", "GitHub owner (defaults to authenticated user)")
+	var src repoSourceFlags
+	src.register(fs)
+	output := fs.String("output", outputText, "Output format: text|json")
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST or github.com)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output != outputText && *output != outputJSON {
+		return fmt.Errorf("unsupported output format: %s", *output)
+	}
+	listOpts, err := src.options(*owner)
+	if err != nil {
+		return err
+	}
+	resolvedHost := app.ResolveHost(*host)
+	gh := withRepoCache(github.NewClient(app.WithHost(runner, resolvedHost)), resolvedHost, src.refresh)
+	repos, err := gh.ListRepos(ctx, *owner, listOpts)
+	if err != nil {
+		return fmt.Errorf("list repositories: %w", err)
+	}
+	stats := planfile.SummarizeRepos(repos)
+	if *output == outputJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	fmt.Fprint(out, statsToString(stats))
+	return nil
+}
+
+func statsToString(s planfile.RepoStats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "repos:      %d\n", s.Total)
+	fmt.Fprintf(&b, "visibility: %d private, %d public\n", s.Private, s.Public)
+	fmt.Fprintf(&b, "origin:     %d sources, %d forks\n", s.Sources, s.Forks)
+	fmt.Fprintf(&b, "archived:   %d\n", s.Archived)
+	fmt.Fprintf(&b, "disk usage: %s (%d/%d repos report a size)\n", planfile.FormatDiskUsage(s.DiskUsage), s.SizedRepos, s.Total)
+	oldest, newest := s.Oldest, s.Newest
+	if oldest == "" {
+		oldest, newest = "-", "-"
+	}
+	fmt.Fprintf(&b, "oldest:     %s\n", oldest)
+	fmt.Fprintf(&b, "newest:     %s\n", newest)
+	return b.String()
+}
+
+func listToString(repos []planfile.RepoRecord) string {
+	var b strings.Builder
+	for _, r := range repos {
+		vis := "public"
+		if r.IsPrivate {
+			vis = "private"
+		}
+		updated := r.UpdatedAt
+		if updated == "" {
+			updated = "-"
+		}
+		var tags []string
+		if r.IsFork {
+			tags = append(tags, "fork")
+		}
+		if r.IsArchived {
+			tags = append(tags, "archived")
+		}
+		line := fmt.Sprintf("%s\t%s\t%s", r.FullName, vis, updated)
+		if len(tags) > 0 {
+			line += "\t" + strings.Join(tags, ",")
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
 func runInspect(args []string) error {
 	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
 	planPath := fs.String("plan", "", "Path to plan file")
 	manifestPath := fs.String("manifest", "", "Optional manifest path")
+	archiveRoot := fs.String("archive-root", "", "Summarize restorable repos in an archive folder instead of a plan")
+	format := fs.String("format", outputText, "Plan output format: text|csv")
+	secretFile := fs.String("secret-file", "", "Hex plan-signing secret to use instead of the config dir's secret.hex")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if *format != outputText && *format != "csv" {
+		return fmt.Errorf("unsupported format %q (want text or csv)", *format)
+	}
+	if *archiveRoot != "" {
+		if *planPath != "" {
+			return errors.New("use either --plan or --archive-root, not both")
+		}
+		out, err := inspectArchiveToString(*archiveRoot)
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	}
 	if *planPath == "" {
-		return errors.New("--plan is required")
+		return errors.New("--plan or --archive-root is required")
+	}
+	if *format == "csv" {
+		return inspectCSV(*planPath, os.Stdout)
 	}
-	out, err := inspectToString(*planPath, *manifestPath)
+	out, err := inspectToString(*planPath, *manifestPath, *secretFile)
 	if err != nil {
 		return err
 	}
@@ -254,64 +965,411 @@ func runInspect(args []string) error {
 	return nil
 }
 
-func runExecute(ctx context.Context, gh github.Client, runner app.CommandRunner, args []string) error {
+func runStatus(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	backupRoot := fs.String("backup-root", "", "Backup root holding the manifest.json to summarize")
+	output := fs.String("output", outputText, "Output format: text|json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output != outputText && *output != outputJSON {
+		return fmt.Errorf("unsupported output format: %s", *output)
+	}
+	if strings.TrimSpace(*backupRoot) == "" {
+		return errors.New("--backup-root is required")
+	}
+	path := manifest.Path(*backupRoot)
+	m, err := manifest.Read(path)
+	if err != nil {
+		return err
+	}
+	summary := summarizeManifest(path, m)
+	if *output == outputJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	}
+	fmt.Fprint(out, statusToString(summary))
+	return nil
+}
+
+type manifestStatus struct {
+	ManifestPath  string           `json:"manifestPath"`
+	Mode          string           `json:"mode"`
+	UpdatedAt     string           `json:"updatedAt"`
+	Total         int              `json:"total"`
+	Deleted       int              `json:"deleted"`
+	Failed        int              `json:"failed"`
+	Status        map[string]int   `json:"status"`
+	ArchiveStatus map[string]int   `json:"archiveStatus"`
+	FailedRepos   []repoRunSummary `json:"failedRepos"`
+}
+
+// summarizeManifest groups manifest entries by status and archive status.
+// Failed repos are those counted by RecomputeCounters plus archive failures.
+func summarizeManifest(path string, m manifest.ExecutionManifestV1) manifestStatus {
+	m.RecomputeCounters()
+	s := manifestStatus{
+		ManifestPath:  path,
+		Mode:          m.Mode,
+		UpdatedAt:     m.UpdatedAt,
+		Total:         len(m.RepoExecutions),
+		Deleted:       m.DeletedCount,
+		Status:        map[string]int{},
+		ArchiveStatus: map[string]int{},
+		FailedRepos:   []repoRunSummary{},
+	}
+	for _, e := range m.RepoExecutions {
+		s.Status[string(e.Status)]++
+		archive := e.ArchiveStatus
+		if archive == "" {
+			archive = "none"
+		}
+		s.ArchiveStatus[archive]++
+		if executor.FailedEntry(e) {
+			s.FailedRepos = append(s.FailedRepos, repoRunSummary{
+				FullName:      e.FullName,
+				Status:        string(e.Status),
+				ArchiveStatus: e.ArchiveStatus,
+				Error:         e.Error,
+			})
+		}
+	}
+	s.Failed = len(s.FailedRepos)
+	return s
+}
+
+func statusToString(s manifestStatus) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "manifest:  %s\n", s.ManifestPath)
+	fmt.Fprintf(&b, "mode:      %s\n", s.Mode)
+	fmt.Fprintf(&b, "updatedAt: %s\n", s.UpdatedAt)
+	fmt.Fprintf(&b, "repos:     %d (%d deleted, %d failed)\n", s.Total, s.Deleted, s.Failed)
+	writeCounts := func(title string, counts map[string]int) {
+		fmt.Fprintln(&b, title+":")
+		keys := make([]string, 0, len(counts))
+		for k := range counts {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "  %s: %d\n", k, counts[k])
+		}
+	}
+	writeCounts("status", s.Status)
+	writeCounts("archiveStatus", s.ArchiveStatus)
+	if len(s.FailedRepos) == 0 {
+		fmt.Fprintln(&b, "failed: none")
+		return b.String()
+	}
+	fmt.Fprintln(&b, "failed:")
+	for _, r := range s.FailedRepos {
+		state := r.Status
+		if r.ArchiveStatus == "archive_failed" {
+			state += ", archive_failed"
+		}
+		errText := r.Error
+		if errText == "" {
+			errText = "-"
+		}
+		fmt.Fprintf(&b, "- %s [%s]: %s\n", r.FullName, state, errText)
+	}
+	return b.String()
+}
+
+func runExecute(ctx context.Context, gh github.Client, runner app.CommandRunner, args []string, out io.Writer) error {
 	fs := flag.NewFlagSet("execute", flag.ContinueOnError)
 	planPath := fs.String("plan", "", "Path to plan file")
+	all := fs.Bool("all", false, "With --two-phase: plan every repo (after --owner and filter flags) instead of --plan")
+	fromFile := fs.String("from-file", "", "With --two-phase: plan the repos listed in this file (one owner/name, glob or /regex/ per line) instead of --plan")
+	owner := fs.String("owner", "", "With --all/--from-file: GitHub owner (defaults to authenticated user)")
+	refreshRepos := fs.Bool("refresh", false, "With --all/--from-file: refetch the repo list instead of using the cache (cache.repos_ttl_minutes)")
+	allowLarge := fs.Bool("allow-large", false, "With --all/--from-file: allow a plan larger than max_plan_size")
+	var rf repoFilterFlags
+	rf.register(fs)
 	backupDir := fs.String("backup-dir", "", "Override backup directory (deprecated: use --backup-location)")
 	backupLocation := fs.String("backup-location", "", "Override backup location")
 	resume := fs.Bool("resume", true, "Resume from existing manifest if available")
+	resumeFrom := fs.String("resume-from", "", "Extra directory to search for a resumable manifest")
+	onlyFailed := fs.Bool("only-failed", false, "On resume, retry only entries that failed (backup, delete or archive)")
 	dryRun := fs.Bool("dry-run", false, "Show actions without making changes")
+	backupThenDelete := fs.Bool("backup-then-delete", false, "Delete a repo only after its mirror and bundle pass `git bundle verify`")
+	twoPhase := fs.Bool("two-phase", false, "Print the plan and its fingerprint and stop; run again with --confirm-fingerprint to execute")
+	confirmFingerprint := fs.String("confirm-fingerprint", "", "Execute only if the plan's fingerprint matches this value")
+	confirmMode := fs.String("confirm-mode", executor.ConfirmPhrase, "Confirmation gate: phrase|count")
+	confirmPhrase := fs.String("confirm-phrase", "", "Custom confirmation phrase (replaces ACCEPT/CONFIRM)")
+	yes := fs.Bool("yes", false, "Skip the confirmation prompt (also GH_MANAGER_ASSUME_YES=1)")
+	output := fs.String("output", outputText, "Summary output format: text|json")
+	printCommands := fs.Bool("print-commands", false, "Echo the gh/git commands that would run instead of running them")
+	logFile := fs.String("log-file", "", "Append per-repo JSONL events to this file (relative paths live in the backup root)")
+	manifestOut := fs.String("manifest-out", "", "Also write a copy of the execution manifest to this path")
+	opTimeout := fs.Duration("op-timeout", 0, "Fail a repo when one backup/snapshot/bundle/delete step runs longer than this (e.g. 30m; 0 disables)")
+	secretFile := fs.String("secret-file", "", "Hex plan-signing secret to use instead of the config dir's secret.hex")
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST, then the plan's host)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
-	return runExecuteTask(ctx, gh, runner, executeConfig{
-		PlanPath:       *planPath,
-		BackupDir:      *backupDir,
-		BackupLocation: *backupLocation,
-		Resume:         *resume,
-		DryRun:         *dryRun,
-	}, os.Stdin, os.Stdout)
+	resolvedHost := executionHost(*host, *planPath)
+	runner = app.WithHost(runner, resolvedHost)
+	if *all || *fromFile != "" {
+		if *planPath != "" {
+			return errors.New("--all/--from-file and --plan are mutually exclusive")
+		}
+		if !*twoPhase || strings.TrimSpace(*confirmFingerprint) != "" {
+			return errors.New("--all/--from-file need --two-phase so the saved plan is reviewed before it runs")
+		}
+		filters, err := rf.filters()
+		if err != nil {
+			return err
+		}
+		var selection []string
+		if *fromFile != "" {
+			if selection, err = planfile.ReadSelectionFile(*fromFile); err != nil {
+				return fmt.Errorf("read --from-file: %w", err)
+			}
+			if len(selection) == 0 {
+				return fmt.Errorf("--from-file %s lists no repos", *fromFile)
+			}
+		}
+		if err := doctor.Check(ctx, runner); err != nil {
+			return err
+		}
+		// Protected repos stay in the plan; the executor skips them.
+		guards := planGuards{AllowProtected: true, AllowLarge: *allowLarge}
+		p, err := planAllRepos(ctx, withRepoCache(github.NewClient(runner), resolvedHost, *refreshRepos), *owner, resolvedHost, *secretFile, filters, selection, guards, time.Now(), out)
+		if err != nil {
+			return err
+		}
+		*planPath = p
+	}
+	cfg := executeConfig{
+		PlanPath:           *planPath,
+		Host:               resolvedHost,
+		BackupDir:          *backupDir,
+		BackupLocation:     *backupLocation,
+		Resume:             *resume,
+		ResumeFrom:         *resumeFrom,
+		OnlyFailed:         *onlyFailed,
+		DryRun:             *dryRun,
+		BackupThenDelete:   *backupThenDelete,
+		ConfirmationMode:   *confirmMode,
+		ConfirmationPhrase: *confirmPhrase,
+		AssumeYes:          *yes || assumeYesFromEnv(),
+		Output:             *output,
+		LogPath:            *logFile,
+		ManifestOut:        *manifestOut,
+		OpTimeout:          *opTimeout,
+		SecretFile:         *secretFile,
+		TwoPhase:           *twoPhase,
+		ConfirmFingerprint: strings.TrimSpace(*confirmFingerprint),
+	}
+	if *printCommands {
+		scratch, wrapped, err := printCommandsRunner(runner, *dryRun, *output)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(scratch)
+		runner = wrapped
+		cfg.BackupDir, cfg.BackupLocation, cfg.Resume, cfg.ResumeFrom, cfg.AssumeYes = "", scratch, false, "", true
+	}
+	gh = github.NewClient(runner)
+	return runExecuteTask(ctx, gh, runner, cfg, os.Stdin, out)
 }
 
 func runBackup(ctx context.Context, gh github.Client, runner app.CommandRunner, args []string) error {
 	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
 	planPath := fs.String("plan", "", "Path to plan file")
+	all := fs.Bool("all", false, "Plan every repo (after --owner and filter flags) and back them up, instead of --plan")
+	owner := fs.String("owner", "", "With --all: GitHub owner (defaults to authenticated user)")
+	// --refresh already fetches into existing mirrors here, so the cache
+	// bypass keeps a distinct name.
+	refreshRepos := fs.Bool("refresh-repos", false, "With --all: refetch the repo list instead of using the cache (cache.repos_ttl_minutes)")
+	var rf repoFilterFlags
+	rf.register(fs)
 	backupDir := fs.String("backup-dir", "", "Override backup directory (deprecated: use --backup-location)")
 	backupLocation := fs.String("backup-location", "", "Override backup location")
 	resume := fs.Bool("resume", true, "Resume from existing manifest if available")
+	resumeFrom := fs.String("resume-from", "", "Extra directory to search for a resumable manifest")
+	onlyFailed := fs.Bool("only-failed", false, "On resume, retry only entries that failed (backup, delete or archive)")
 	dryRun := fs.Bool("dry-run", false, "Show actions without making changes")
 	archiveRepo := fs.String("archive-repo", "", "Archive repository (owner/name)")
 	archiveBranch := fs.String("archive-branch", "main", "Archive branch name")
-	archiveVisibility := fs.String("archive-visibility", "private", "Archive repo visibility: private|public")
+	archiveVisibility := fs.String("archive-visibility", "private", "Archive repo visibility: private|public|internal")
 	noArchive := fs.Bool("no-archive", false, "Disable archive publishing")
+	keepMirror := fs.Bool("keep-mirror", true, "Keep mirror clones after the bundle and snapshot are created")
+	noSnapshot := fs.Bool("no-snapshot", false, "Skip the browsable working clone (restore uses the bundle)")
+	refresh := fs.Bool("refresh", false, "Fetch updates into existing mirrors (git remote update --prune) before bundling")
+	compress := fs.Bool("compress", false, "Gzip bundles after creating them (.bundle.gz)")
+	includeLFS := fs.Bool("include-lfs", false, "Fetch Git LFS objects alongside each mirror (requires git-lfs)")
+	confirmMode := fs.String("confirm-mode", executor.ConfirmPhrase, "Confirmation gate: phrase|count")
+	confirmPhrase := fs.String("confirm-phrase", "", "Custom confirmation phrase (replaces ACCEPT/CONFIRM)")
+	yes := fs.Bool("yes", false, "Skip the confirmation prompt (also GH_MANAGER_ASSUME_YES=1)")
+	output := fs.String("output", outputText, "Summary output format: text|json")
+	printCommands := fs.Bool("print-commands", false, "Echo the gh/git commands that would run instead of running them")
+	logFile := fs.String("log-file", "", "Append per-repo JSONL events to this file (relative paths live in the backup root)")
+	manifestOut := fs.String("manifest-out", "", "Also write a copy of the execution manifest to this path")
+	opTimeout := fs.Duration("op-timeout", 0, "Fail a repo when one backup/snapshot/bundle/delete step runs longer than this (e.g. 30m; 0 disables)")
+	secretFile := fs.String("secret-file", "", "Hex plan-signing secret to use instead of the config dir's secret.hex")
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST, then the plan's host)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
-	return runBackupTask(ctx, gh, runner, backupConfig{
-		PlanPath:          *planPath,
-		BackupDir:         *backupDir,
-		BackupLocation:    *backupLocation,
-		Resume:            *resume,
-		DryRun:            *dryRun,
-		ArchiveRepo:       *archiveRepo,
-		ArchiveBranch:     *archiveBranch,
-		ArchiveVisibility: *archiveVisibility,
-		NoArchive:         *noArchive,
-	}, os.Stdin, os.Stdout)
+	resolvedHost := executionHost(*host, *planPath)
+	runner = app.WithHost(runner, resolvedHost)
+	if *all {
+		if *planPath != "" {
+			return errors.New("--all and --plan are mutually exclusive")
+		}
+		filters, err := rf.filters()
+		if err != nil {
+			return err
+		}
+		if err := doctor.Check(ctx, runner); err != nil {
+			return err
+		}
+		notes := io.Writer(os.Stdout)
+		if *output == outputJSON {
+			notes = os.Stderr
+		}
+		p, err := planAllRepos(ctx, withRepoCache(github.NewClient(runner), resolvedHost, *refreshRepos), *owner, resolvedHost, *secretFile, filters, nil, planGuards{AllowProtected: true, AllowLarge: true}, time.Now(), notes)
+		if err != nil {
+			return err
+		}
+		*planPath = p
+	}
+	cfg := backupConfig{
+		PlanPath:           *planPath,
+		Host:               resolvedHost,
+		BackupDir:          *backupDir,
+		BackupLocation:     *backupLocation,
+		Resume:             *resume,
+		ResumeFrom:         *resumeFrom,
+		OnlyFailed:         *onlyFailed,
+		DryRun:             *dryRun,
+		ArchiveRepo:        *archiveRepo,
+		ArchiveBranch:      *archiveBranch,
+		ArchiveVisibility:  *archiveVisibility,
+		NoArchive:          *noArchive,
+		PruneMirror:        !*keepMirror,
+		NoSnapshot:         *noSnapshot,
+		Refresh:            *refresh,
+		Compress:           *compress,
+		IncludeLFS:         *includeLFS,
+		ConfirmationMode:   *confirmMode,
+		ConfirmationPhrase: *confirmPhrase,
+		AssumeYes:          *yes || assumeYesFromEnv(),
+		Output:             *output,
+		LogPath:            *logFile,
+		ManifestOut:        *manifestOut,
+		OpTimeout:          *opTimeout,
+		SecretFile:         *secretFile,
+	}
+	if *printCommands {
+		scratch, wrapped, err := printCommandsRunner(runner, *dryRun, *output)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(scratch)
+		runner = wrapped
+		cfg.BackupDir, cfg.BackupLocation, cfg.Resume, cfg.ResumeFrom, cfg.AssumeYes = "", scratch, false, "", true
+		cfg.PrintCommands = true
+	}
+	gh = github.NewClient(runner)
+	return runBackupTask(ctx, gh, runner, cfg, os.Stdin, os.Stdout)
+}
+
+// planAllRepos signs a plan covering every listed repo that passes filters
+// (narrowed to selection when it is non-nil), for `backup --all` and the first
+// phase of `execute --two-phase`, and reports where it was written.
+func planAllRepos(ctx context.Context, gh github.Client, owner, host, secretFile string, filters []planfile.RepoFilter, selection []string, guards planGuards, now time.Time, out io.Writer) (string, error) {
+	actor, err := gh.CurrentUser(ctx)
+	if err != nil {
+		return "", fmt.Errorf("fetch current user: %w", err)
+	}
+	repos, err := gh.ListUserRepos(ctx, owner)
+	if err != nil {
+		return "", fmt.Errorf("list repositories: %w", err)
+	}
+	if selection != nil {
+		if repos, err = planfile.ExpandSelection(repos, selection); err != nil {
+			return "", err
+		}
+	}
+	repos, dropped := planfile.FilterRepos(repos, filters...)
+	if dropped > 0 {
+		fmt.Fprintf(out, "filtered out %d repos (%d remaining)\n", dropped, len(repos))
+	}
+	planPath, count, err := createSignedPlan(actor, host, secretFile, repos, "", guards, now)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(out, "auto-generated plan: %s (%d repos)\n", planPath, count)
+	return planPath, nil
+}
+
+// printCommandsRunner wraps runner so mutating gh/git commands are echoed
+// rather than run, and creates a scratch backup location so the executor's
+// manifest never lands next to real backups or gets resumed later.
+func printCommandsRunner(runner app.CommandRunner, dryRun bool, output string) (string, app.CommandRunner, error) {
+	if dryRun {
+		return "", nil, errors.New("use either --dry-run or --print-commands, not both")
+	}
+	out, err := progressWriter(output, os.Stdout)
+	if err != nil {
+		return "", nil, err
+	}
+	scratch, err := os.MkdirTemp("", "gh-manager-print-commands-*")
+	if err != nil {
+		return "", nil, err
+	}
+	fmt.Fprintf(out, "print-commands: commands below are echoed, not run; scratch backup location %s\n", scratch)
+	return scratch, &app.DryRunner{Runner: runner, Out: out}, nil
 }
 
 func runRestore(ctx context.Context, gh github.Client, runner app.CommandRunner, args []string) error {
 	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
 	archiveRoot := fs.String("archive-root", "", "Archive root folder")
 	repoName := fs.String("repo", "", "Source full repo name (owner/name) from archive")
+	all := fs.Bool("all", false, "Restore every repo in the archive index instead of --repo")
+	parallel := fs.Int("parallel", 1, "With --all: number of restores to run at once")
+	list := fs.Bool("list", false, "Print restorable repos in --archive-root with their preferred source, then exit")
 	targetOwner := fs.String("target-owner", "", "Target owner (defaults to authenticated user)")
 	targetName := fs.String("target-name", "", "Target repository name (defaults to source name)")
-	visibility := fs.String("visibility", "private", "Target visibility: private|public")
+	keepWorkDir := fs.Bool("workdir-keep", false, "Keep the temporary clone after a successful restore (failed restores always keep it)")
+	nameTemplate := fs.String("name-template", "", "Target name template with {owner} and {name}, e.g. {owner}-{name}-restored; conflicts get a numeric suffix")
+	namePrefix := fs.String("name-prefix", "", "Prepend this to each source repo name; conflicts get a numeric suffix")
+	nameSuffix := fs.String("name-suffix", "", "Append this to each source repo name; conflicts get a numeric suffix")
+	visibility := fs.String("visibility", "private", "Target visibility: private|public|internal")
+	allowVisibility := fs.Bool("allow-visibility-change", false, "Allow restoring with a more permissive visibility than the source had (e.g. private as public)")
+	includeLFS := fs.Bool("include-lfs", false, "Push stored Git LFS objects before the refs (requires git-lfs)")
+	targetBranch := fs.String("target-branch", "", "Default branch for the restored repo (defaults to the source's HEAD branch)")
+	printCommands := fs.Bool("print-commands", false, "Echo the gh/git commands that would run instead of running them")
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST or github.com)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
-	if strings.TrimSpace(*archiveRoot) == "" || strings.TrimSpace(*repoName) == "" {
-		return errors.New("--archive-root and --repo are required")
+	resolvedHost := app.ResolveHost(*host)
+	runner = app.WithHost(runner, resolvedHost)
+	if *printCommands {
+		fmt.Println("print-commands: commands below are echoed, not run")
+		runner = &app.DryRunner{Runner: runner, Out: os.Stdout}
+	}
+	gh = github.NewClient(runner)
+	if strings.TrimSpace(*archiveRoot) == "" {
+		return errors.New("--archive-root is required")
+	}
+	if *list {
+		return listRestorable(*archiveRoot, os.Stdout)
+	}
+	if *all == (strings.TrimSpace(*repoName) != "") {
+		return errors.New("pass exactly one of --repo or --all")
+	}
+	if *parallel < 1 {
+		return errors.New("--parallel must be at least 1")
+	}
+	template, err := restoreNameTemplate(strings.TrimSpace(*targetName), *nameTemplate, *namePrefix, *nameSuffix, *all)
+	if err != nil {
+		return err
 	}
 	owner := strings.TrimSpace(*targetOwner)
 	if owner == "" {
@@ -321,65 +1379,153 @@ func runRestore(ctx context.Context, gh github.Client, runner app.CommandRunner,
 		}
 		owner = u
 	}
-	name := strings.TrimSpace(*targetName)
-	if name == "" {
-		name = repoBasename(*repoName)
-	}
 
 	entries, err := restore.LoadIndex(*archiveRoot)
 	if err != nil {
 		return err
 	}
-	var selected restore.ArchiveEntry
-	found := false
-	for _, e := range entries {
-		if e.FullName == *repoName {
-			selected = e
-			found = true
-			break
+	if !*all {
+		found := false
+		for _, e := range entries {
+			if e.FullName == *repoName {
+				entries = []restore.ArchiveEntry{e}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("repo not found in archive index: %s", *repoName)
 		}
+	} else if len(entries) == 0 {
+		return fmt.Errorf("archive index is empty: %s", *archiveRoot)
 	}
-	if !found {
-		return fmt.Errorf("repo not found in archive index: %s", *repoName)
+	if *includeLFS && !doctor.GitLFSAvailable() {
+		for _, e := range entries {
+			if e.LFSPath != "" {
+				return fmt.Errorf("%s has LFS objects but git-lfs is not in PATH", e.FullName)
+			}
+		}
 	}
-	src, ok := restore.PreferredSource(selected)
-	if !ok {
-		return fmt.Errorf("repo has no valid restore source: %s", *repoName)
-	}
-
-	svc := restore.NewService(runner)
-	res, err := svc.Restore(ctx, restore.Request{
-		ArchiveRoot:      *archiveRoot,
-		RepoFullName:     selected.FullName,
-		SourceKind:       src.Kind,
-		SourcePath:       src.Path,
-		TargetOwner:      owner,
-		TargetName:       name,
-		TargetVisibility: *visibility,
-	})
-	if err != nil {
-		return err
+
+	failed := 0
+	reqs := make([]restore.Request, 0, len(entries))
+	for _, selected := range entries {
+		src, ok := restore.PreferredSource(selected)
+		if !ok {
+			err := fmt.Errorf("repo has no valid restore source: %s", selected.FullName)
+			if !*all {
+				return err
+			}
+			failed++
+			fmt.Printf("restore failed: %s: %v\n", selected.FullName, err)
+			continue
+		}
+		lfsPath := ""
+		if *includeLFS {
+			lfsPath = selected.LFSPath
+		}
+		name := strings.TrimSpace(*targetName)
+		if name == "" {
+			name = repoBasename(selected.FullName)
+		}
+		reqs = append(reqs, restore.Request{
+			ArchiveRoot:           *archiveRoot,
+			RepoFullName:          selected.FullName,
+			SourceKind:            src.Kind,
+			SourcePath:            src.Path,
+			SourceSHA256:          src.SHA256,
+			TargetOwner:           owner,
+			TargetName:            name,
+			TargetVisibility:      *visibility,
+			LFSPath:               lfsPath,
+			TargetBranch:          *targetBranch,
+			NameTemplate:          template,
+			Host:                  resolvedHost,
+			KeepWorkDir:           *keepWorkDir,
+			SourceVisibility:      selected.Visibility,
+			AllowVisibilityChange: *allowVisibility,
+		})
+	}
+	restored := 0
+	defer func() {
+		if restored > 0 {
+			clearRepoCache(os.Stdout)
+		}
+	}()
+	for _, r := range restore.NewService(runner, resolvedHost).RestoreAll(ctx, reqs, *parallel) {
+		err := r.Err
+		var downgrade restore.VisibilityDowngradeError
+		if errors.As(err, &downgrade) {
+			err = fmt.Errorf("%w; rerun with --allow-visibility-change to proceed", err)
+		}
+		if err == nil {
+			restored++
+			res := r.Result
+			fmt.Printf("restore complete: %s from %s (%s)\n", res.TargetFullName, res.SourcePath, res.SourceKind)
+			if res.DefaultBranch != "" {
+				fmt.Printf("default branch: %s\n", res.DefaultBranch)
+			}
+			if res.WorkDir != "" {
+				fmt.Printf("workdir: %s\n", res.WorkDir)
+			}
+			continue
+		}
+		if !*all {
+			return err
+		}
+		failed++
+		fmt.Printf("restore failed: %s: %v\n", r.Request.RepoFullName, err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d restores failed", failed, len(entries))
 	}
-	fmt.Printf("restore complete: %s from %s (%s)\n", res.TargetFullName, res.SourcePath, res.SourceKind)
-	fmt.Printf("workdir: %s\n", res.WorkDir)
 	return nil
 }
 
+// restoreNameTemplate turns the naming flags into a restore name template.
+// --name-prefix/--name-suffix wrap {name}; --all with no naming flag uses
+// {name} so conflicts get a numeric suffix instead of stopping the batch.
+func restoreNameTemplate(targetName, template, prefix, suffix string, all bool) (string, error) {
+	affix := prefix != "" || suffix != ""
+	switch {
+	case targetName != "" && (template != "" || affix):
+		return "", errors.New("use either --target-name or --name-template/--name-prefix/--name-suffix, not both")
+	case template != "" && affix:
+		return "", errors.New("use either --name-template or --name-prefix/--name-suffix, not both")
+	case targetName != "" && all:
+		return "", errors.New("--target-name cannot be combined with --all")
+	case affix:
+		return prefix + "{name}" + suffix, nil
+	case template == "" && all:
+		return "{name}", nil
+	}
+	return template, nil
+}
+
 func runDelete(ctx context.Context, gh github.Client, runner app.CommandRunner, args []string, in io.Reader, out io.Writer) error {
 	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
 	repo := fs.String("repo", "", "Repository full name (owner/name)")
 	force := fs.Bool("force", false, "Skip warning prompt and delete immediately")
+	yes := fs.Bool("yes", false, "Skip the confirmation prompt (also GH_MANAGER_ASSUME_YES=1)")
+	host := fs.String("host", "", "GitHub host (defaults to GH_HOST or github.com)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	runner = app.WithHost(runner, app.ResolveHost(*host))
+	gh = github.NewClient(runner)
 	fullName := strings.TrimSpace(*repo)
 	if fullName == "" {
 		return errors.New("--repo is required")
 	}
+	if err := refuseProtected(fullName); err != nil {
+		return err
+	}
 	if err := doctor.Check(ctx, runner); err != nil {
 		return err
 	}
-	if !*force {
+	if *yes || assumeYesFromEnv() {
+		fmt.Fprintln(out, "WARNING: confirmation bypassed via --yes")
+	} else if !*force {
 		base := repoBasename(fullName)
 		fmt.Fprintf(out, "WARNING: deleting %s without backup can permanently lose data.\n", fullName)
 		fmt.Fprintf(out, "Type %q to confirm delete: ", base)
@@ -394,15 +1540,197 @@ func runDelete(ctx context.Context, gh github.Client, runner app.CommandRunner,
 	if err := gh.DeleteRepo(ctx, fullName); err != nil {
 		return err
 	}
+	clearRepoCache(out)
 	fmt.Fprintf(out, "delete complete: %s\n", fullName)
 	return nil
 }
 
+// listRestorable prints one "fullName<TAB>kind<TAB>path" line per archived repo.
+func listRestorable(archiveRoot string, out io.Writer) error {
+	entries, err := restore.LoadIndex(archiveRoot)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(out, "no restorable repos in %s\n", archiveRoot)
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].FullName < entries[j].FullName })
+	for _, e := range entries {
+		src, ok := restore.PreferredSource(e)
+		if !ok {
+			fmt.Fprintf(out, "%s\t-\t(no valid source)\n", e.FullName)
+			continue
+		}
+		fmt.Fprintf(out, "%s\t%s\t%s\n", e.FullName, src.Kind, src.Path)
+	}
+	return nil
+}
+
+func runPruneArchives(args []string, in io.Reader, out io.Writer, now time.Time) error {
+	fs := flag.NewFlagSet("prune-archives", flag.ContinueOnError)
+	olderThan := fs.String("older-than", "", "Prune backup roots last updated longer ago than this (e.g. 30d, 12h)")
+	keep := fs.Int("keep", 0, "Prune all but the N most recent backup roots")
+	dir := fs.String("dir", "", "Extra directory to scan for gh-manager-archive-* roots")
+	dryRun := fs.Bool("dry-run", true, "Only list what would be pruned (pass --dry-run=false to delete)")
+	force := fs.Bool("force", false, "Also prune roots whose manifest is missing or has pending/failed entries")
+	yes := fs.Bool("yes", false, "Skip the confirmation prompt (also GH_MANAGER_ASSUME_YES=1)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		return fmt.Errorf("--older-than: %w", err)
+	}
+	if *keep < 0 {
+		return fmt.Errorf("--keep must not be negative, got %d", *keep)
+	}
+	if age == 0 && *keep == 0 {
+		return errors.New("set --older-than and/or --keep")
+	}
+	base, err := configuredBackupBase()
+	if err != nil {
+		return err
+	}
+	bases := []string{base}
+	if home, err := os.UserHomeDir(); err == nil {
+		bases = append([]string{home}, bases...)
+	}
+	bases = append(bases, resumeSearchDirs(*dir)...)
+	roots := executor.ScanBackupRoots(bases)
+	prune, held := executor.SelectPrunable(roots, now, age, *keep, *force)
+	for _, r := range held {
+		reason := fmt.Sprintf("%d pending/failed entries", r.Incomplete)
+		if !r.HasManifest {
+			reason = "no manifest"
+		}
+		fmt.Fprintf(out, "keep %s (%s; use --force)\n", r.Path, reason)
+	}
+	for _, r := range prune {
+		fmt.Fprintf(out, "prune %s (updated %s)\n", r.Path, r.ModTime.Format("2006-01-02"))
+	}
+	if len(prune) == 0 {
+		fmt.Fprintf(out, "nothing to prune (%d backup roots scanned)\n", len(roots))
+		return nil
+	}
+	if *dryRun {
+		fmt.Fprintf(out, "dry-run: %d of %d backup roots would be pruned; pass --dry-run=false to delete\n", len(prune), len(roots))
+		return nil
+	}
+	if *yes || assumeYesFromEnv() {
+		fmt.Fprintln(out, "WARNING: confirmation bypassed via --yes")
+	} else {
+		fmt.Fprintf(out, "Type the number of backup roots to delete (%d) to continue: ", len(prune))
+		var typed string
+		if _, err := fmt.Fscanln(in, &typed); err != nil {
+			return fmt.Errorf("read confirmation: %w", err)
+		}
+		if strings.TrimSpace(typed) != strconv.Itoa(len(prune)) {
+			return errors.New("confirmation mismatch; prune canceled")
+		}
+	}
+	for _, r := range prune {
+		if err := executor.RemoveBackupRoot(r.Path); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "pruned %d backup roots\n", len(prune))
+	return nil
+}
+
+// parseAge accepts Go durations plus a whole-day suffix ("30d").
+func parseAge(v string) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", v)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", v)
+	}
+	return d, nil
+}
+
 func usage() {
 	fmt.Println("gh-manager")
-	fmt.Println("Runs interactive TUI when no command is provided.")
+	fmt.Println("Runs interactive TUI when no command is provided (optionally with --restore-selection).")
 	fmt.Println("gh-manager <command>")
-	fmt.Println("Commands: plan, backup, execute, restore, delete, theme, inspect, doctor, version")
+	fmt.Println("Commands: plan, import, list, stats, backup, execute, status, restore, delete, prune-archives, theme, config, inspect, doctor, whoami, version")
+}
+
+func loadSavedSelection(w io.Writer) []string {
+	names, err := configpkg.LoadSelection()
+	if err != nil {
+		fmt.Fprintf(w, "warning: loading saved selection failed: %v\n", err)
+		return nil
+	}
+	return names
+}
+
+func saveSelection(selected []planfile.RepoRecord, w io.Writer) {
+	names := make([]string, 0, len(selected))
+	for _, r := range selected {
+		names = append(names, r.FullName)
+	}
+	if err := configpkg.SaveSelection(names, time.Now()); err != nil {
+		fmt.Fprintf(w, "warning: saving selection failed: %v\n", err)
+	}
+}
+
+func newCommandRunner() app.CommandRunner {
+	cfg, err := configpkg.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: loading config failed: %v; using default retry and rate-limit settings\n", err)
+		cfg = configpkg.Default()
+	}
+	base := app.NewRateLimitRunner(app.ExecRunner{}, cfg.RateLimit.GHRequestsPerMinute)
+	if !cfg.Retry.Enabled {
+		return base
+	}
+	return app.RetryRunner{
+		Runner:      base,
+		MaxAttempts: cfg.Retry.MaxAttempts,
+		BaseDelay:   time.Duration(cfg.Retry.BaseDelayMS) * time.Millisecond,
+	}
+}
+
+// withRepoCache lets gh reuse repo listings from the config dir while they
+// are younger than cache.repos_ttl_minutes; refresh refetches and rewrites
+// them. Caching is off when the TTL is zero or the config can't be read.
+func withRepoCache(gh github.Client, host string, refresh bool) github.Client {
+	cfg, err := configpkg.Load()
+	if err != nil || cfg.Cache.ReposTTLMinutes <= 0 {
+		return gh
+	}
+	dir, err := configpkg.Dir()
+	if err != nil {
+		return gh
+	}
+	return gh.WithRepoCache(github.RepoCache{
+		Dir:     dir,
+		TTL:     time.Duration(cfg.Cache.ReposTTLMinutes) * time.Minute,
+		Host:    host,
+		Refresh: refresh,
+	})
+}
+
+// clearRepoCache drops cached repo listings once repos have been deleted or
+// restored.
+func clearRepoCache(w io.Writer) {
+	dir, err := configpkg.Dir()
+	if err != nil {
+		return
+	}
+	if err := (github.RepoCache{Dir: dir}).Clear(); err != nil {
+		fmt.Fprintf(w, "warning: clearing repo cache failed: %v\n", err)
+	}
 }
 
 func resolveUITheme(w io.Writer) tui.UITheme {
@@ -411,6 +1739,7 @@ func resolveUITheme(w io.Writer) tui.UITheme {
 		fmt.Fprintf(w, "warning: loading config failed, using default theme: %v\n", err)
 		return tui.UITheme{}
 	}
+	cfg.Theme.Active = themepkg.SchemeThemeID(cfg.Theme, themepkg.DetectDarkBackground)
 	palette, _, err := themepkg.LoadActivePaletteHex(cfg)
 	if err != nil {
 		fmt.Fprintf(w, "warning: loading theme %q failed, using default: %v\n", cfg.Theme.Active, err)
@@ -422,7 +1751,7 @@ func resolveUITheme(w io.Writer) tui.UITheme {
 
 func runTheme(ctx context.Context, args []string, out io.Writer) error {
 	if len(args) == 0 {
-		return errors.New("theme subcommand required: list, current, apply, install, uninstall")
+		return errors.New("theme subcommand required: list, current, apply, install, uninstall, export, validate, new, diff")
 	}
 	switch args[0] {
 	case "list":
@@ -510,11 +1839,229 @@ func runTheme(ctx context.Context, args []string, out io.Writer) error {
 		}
 		fmt.Fprintf(out, "%s\n", msg)
 		return nil
+	case "export":
+		fs := flag.NewFlagSet("theme export", flag.ContinueOnError)
+		outPath := fs.String("out", "", "Write theme JSON to file instead of stdout")
+		positional, err := parseInterspersed(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(positional) != 1 {
+			return errors.New("usage: gh-manager theme export <theme-id|default|current> [--out file.json]")
+		}
+		b, err := themeExport(strings.TrimSpace(positional[0]))
+		if err != nil {
+			return err
+		}
+		if *outPath == "" {
+			_, err = out.Write(b)
+			return err
+		}
+		if err := os.WriteFile(*outPath, b, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "exported theme: %s\n", *outPath)
+		return nil
+	case "validate":
+		if len(args) != 2 {
+			return errors.New("usage: gh-manager theme validate <file|->")
+		}
+		var r io.Reader = os.Stdin
+		if args[1] != "-" {
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		id, err := themeValidate(r)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "valid: %s\n", id)
+		return nil
+	case "new":
+		fs := flag.NewFlagSet("theme new", flag.ContinueOnError)
+		force := fs.Bool("force", false, "Overwrite an existing theme with the same id")
+		positional, err := parseInterspersed(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(positional) != 1 {
+			return errors.New("usage: gh-manager theme new <theme-id> [--force]")
+		}
+		path, err := themeNew(strings.TrimSpace(positional[0]), *force)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "created theme: %s\n", path)
+		return nil
+	case "diff":
+		if len(args) != 3 {
+			return errors.New("usage: gh-manager theme diff <theme-id|file.json> <theme-id|file.json>")
+		}
+		return themeDiff(strings.TrimSpace(args[1]), strings.TrimSpace(args[2]), out)
 	default:
 		return fmt.Errorf("unknown theme subcommand: %s", args[0])
 	}
 }
 
+func themeNew(id string, force bool) (string, error) {
+	if err := themepkg.ValidateThemeID(id); err != nil {
+		return "", err
+	}
+	path, err := themepkg.ThemeFilePath(id)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("theme already exists: %s (use --force to overwrite)", path)
+	}
+	tf := themepkg.ThemeFile{ID: id, Name: id, Version: 1, Colors: themepkg.DefaultPaletteHex()}
+	if err := themepkg.SaveThemeFile(tf); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// loadThemeArg loads an existing theme file path, or else an installed
+// theme id (including default and current).
+func loadThemeArg(arg string) (themepkg.ThemeFile, error) {
+	if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+		b, err := os.ReadFile(arg)
+		if err != nil {
+			return themepkg.ThemeFile{}, err
+		}
+		tf, err := themepkg.ParseThemeFile(b)
+		if err != nil {
+			return themepkg.ThemeFile{}, fmt.Errorf("%s: %w", arg, err)
+		}
+		return tf, nil
+	}
+	b, err := themeExport(arg)
+	if err != nil {
+		return themepkg.ThemeFile{}, err
+	}
+	return themepkg.ParseThemeFile(b)
+}
+
+func themeDiff(a, b string, out io.Writer) error {
+	ta, err := loadThemeArg(a)
+	if err != nil {
+		return err
+	}
+	tb, err := loadThemeArg(b)
+	if err != nil {
+		return err
+	}
+	diffs := themepkg.DiffPalettes(ta.Colors, tb.Colors)
+	if len(diffs) == 0 {
+		fmt.Fprintf(out, "%s and %s have identical colors\n", ta.ID, tb.ID)
+		return nil
+	}
+	fmt.Fprintf(out, "%d of %d colors differ (%s -> %s):\n", len(diffs), themepkg.PaletteFieldCount(), ta.ID, tb.ID)
+	for _, d := range diffs {
+		fmt.Fprintf(out, "  %-22s %s  %s\n", d.Field, d.A, d.B)
+	}
+	return nil
+}
+
+func themeValidate(r io.Reader) (string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	tf, err := themepkg.ParseThemeFile(b)
+	if err != nil {
+		return "", fmt.Errorf("invalid theme: %w", err)
+	}
+	return tf.ID, nil
+}
+
+func themeExport(id string) ([]byte, error) {
+	if id == "" {
+		return nil, errors.New("theme id is required")
+	}
+	if id == "current" {
+		cfg, err := configpkg.Load()
+		if err != nil {
+			return nil, err
+		}
+		id = cfg.Theme.Active
+	}
+	tf, err := themepkg.LoadThemeFile(id)
+	if err != nil {
+		return nil, err
+	}
+	return themepkg.MarshalThemeFile(tf)
+}
+
+// parseInterspersed parses fs while allowing flags after positional
+// arguments, returning the positional arguments in order.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func runConfig(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("config subcommand required: get, set, path")
+	}
+	switch args[0] {
+	case "path":
+		p, err := configpkg.Path()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, p)
+		return nil
+	case "get":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: gh-manager config get <key> (keys: %s)", strings.Join(configpkg.Keys(), ", "))
+		}
+		cfg, err := configpkg.Load()
+		if err != nil {
+			return err
+		}
+		v, err := configpkg.Get(cfg, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, v)
+		return nil
+	case "set":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: gh-manager config set <key> <value> (keys: %s)", strings.Join(configpkg.Keys(), ", "))
+		}
+		cfg, err := configpkg.Load()
+		if err != nil {
+			return err
+		}
+		if err := configpkg.Set(&cfg, args[1], args[2]); err != nil {
+			return err
+		}
+		if err := configpkg.Save(cfg); err != nil {
+			return err
+		}
+		v, _ := configpkg.Get(cfg, args[1])
+		fmt.Fprintf(out, "%s = %s\n", args[1], v)
+		return nil
+	default:
+		return fmt.Errorf("unknown config subcommand: %s", args[0])
+	}
+}
+
 func themeCurrentLabel() (string, error) {
 	cfg, err := configpkg.Load()
 	if err != nil {
@@ -540,15 +2087,15 @@ func themeListRemote(ctx context.Context) ([]tui.ThemeOption, string, error) {
 	if err != nil {
 		return nil, "", err
 	}
-	idx, sourceURL, err := fetchThemeIndexWithLocalFallback(ctx, cfg.Theme.IndexURL)
+	merged, sourceLabel, err := fetchThemeIndexWithLocalFallback(ctx, cfg.Theme.IndexSources())
 	if err != nil {
 		return nil, "", err
 	}
-	out := make([]tui.ThemeOption, 0, len(idx.Themes))
-	for _, th := range idx.Themes {
+	out := make([]tui.ThemeOption, 0, len(merged.Index.Themes))
+	for _, th := range merged.Index.Themes {
 		out = append(out, tui.ThemeOption{ID: th.ID, Name: th.Name, Description: th.Description})
 	}
-	return out, sourceURL, nil
+	return out, sourceLabel, nil
 }
 
 func themeInstall(ctx context.Context, id string) (string, error) {
@@ -556,11 +2103,15 @@ func themeInstall(ctx context.Context, id string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	_, sourceURL, err := fetchThemeIndexWithLocalFallback(ctx, cfg.Theme.IndexURL)
+	merged, _, err := fetchThemeIndexWithLocalFallback(ctx, cfg.Theme.IndexSources())
 	if err != nil {
 		return "", err
 	}
-	themeFile, err := themepkg.FetchThemeByID(ctx, sourceURL, id)
+	entry, sourceURL, ok := merged.Find(id)
+	if !ok {
+		return "", fmt.Errorf("theme not found in index: %s", id)
+	}
+	themeFile, err := themepkg.FetchThemeEntry(ctx, sourceURL, entry)
 	if err != nil {
 		return "", err
 	}
@@ -605,6 +2156,20 @@ func themeApply(id string) (tui.UITheme, string, error) {
 	return resolvedToUITheme(resolved), fmt.Sprintf("applied theme: %s", id), nil
 }
 
+func themePreview(id string) (tui.UITheme, error) {
+	cfg, err := configpkg.Load()
+	if err != nil {
+		return tui.UITheme{}, err
+	}
+	cfg.Theme.Active = id
+	palette, _, err := themepkg.LoadActivePaletteHex(cfg)
+	if err != nil {
+		return tui.UITheme{}, err
+	}
+	resolved := themepkg.ResolveForTerminal(palette, themepkg.DetectTrueColor())
+	return resolvedToUITheme(resolved), nil
+}
+
 func themeUninstall(id string) (tui.UITheme, string, error) {
 	if id == "default" {
 		return tui.UITheme{}, "", errors.New("cannot uninstall built-in theme: default")
@@ -711,6 +2276,74 @@ func checkLatestRelease(ctx context.Context, runner app.CommandRunner) (tui.Upda
 	return info, nil
 }
 
+// updateChecker serves the TUI's startup update check from the last saved
+// check while it is younger than cache.update_check_hours, unless force is
+// set. Later checks are user-triggered and always go to the network.
+func updateChecker(ctx context.Context, runner app.CommandRunner, force bool) func() (tui.UpdateInfo, error) {
+	var started atomic.Bool
+	started.Store(force)
+	return func() (tui.UpdateInfo, error) {
+		if !started.Swap(true) {
+			if c, ok, err := configpkg.LoadUpdateCheck(); err == nil && ok {
+				if info, fresh := cachedUpdateInfo(c, version.Value, updateCheckTTL(), time.Now()); fresh {
+					return info, nil
+				}
+			}
+		}
+		info, err := checkLatestRelease(ctx, runner)
+		if err == nil {
+			_ = configpkg.SaveUpdateCheck(configpkg.UpdateCheck{
+				CheckedAt:      info.CheckedAt.UTC().Format(time.RFC3339),
+				CurrentVersion: info.CurrentVersion,
+				LatestVersion:  info.LatestVersion,
+				ReleaseURL:     info.ReleaseURL,
+			})
+		}
+		return info, err
+	}
+}
+
+// updateChecksEnabled is false when update.enabled is false or
+// GH_MANAGER_NO_UPDATE_CHECK is set to a true value.
+func updateChecksEnabled() bool {
+	if v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("GH_MANAGER_NO_UPDATE_CHECK"))); err == nil && v {
+		return false
+	}
+	cfg, err := configpkg.Load()
+	if err != nil {
+		return true
+	}
+	return cfg.Update.CheckEnabled()
+}
+
+func updateCheckTTL() time.Duration {
+	cfg, err := configpkg.Load()
+	if err != nil {
+		return 0
+	}
+	return cfg.Cache.UpdateCheckTTL()
+}
+
+// cachedUpdateInfo rebuilds an UpdateInfo from a saved check. It reports
+// false when the check is older than ttl or was made by another version.
+func cachedUpdateInfo(c configpkg.UpdateCheck, current string, ttl time.Duration, now time.Time) (tui.UpdateInfo, bool) {
+	if ttl <= 0 || c.CurrentVersion != current || c.LatestVersion == "" {
+		return tui.UpdateInfo{}, false
+	}
+	checkedAt, err := time.Parse(time.RFC3339, c.CheckedAt)
+	if err != nil || checkedAt.After(now) || now.Sub(checkedAt) >= ttl {
+		return tui.UpdateInfo{}, false
+	}
+	return tui.UpdateInfo{
+		CurrentVersion:  current,
+		LatestVersion:   c.LatestVersion,
+		UpdateAvailable: compareSemverLabels(current, c.LatestVersion) < 0,
+		ReleaseURL:      c.ReleaseURL,
+		CheckedAt:       checkedAt,
+		Source:          "github-releases (cached)",
+	}, true
+}
+
 func runSelfUpdate(ctx context.Context, runner app.CommandRunner) (string, error) {
 	var name string
 	var args []string
@@ -775,33 +2408,121 @@ func parseSemverParts(v string) [3]int {
 	return out
 }
 
-func fetchThemeIndexWithLocalFallback(ctx context.Context, primaryURL string) (themepkg.ThemeIndex, string, error) {
-	idx, err := themepkg.FetchIndex(ctx, primaryURL)
+// fetchThemeIndexWithLocalFallback returns the merged index of indexURLs and
+// a display label for where it came from.
+func fetchThemeIndexWithLocalFallback(ctx context.Context, indexURLs []string) (themepkg.MergedIndex, string, error) {
+	label := strings.Join(indexURLs, ", ")
+	merged, err := themepkg.FetchIndexes(ctx, indexURLs)
 	if err == nil {
-		return idx, primaryURL, nil
+		_ = themepkg.SaveIndexCache(merged, label, time.Now())
+		return merged, label, nil
 	}
 	localPath := filepath.Join("themes", "index.json")
-	localIdx, localErr := themepkg.FetchIndex(ctx, localPath)
-	if localErr != nil {
-		return themepkg.ThemeIndex{}, "", fmt.Errorf("fetch theme index failed (%s): %w", primaryURL, err)
+	if local, localErr := themepkg.FetchIndexes(ctx, []string{localPath}); localErr == nil {
+		return local, localPath, nil
+	}
+	if cache, cacheErr := themepkg.LoadIndexCache(); cacheErr == nil {
+		cached := themepkg.MergedIndex{Index: cache.Index, Sources: cache.Sources}
+		if cached.Sources == nil {
+			cached.Sources = map[string]string{}
+			for _, entry := range cache.Index.Themes {
+				cached.Sources[entry.ID] = cache.SourceURL
+			}
+		}
+		return cached, fmt.Sprintf("%s (cached %s)", cache.SourceURL, formatAge(time.Since(cache.FetchedAt))), nil
+	}
+	return themepkg.MergedIndex{}, "", fmt.Errorf("fetch theme index failed (%s): %w", label, err)
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+}
+
+func preferredRestoreArchiveDir() string {
+	candidates := restoreDocumentsCandidates()
+	for _, p := range candidates {
+		if p == "" {
+			continue
+		}
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			return p
+		}
+	}
+	if len(candidates) > 0 && strings.TrimSpace(candidates[0]) != "" {
+		return candidates[0]
+	}
+	return "."
+}
+
+// archiveRootsUnder lists the archive roots at or directly under bases,
+// newest gh-manager-archive-* folder first within each base.
+func archiveRootsUnder(bases []string) []string {
+	var roots []string
+	seen := map[string]bool{}
+	for _, base := range bases {
+		base = strings.TrimSpace(base)
+		if base == "" || seen[base] {
+			continue
+		}
+		seen[base] = true
+		candidates := []string{base}
+		children, _ := filepath.Glob(filepath.Join(base, "gh-manager-archive-*"))
+		sort.Sort(sort.Reverse(sort.StringSlice(children)))
+		candidates = append(candidates, children...)
+		for _, root := range candidates {
+			if restore.IsArchiveRoot(root) {
+				roots = append(roots, root)
+			}
+		}
 	}
-	return localIdx, localPath, nil
+	return roots
 }
 
-func preferredRestoreArchiveDir() string {
-	candidates := restoreDocumentsCandidates()
-	for _, p := range candidates {
-		if p == "" {
-			continue
+// findRepoBackup looks for fullName in every archive root found at or
+// directly under bases and returns the preferred restore source path.
+func findRepoBackup(fullName string, bases []string) (string, bool, error) {
+	for _, root := range archiveRootsUnder(bases) {
+		entries, err := restore.LoadIndex(root)
+		if err != nil {
+			return "", false, fmt.Errorf("read archive %s: %w", root, err)
 		}
-		if info, err := os.Stat(p); err == nil && info.IsDir() {
-			return p
+		for _, e := range entries {
+			if e.FullName != fullName {
+				continue
+			}
+			if src, ok := restore.PreferredSource(e); ok {
+				return src.Path, true, nil
+			}
 		}
 	}
-	if len(candidates) > 0 && strings.TrimSpace(candidates[0]) != "" {
-		return candidates[0]
+	return "", false, nil
+}
+
+// backedUpRepos collects every repo with a usable bundle or snapshot in the
+// archive roots under bases, for the TUI Bak column.
+func backedUpRepos(bases []string) (map[string]bool, error) {
+	out := map[string]bool{}
+	for _, root := range archiveRootsUnder(bases) {
+		entries, err := restore.LoadIndex(root)
+		if err != nil {
+			return nil, fmt.Errorf("read archive %s: %w", root, err)
+		}
+		for _, e := range entries {
+			if _, ok := restore.PreferredSource(e); ok {
+				out[e.FullName] = true
+			}
+		}
 	}
-	return "."
+	return out, nil
 }
 
 func restoreDocumentsCandidates() []string {
@@ -834,40 +2555,148 @@ func repoBasename(fullName string) string {
 }
 
 type executeConfig struct {
-	PlanPath       string
-	BackupDir      string
-	BackupLocation string
-	Resume         bool
-	DryRun         bool
-	Confirmation   string
+	PlanPath           string
+	Host               string
+	BackupDir          string
+	BackupLocation     string
+	Resume             bool
+	ResumeFrom         string
+	OnlyFailed         bool
+	DryRun             bool
+	Confirmation       string
+	ConfirmationMode   string
+	ConfirmationPhrase string
+	AssumeYes          bool
+	Output             string
+	LogPath            string
+	ManifestOut        string
+	OpTimeout          time.Duration
+	SecretFile         string
+	BackupThenDelete   bool
+	// TwoPhase stops after printing the plan and its fingerprint unless
+	// ConfirmFingerprint is set; a set ConfirmFingerprint must match the plan.
+	TwoPhase           bool
+	ConfirmFingerprint string
 }
 
 type backupConfig struct {
-	PlanPath          string
-	BackupDir         string
-	BackupLocation    string
-	Resume            bool
-	DryRun            bool
-	ArchiveRepo       string
-	ArchiveBranch     string
-	ArchiveVisibility string
-	NoArchive         bool
-	Confirmation      string
-}
-
-func createSignedPlan(actor string, selected []planfile.RepoRecord, outPath string, now time.Time) (string, int, error) {
-	if len(selected) == 0 {
-		return "", 0, errors.New("no repositories selected")
+	PlanPath           string
+	Host               string
+	BackupDir          string
+	BackupLocation     string
+	Resume             bool
+	ResumeFrom         string
+	OnlyFailed         bool
+	DryRun             bool
+	ArchiveRepo        string
+	ArchiveBranch      string
+	ArchiveVisibility  string
+	NoArchive          bool
+	PruneMirror        bool
+	NoSnapshot         bool
+	Refresh            bool
+	Compress           bool
+	IncludeLFS         bool
+	Confirmation       string
+	ConfirmationMode   string
+	ConfirmationPhrase string
+	AssumeYes          bool
+	Output             string
+	LogPath            string
+	ManifestOut        string
+	OpTimeout          time.Duration
+	SecretFile         string
+	// PrintCommands is set when the runner only echoes mutating commands.
+	PrintCommands bool
+}
+
+// signingSecret reads secretFile when set, otherwise the config dir's
+// secret.hex (created on first use).
+func signingSecret(secretFile string) ([]byte, error) {
+	if strings.TrimSpace(secretFile) != "" {
+		return planfile.ReadSecret(secretFile)
 	}
 	configDir, err := app.ConfigDir()
 	if err != nil {
-		return "", 0, err
+		return nil, err
+	}
+	return planfile.EnsureSecret(configDir)
+}
+
+// verificationSecrets is signingSecret plus, without --secret-file, the
+// retired secret.old.hex so plans signed before a rotation still validate.
+func verificationSecrets(secretFile string) ([][]byte, error) {
+	if strings.TrimSpace(secretFile) != "" {
+		secret, err := planfile.ReadSecret(secretFile)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{secret}, nil
+	}
+	configDir, err := app.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return planfile.VerificationSecrets(configDir)
+}
+
+// taskPlanPath picks the plan a TUI backup/execute runs against. An explicit
+// planPath wins and the table selection is ignored; otherwise a plan is
+// generated from selected with create. Either way the choice is logged.
+func taskPlanPath(planPath string, selected []planfile.RepoRecord, create func([]planfile.RepoRecord) (string, error), out io.Writer) (string, error) {
+	if planPath = strings.TrimSpace(planPath); planPath != "" {
+		p, err := planfile.Read(planPath)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(out, "using repos from plan (%d): %s\n", len(p.Repos), planPath)
+		if len(selected) > 0 {
+			fmt.Fprintf(out, "ignoring current selection (%d repos)\n", len(selected))
+		}
+		return planPath, nil
+	}
+	fmt.Fprintf(out, "auto-generating plan from selection (%d)\n", len(selected))
+	p, err := create(selected)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(out, "auto-generated plan: %s (%d repos)\n", p, len(selected))
+	return p, nil
+}
+
+// planGuards lifts the config-driven safeguards createSignedPlan applies.
+type planGuards struct {
+	AllowProtected bool
+	AllowLarge     bool
+}
+
+func createSignedPlan(actor, host, secretFile string, selected []planfile.RepoRecord, outPath string, guards planGuards, now time.Time) (string, int, error) {
+	if len(selected) == 0 {
+		return "", 0, errors.New("no repositories selected")
+	}
+	if !guards.AllowProtected || !guards.AllowLarge {
+		cfg, err := configpkg.Load()
+		if err != nil {
+			return "", 0, err
+		}
+		if !guards.AllowLarge && cfg.MaxPlanSize > 0 && len(selected) > cfg.MaxPlanSize {
+			return "", 0, fmt.Errorf("selected %d repos, more than max_plan_size (%d); use --allow-large or raise max_plan_size", len(selected), cfg.MaxPlanSize)
+		}
+		if !guards.AllowProtected {
+			protected, err := planfile.ProtectedRepos(selected, cfg.ProtectedRepos)
+			if err != nil {
+				return "", 0, err
+			}
+			if len(protected) > 0 {
+				return "", 0, fmt.Errorf("selection includes protected repos: %s (remove them or use --allow-protected)", strings.Join(protected, ", "))
+			}
+		}
 	}
-	secret, err := planfile.EnsureSecret(configDir)
+	secret, err := signingSecret(secretFile)
 	if err != nil {
 		return "", 0, err
 	}
-	plan := planfile.New(actor, "github.com", version.Value, selected, now)
+	plan := planfile.New(actor, host, version.Value, selected, now)
 	if err := plan.Sign(secret); err != nil {
 		return "", 0, err
 	}
@@ -881,7 +2710,31 @@ func createSignedPlan(actor string, selected []planfile.RepoRecord, outPath stri
 	return planPath, plan.Count, nil
 }
 
-func inspectToString(planPath, manifestPath string) (string, error) {
+// inspectCSV writes the plan's repos as CSV for sharing outside gh-manager.
+func inspectCSV(planPath string, out io.Writer) error {
+	p, err := planfile.Read(planPath)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"fullName", "visibility", "isFork", "isArchived", "updatedAt", "description"}); err != nil {
+		return err
+	}
+	for _, r := range p.Repos {
+		vis := "public"
+		if r.IsPrivate {
+			vis = "private"
+		}
+		row := []string{r.FullName, vis, strconv.FormatBool(r.IsFork), strconv.FormatBool(r.IsArchived), r.UpdatedAt, r.Description}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func inspectToString(planPath, manifestPath, secretFile string) (string, error) {
 	if strings.TrimSpace(planPath) == "" {
 		return "", errors.New("--plan is required")
 	}
@@ -890,13 +2743,11 @@ func inspectToString(planPath, manifestPath string) (string, error) {
 		return "", err
 	}
 	verification := "unknown"
-	if configDir, cErr := app.ConfigDir(); cErr == nil {
-		if secret, sErr := planfile.EnsureSecret(configDir); sErr == nil {
-			if vErr := p.Validate(secret); vErr == nil {
-				verification = "valid"
-			} else {
-				verification = "invalid (" + vErr.Error() + ")"
-			}
+	if secrets, sErr := verificationSecrets(secretFile); sErr == nil {
+		if vErr := p.ValidateAny(secrets); vErr == nil {
+			verification = "valid"
+		} else {
+			verification = "invalid (" + vErr.Error() + ")"
 		}
 	}
 	var b strings.Builder
@@ -924,7 +2775,56 @@ func inspectToString(planPath, manifestPath string) (string, error) {
 	return b.String(), nil
 }
 
-func validatePlanForExecution(ctx context.Context, gh github.Client, runner app.CommandRunner, planPath string) (planfile.DeletionPlanV1, error) {
+func inspectArchiveToString(root string) (string, error) {
+	if !restore.IsArchiveRoot(root) {
+		return "", fmt.Errorf("not an archive root: %s", root)
+	}
+	entries, err := restore.LoadIndex(root)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "archiveRoot: %s\n", root)
+	fmt.Fprintf(&b, "count: %d\n", len(entries))
+	fmt.Fprintln(&b, "repos:")
+	for _, e := range entries {
+		size := "-"
+		if src, ok := restore.PreferredSource(e); ok {
+			size = fmt.Sprintf("%s (%s)", formatBytes(executor.PathSize(src.Path)), src.Kind)
+		}
+		updated := e.UpdatedAt
+		if updated == "" {
+			updated = "unknown"
+		}
+		fmt.Fprintf(&b, "- %s bundle=%s snapshot=%s size=%s updatedAt=%s\n", e.FullName, yesNo(fileExists(e.BundlePath)), yesNo(dirExists(e.SnapshotPath)), size, updated)
+	}
+	return b.String(), nil
+}
+
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func dirExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+func validatePlanForExecution(ctx context.Context, gh github.Client, runner app.CommandRunner, planPath, host, secretFile, confirmFingerprint string) (planfile.DeletionPlanV1, error) {
 	var p planfile.DeletionPlanV1
 	if strings.TrimSpace(planPath) == "" {
 		return p, errors.New("--plan is required")
@@ -932,74 +2832,210 @@ func validatePlanForExecution(ctx context.Context, gh github.Client, runner app.
 	if err := doctor.Check(ctx, runner); err != nil {
 		return p, err
 	}
-	configDir, err := app.ConfigDir()
+	p, err := readVerifiedPlan(planPath, secretFile, confirmFingerprint)
 	if err != nil {
 		return p, err
 	}
-	secret, err := planfile.EnsureSecret(configDir)
-	if err != nil {
+	if err := checkPlanHost(p, host); err != nil {
 		return p, err
 	}
-	p, err = planfile.Read(planPath)
+	actor, err := gh.CurrentUser(ctx)
 	if err != nil {
 		return p, err
 	}
-	if err := p.Validate(secret); err != nil {
-		return p, fmt.Errorf("plan validation failed: %w", err)
+	if actor != p.Actor {
+		return p, fmt.Errorf("actor mismatch: plan=%s current=%s", p.Actor, actor)
 	}
-	actor, err := gh.CurrentUser(ctx)
+	checkHeadDrift(ctx, gh, p, os.Stderr)
+	return p, nil
+}
+
+// printPlanForConfirmation is the first half of execute --two-phase.
+func printPlanForConfirmation(out io.Writer, planPath string, p planfile.DeletionPlanV1) {
+	fmt.Fprintf(out, "plan: %s (%d repos)\n", planPath, len(p.Repos))
+	for _, r := range p.Repos {
+		fmt.Fprintf(out, "  %s\n", r.FullName)
+	}
+	fmt.Fprintf(out, "fingerprint: %s\n", p.Fingerprint)
+	fmt.Fprintf(out, "review the plan, then run: gh-manager execute --plan %s --confirm-fingerprint %s\n", planPath, p.Fingerprint)
+}
+
+// readVerifiedPlan reads a plan and checks its signature and, when
+// confirmFingerprint is set, that it is still the plan that was reviewed.
+func readVerifiedPlan(planPath, secretFile, confirmFingerprint string) (planfile.DeletionPlanV1, error) {
+	secrets, err := verificationSecrets(secretFile)
+	if err != nil {
+		return planfile.DeletionPlanV1{}, err
+	}
+	p, err := planfile.Read(planPath)
 	if err != nil {
 		return p, err
 	}
-	if actor != p.Actor {
-		return p, fmt.Errorf("actor mismatch: plan=%s current=%s", p.Actor, actor)
+	if err := p.ValidateAny(secrets); err != nil {
+		return p, fmt.Errorf("plan validation failed: %w", err)
 	}
-	if p.Host != "github.com" {
-		return p, fmt.Errorf("unsupported host: %s", p.Host)
+	if fp := strings.TrimSpace(confirmFingerprint); fp != "" && fp != p.Fingerprint {
+		return p, fmt.Errorf("fingerprint mismatch: plan=%s confirmed=%s; the plan changed since it was reviewed", p.Fingerprint, fp)
 	}
 	return p, nil
 }
 
+func captureHeads(ctx context.Context, gh github.Client, repos []planfile.RepoRecord, warn io.Writer) []planfile.RepoRecord {
+	out := make([]planfile.RepoRecord, len(repos))
+	for i, r := range repos {
+		out[i] = r
+		sha, err := gh.HeadSHA(ctx, r.FullName)
+		if err != nil {
+			fmt.Fprintf(warn, "warning: could not capture HEAD for %s: %v\n", r.FullName, err)
+			continue
+		}
+		out[i].HeadSHA = sha
+	}
+	return out
+}
+
+// checkHeadDrift warns about repos whose HEAD moved since the plan captured
+// it and returns how many drifted.
+func checkHeadDrift(ctx context.Context, gh github.Client, p planfile.DeletionPlanV1, warn io.Writer) int {
+	drifted := 0
+	for _, r := range p.Repos {
+		if r.HeadSHA == "" {
+			continue
+		}
+		live, err := gh.HeadSHA(ctx, r.FullName)
+		if err != nil {
+			fmt.Fprintf(warn, "warning: could not check HEAD for %s: %v\n", r.FullName, err)
+			continue
+		}
+		if live != r.HeadSHA {
+			drifted++
+			fmt.Fprintf(warn, "warning: %s has new commits since the plan was created (plan %s, live %s)\n", r.FullName, shortSHA(r.HeadSHA), shortSHA(live))
+		}
+	}
+	return drifted
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// executionHost picks the host a plan runs against: --host, then GH_HOST,
+// then the host recorded in the plan. An unreadable plan falls back to the
+// default and is reported by plan validation.
+func executionHost(flagHost, planPath string) string {
+	if strings.TrimSpace(flagHost) != "" || strings.TrimSpace(os.Getenv("GH_HOST")) != "" || strings.TrimSpace(planPath) == "" {
+		return app.ResolveHost(flagHost)
+	}
+	if p, err := planfile.Read(planPath); err == nil && strings.TrimSpace(p.Host) != "" {
+		return strings.TrimSpace(p.Host)
+	}
+	return app.DefaultHost
+}
+
+func checkPlanHost(p planfile.DeletionPlanV1, host string) error {
+	if host == "" {
+		host = app.DefaultHost
+	}
+	if !strings.EqualFold(p.Host, host) {
+		return fmt.Errorf("host mismatch: plan=%s current=%s", p.Host, host)
+	}
+	return nil
+}
+
 func runExecuteTask(ctx context.Context, gh github.Client, runner app.CommandRunner, cfg executeConfig, in io.Reader, out io.Writer) error {
-	p, err := validatePlanForExecution(ctx, gh, runner, cfg.PlanPath)
+	progress, err := progressWriter(cfg.Output, out)
+	if err != nil {
+		return err
+	}
+	p, err := validatePlanForExecution(ctx, gh, runner, cfg.PlanPath, cfg.Host, cfg.SecretFile, cfg.ConfirmFingerprint)
 	if err != nil {
 		return err
 	}
+	if cfg.TwoPhase && cfg.ConfirmFingerprint == "" {
+		printPlanForConfirmation(out, cfg.PlanPath, p)
+		return nil
+	}
 	resolvedBackupDir, err := resolveBackupLocation(cfg.BackupDir, cfg.BackupLocation)
 	if err != nil {
 		return err
 	}
+	protected, err := configuredProtectedRepos()
+	if err != nil {
+		return err
+	}
+	backupBase, err := configuredBackupBase()
+	if err != nil {
+		return err
+	}
 	if cfg.Confirmation != "" {
 		in = strings.NewReader(cfg.Confirmation + "\n")
 	}
 	exec := executor.Executor{
 		GH:     gh,
-		Backup: backup.NewService(runner),
+		Backup: backup.NewService(runner, p.Host),
 		Now:    time.Now,
 		In:     in,
-		Out:    out,
+		Out:    progress,
 	}
 	res, err := exec.Execute(ctx, executor.Config{
-		PlanPath:  cfg.PlanPath,
-		Resume:    cfg.Resume,
-		BackupDir: resolvedBackupDir,
-		Mode:      executor.ModeDelete,
-		DryRun:    cfg.DryRun,
+		PlanPath:           cfg.PlanPath,
+		Resume:             cfg.Resume,
+		OnlyFailed:         cfg.OnlyFailed,
+		ProtectedRepos:     protected,
+		BackupDir:          resolvedBackupDir,
+		Mode:               executor.ModeDelete,
+		DryRun:             cfg.DryRun,
+		BackupThenDelete:   cfg.BackupThenDelete,
+		ConfirmationMode:   cfg.ConfirmationMode,
+		ConfirmationPhrase: cfg.ConfirmationPhrase,
+		AssumeYes:          cfg.AssumeYes,
+		DefaultBackupBase:  backupBase,
+		ResumeSearchDirs:   resumeSearchDirs(cfg.ResumeFrom),
+		LogPath:            cfg.LogPath,
+		ExtraManifestPath:  cfg.ManifestOut,
+		PerRepoTimeout:     cfg.OpTimeout,
 	}, p)
+	if res.Deleted > 0 {
+		clearRepoCache(progress)
+	}
 	if err != nil {
-		return err
+		return interruptedError(err, res)
+	}
+	if cfg.Output == outputJSON {
+		return writeRunSummaryJSON(out, executor.ModeDelete, cfg.DryRun, res)
 	}
 	if cfg.DryRun {
 		fmt.Fprintln(out, "execution dry-run complete")
 	}
-	fmt.Fprintf(out, "execution complete: deleted=%d failed=%d total=%d\n", res.Deleted, res.Failed, res.Total)
+	if cfg.BackupThenDelete {
+		fmt.Fprintf(out, "execution complete: deleted=%d failed=%d skipped_no_backup=%d total=%d\n", res.Deleted, res.Failed, res.SkippedNoBackup, res.Total)
+	} else {
+		fmt.Fprintf(out, "execution complete: deleted=%d failed=%d total=%d\n", res.Deleted, res.Failed, res.Total)
+	}
+	if res.SkippedProtected > 0 {
+		fmt.Fprintf(out, "protected repos skipped: %d\n", res.SkippedProtected)
+	}
 	fmt.Fprintf(out, "backup root: %s\n", res.BackupRoot)
 	fmt.Fprintf(out, "manifest: %s\n", res.ManifestPath)
 	return nil
 }
 
 func runBackupTask(ctx context.Context, gh github.Client, runner app.CommandRunner, cfg backupConfig, in io.Reader, out io.Writer) error {
-	p, err := validatePlanForExecution(ctx, gh, runner, cfg.PlanPath)
+	progress, err := progressWriter(cfg.Output, out)
+	if err != nil {
+		return err
+	}
+	if _, err := github.VisibilityFlag(cfg.ArchiveVisibility); err != nil {
+		return fmt.Errorf("--archive-visibility: %w", err)
+	}
+	cfg.ArchiveRepo, err = resolveArchiveRepo(cfg.ArchiveRepo)
+	if err != nil {
+		return err
+	}
+	p, err := validatePlanForExecution(ctx, gh, runner, cfg.PlanPath, cfg.Host, cfg.SecretFile, "")
 	if err != nil {
 		return err
 	}
@@ -1007,30 +3043,59 @@ func runBackupTask(ctx context.Context, gh github.Client, runner app.CommandRunn
 	if err != nil {
 		return err
 	}
+	backupBase, err := configuredBackupBase()
+	if err != nil {
+		return err
+	}
 	if cfg.Confirmation != "" {
 		in = strings.NewReader(cfg.Confirmation + "\n")
 	}
+	if cfg.IncludeLFS && !doctor.GitLFSAvailable() {
+		fmt.Fprintln(progress, "git-lfs not found in PATH; skipping LFS objects")
+		cfg.IncludeLFS = false
+	}
+	backupSvc := backup.NewService(runner, p.Host)
+	backupSvc.Refresh = cfg.Refresh
+	backupSvc.Compress = cfg.Compress
+	archiveSvc := backup.NewArchiveService(runner)
+	archiveSvc.PrintOnly = cfg.PrintCommands
 	exec := executor.Executor{
 		RepoMgr: gh,
-		Backup:  backup.NewService(runner),
-		Archive: backup.NewArchiveService(runner),
+		Backup:  backupSvc,
+		Archive: archiveSvc,
 		Now:     time.Now,
 		In:      in,
-		Out:     out,
+		Out:     progress,
 	}
 	res, err := exec.Execute(ctx, executor.Config{
-		PlanPath:          cfg.PlanPath,
-		Resume:            cfg.Resume,
-		BackupDir:         resolvedBackupDir,
-		Mode:              executor.ModeBackup,
-		DryRun:            cfg.DryRun,
-		ArchiveRepo:       cfg.ArchiveRepo,
-		ArchiveBranch:     cfg.ArchiveBranch,
-		ArchiveVisibility: cfg.ArchiveVisibility,
-		NoArchive:         cfg.NoArchive,
+		PlanPath:           cfg.PlanPath,
+		Resume:             cfg.Resume,
+		OnlyFailed:         cfg.OnlyFailed,
+		BackupDir:          resolvedBackupDir,
+		Mode:               executor.ModeBackup,
+		DryRun:             cfg.DryRun,
+		ArchiveRepo:        cfg.ArchiveRepo,
+		ArchiveBranch:      cfg.ArchiveBranch,
+		ArchiveVisibility:  cfg.ArchiveVisibility,
+		NoArchive:          cfg.NoArchive,
+		PruneMirror:        cfg.PruneMirror,
+		NoSnapshot:         cfg.NoSnapshot,
+		IncludeLFS:         cfg.IncludeLFS,
+		ConfirmationMode:   cfg.ConfirmationMode,
+		ConfirmationPhrase: cfg.ConfirmationPhrase,
+		AssumeYes:          cfg.AssumeYes,
+		DefaultBackupBase:  backupBase,
+		ResumeSearchDirs:   resumeSearchDirs(cfg.ResumeFrom),
+		LogPath:            cfg.LogPath,
+		ExtraManifestPath:  cfg.ManifestOut,
+		PerRepoTimeout:     cfg.OpTimeout,
+		PrintCommands:      cfg.PrintCommands,
 	}, p)
 	if err != nil {
-		return err
+		return interruptedError(err, res)
+	}
+	if cfg.Output == outputJSON {
+		return writeRunSummaryJSON(out, executor.ModeBackup, cfg.DryRun, res)
 	}
 	if cfg.DryRun {
 		fmt.Fprintln(out, "backup dry-run complete")
@@ -1038,6 +3103,11 @@ func runBackupTask(ctx context.Context, gh github.Client, runner app.CommandRunn
 	fmt.Fprintf(out, "backup complete: local_failed=%d archive_failed=%d archive_skipped_size=%d total=%d\n", res.Failed, res.ArchiveFailed, res.ArchiveSkippedSize, res.Total)
 	fmt.Fprintf(out, "backup root: %s\n", res.BackupRoot)
 	fmt.Fprintf(out, "manifest: %s\n", res.ManifestPath)
+	if !cfg.DryRun {
+		fmt.Fprintf(out, "bytes mirrored: %s\n", formatBytes(res.BytesMirrored))
+		fmt.Fprintf(out, "bytes bundled: %s\n", formatBytes(res.BytesBundled))
+		fmt.Fprintf(out, "bytes skipped (size): %s\n", formatBytes(res.BytesSkippedSize))
+	}
 	if res.ArchiveSkippedSize > 0 {
 		fmt.Fprintf(out, "archive skipped folder: %s\n", filepath.Join(res.BackupRoot, "archive-skipped-size"))
 		fmt.Fprintln(out, "archive skipped repos:")
@@ -1050,11 +3120,230 @@ func runBackupTask(ctx context.Context, gh github.Client, runner app.CommandRunn
 		fmt.Fprintf(out, "archive branch: %s\n", res.ArchiveBranch)
 		if res.ArchiveCommit != "" {
 			fmt.Fprintf(out, "archive commit: %s\n", res.ArchiveCommit)
+			fmt.Fprintf(out, "archive published: %d bundles (%s)\n", res.ArchiveBundles, formatBytes(res.ArchiveBytes))
+			if res.ArchiveDeduped > 0 {
+				fmt.Fprintf(out, "archive already had: %d bundles\n", res.ArchiveDeduped)
+			}
+		}
+	}
+	return nil
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+type runSummary struct {
+	Mode                string           `json:"mode"`
+	DryRun              bool             `json:"dryRun"`
+	Total               int              `json:"total"`
+	Deleted             int              `json:"deleted"`
+	Failed              int              `json:"failed"`
+	SkippedNoBackup     int              `json:"skippedNoBackup"`
+	SkippedProtected    int              `json:"skippedProtected"`
+	ArchiveFailed       int              `json:"archiveFailed"`
+	ArchiveSkippedSize  int              `json:"archiveSkippedSize"`
+	BackupRoot          string           `json:"backupRoot"`
+	ManifestPath        string           `json:"manifestPath"`
+	ArchiveRepo         string           `json:"archiveRepo,omitempty"`
+	ArchiveBranch       string           `json:"archiveBranch,omitempty"`
+	ArchiveCommit       string           `json:"archiveCommit,omitempty"`
+	ArchiveBundles      int              `json:"archiveBundles"`
+	ArchiveBytes        int64            `json:"archiveBytes"`
+	ArchiveDeduped      int              `json:"archiveDeduped"`
+	ArchiveSkippedRepos []string         `json:"archiveSkippedRepos"`
+	BytesMirrored       int64            `json:"bytesMirrored"`
+	BytesBundled        int64            `json:"bytesBundled"`
+	BytesSkippedSize    int64            `json:"bytesSkippedSize"`
+	Repos               []repoRunSummary `json:"repos"`
+}
+
+type repoRunSummary struct {
+	FullName      string `json:"fullName"`
+	Status        string `json:"status"`
+	ArchiveStatus string `json:"archiveStatus,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// progressWriter keeps stdout clean for JSON output by sending executor
+// progress lines and prompts to stderr instead.
+func progressWriter(format string, out io.Writer) (io.Writer, error) {
+	switch format {
+	case "", outputText:
+		return out, nil
+	case outputJSON:
+		return os.Stderr, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func buildRunSummary(mode string, dryRun bool, res executor.Result) (runSummary, error) {
+	summary := runSummary{
+		Mode:                mode,
+		DryRun:              dryRun,
+		Total:               res.Total,
+		Deleted:             res.Deleted,
+		Failed:              res.Failed,
+		SkippedNoBackup:     res.SkippedNoBackup,
+		SkippedProtected:    res.SkippedProtected,
+		ArchiveFailed:       res.ArchiveFailed,
+		ArchiveSkippedSize:  res.ArchiveSkippedSize,
+		BackupRoot:          res.BackupRoot,
+		ManifestPath:        res.ManifestPath,
+		ArchiveRepo:         res.ArchiveRepo,
+		ArchiveBranch:       res.ArchiveBranch,
+		ArchiveCommit:       res.ArchiveCommit,
+		ArchiveBundles:      res.ArchiveBundles,
+		ArchiveBytes:        res.ArchiveBytes,
+		ArchiveDeduped:      res.ArchiveDeduped,
+		ArchiveSkippedRepos: res.ArchiveSkippedRepos,
+		BytesMirrored:       res.BytesMirrored,
+		BytesBundled:        res.BytesBundled,
+		BytesSkippedSize:    res.BytesSkippedSize,
+		Repos:               []repoRunSummary{},
+	}
+	if summary.ArchiveSkippedRepos == nil {
+		summary.ArchiveSkippedRepos = []string{}
+	}
+	if mode == executor.ModeDelete {
+		summary.ArchiveRepo = ""
+		summary.ArchiveBranch = ""
+	}
+	if dryRun {
+		return summary, nil
+	}
+	m, err := manifest.Read(res.ManifestPath)
+	if err != nil {
+		return runSummary{}, fmt.Errorf("read manifest for summary: %w", err)
+	}
+	for _, entry := range m.RepoExecutions {
+		summary.Repos = append(summary.Repos, repoRunSummary{
+			FullName:      entry.FullName,
+			Status:        string(entry.Status),
+			ArchiveStatus: entry.ArchiveStatus,
+			Error:         entry.Error,
+		})
+	}
+	return summary, nil
+}
+
+func writeRunSummaryJSON(out io.Writer, mode string, dryRun bool, res executor.Result) error {
+	summary, err := buildRunSummary(mode, dryRun, res)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = out.Write(b)
+	return err
+}
+
+func assumeYesFromEnv() bool {
+	v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("GH_MANAGER_ASSUME_YES")))
+	return err == nil && v
+}
+
+func resumeSearchDirs(dir string) []string {
+	if strings.TrimSpace(dir) == "" {
+		return nil
+	}
+	return []string{strings.TrimSpace(dir)}
+}
+
+// loadKeybindings resolves the configured TUI keys. An invalid config or
+// binding is reported to w and the defaults are used instead.
+func loadKeybindings(w io.Writer) map[string]string {
+	cfg, err := configpkg.Load()
+	if err != nil {
+		fmt.Fprintf(w, "warning: loading keybindings failed: %v; using the defaults\n", err)
+		return nil
+	}
+	bindings, err := configpkg.ResolveKeybindings(cfg.Keybindings)
+	if err != nil {
+		fmt.Fprintf(w, "warning: %v; using the default keybindings\n", err)
+		return nil
+	}
+	return bindings
+}
+
+func configuredScanArchives() bool {
+	cfg, err := configpkg.Load()
+	if err != nil {
+		return false
+	}
+	return cfg.Backup.ScanArchives
+}
+
+// resolveArchiveRepo prefers the --archive-repo flag, then
+// archive.default_repo. Empty leaves the executor's <actor>/gh-manager-archive.
+func resolveArchiveRepo(flagValue string) (string, error) {
+	repo := strings.TrimSpace(flagValue)
+	source := "--archive-repo"
+	if repo == "" {
+		if cfg, err := configpkg.Load(); err == nil {
+			repo = strings.TrimSpace(cfg.Archive.DefaultRepo)
+			source = "archive.default_repo"
 		}
 	}
+	if repo == "" {
+		return "", nil
+	}
+	if err := github.ValidateFullName(repo); err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+	return repo, nil
+}
+
+func configuredProtectedRepos() ([]string, error) {
+	cfg, err := configpkg.Load()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.ProtectedRepos, nil
+}
+
+// refuseProtected stops a direct delete of a repo matching protected_repos.
+func refuseProtected(fullName string) error {
+	patterns, err := configuredProtectedRepos()
+	if err != nil {
+		return err
+	}
+	isProtected, err := planfile.ProtectedMatcher(patterns)
+	if err != nil {
+		return err
+	}
+	if isProtected(fullName) {
+		return fmt.Errorf("%s is protected by protected_repos; remove it from the config to delete it", fullName)
+	}
 	return nil
 }
 
+func configuredBackupBase() (string, error) {
+	cfg, err := configpkg.Load()
+	if err != nil {
+		return "", err
+	}
+	return cfg.Backup.DefaultDir, nil
+}
+
 func resolveBackupLocation(backupDir, backupLocation string) (string, error) {
 	if backupDir != "" && backupLocation != "" && backupDir != backupLocation {
 		return "", errors.New("use either --backup-location or --backup-dir, not both with different values")
@@ -1075,6 +3364,27 @@ func truncateInspect(s string, max int) string {
 	return s[:max-1] + "~"
 }
 
+// interruptedError turns executor.ErrInterrupted into a message pointing at
+// the saved manifest; other errors pass through.
+func interruptedError(err error, res executor.Result) error {
+	if !errors.Is(err, executor.ErrInterrupted) {
+		return err
+	}
+	return fmt.Errorf("interrupted; manifest saved to %s, resume with --resume", res.ManifestPath)
+}
+
+// withInterrupt cancels ctx on SIGINT/SIGTERM so a run can stop between
+// repos and save its manifest instead of dying mid-write. Default signal
+// handling comes back after the first one, so a second Ctrl-C exits at once.
+func withInterrupt(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
 func fatal(err error) {
 	fmt.Fprintf(os.Stderr, "error: %v\n", err)
 	os.Exit(1)
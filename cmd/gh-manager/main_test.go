@@ -1,9 +1,26 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"gh-manager/internal/app"
+	configpkg "gh-manager/internal/config"
+	"gh-manager/internal/executor"
+	"gh-manager/internal/github"
+	"gh-manager/internal/manifest"
+	"gh-manager/internal/planfile"
+	themepkg "gh-manager/internal/theme"
+	"gh-manager/internal/version"
 )
 
 type fakeRunner struct {
@@ -18,6 +35,166 @@ func (f fakeRunner) Run(ctx context.Context, name string, args ...string) ([]byt
 	return f.out, nil
 }
 
+type scriptRunner map[string]string
+
+func (s scriptRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	out, ok := s[name+" "+strings.Join(args, " ")]
+	if !ok {
+		return nil, errors.New("unexpected command")
+	}
+	return []byte(out), nil
+}
+
+// restoreRunner answers gh repo view with success only for existing repos and
+// records every gh repo create.
+type restoreRunner struct {
+	existing map[string]bool
+	created  []string
+}
+
+func (r *restoreRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	if name == "gh" && len(args) > 2 && args[0] == "repo" {
+		switch args[1] {
+		case "view":
+			if !r.existing[args[2]] {
+				return nil, errors.New("HTTP 404: Not Found")
+			}
+		case "create":
+			r.created = append(r.created, args[2])
+		}
+	}
+	return nil, nil
+}
+
+func TestCaptureHeadsAndDetectDrift(t *testing.T) {
+	repos := []planfile.RepoRecord{{FullName: "alice/a"}, {FullName: "alice/b"}, {FullName: "alice/empty"}}
+	r := scriptRunner{
+		"gh api repos/alice/a/commits/HEAD --jq .sha": "aaaaaaaaaa\n",
+		"gh api repos/alice/b/commits/HEAD --jq .sha": "bbbbbbbbbb\n",
+	}
+	var warn bytes.Buffer
+	captured := captureHeads(context.Background(), github.NewClient(r), repos, &warn)
+	if captured[0].HeadSHA != "aaaaaaaaaa" || captured[1].HeadSHA != "bbbbbbbbbb" || captured[2].HeadSHA != "" {
+		t.Fatalf("unexpected captured heads: %+v", captured)
+	}
+	if repos[0].HeadSHA != "" {
+		t.Fatal("captureHeads must not mutate its input")
+	}
+	if !strings.Contains(warn.String(), "could not capture HEAD for alice/empty") {
+		t.Fatalf("expected capture warning, got %q", warn.String())
+	}
+
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	withHead := planfile.New("alice", "github.com", "test", captured, now)
+	without := planfile.New("alice", "github.com", "test", repos, now)
+	fpWith, _ := withHead.ComputeFingerprint()
+	fpWithout, _ := without.ComputeFingerprint()
+	if fpWith == fpWithout {
+		t.Fatal("expected HEAD sha to be part of the fingerprint")
+	}
+
+	r["gh api repos/alice/b/commits/HEAD --jq .sha"] = "cccccccccc\n"
+	warn.Reset()
+	if n := checkHeadDrift(context.Background(), github.NewClient(r), withHead, &warn); n != 1 {
+		t.Fatalf("expected one drifted repo, got %d", n)
+	}
+	if !strings.Contains(warn.String(), "alice/b has new commits since the plan was created (plan bbbbbbb, live ccccccc)") {
+		t.Fatalf("unexpected drift warning: %q", warn.String())
+	}
+}
+
+func TestPlanOutPathFormat(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got, err := planOutPath("", "yaml", now); err != nil || got != filepath.Join(".", "deletion-plan-20260304-050607.yaml") {
+		t.Fatalf("unexpected default yaml path: %q %v", got, err)
+	}
+	if got, err := planOutPath("p.yml", "yaml", now); err != nil || got != "p.yml" {
+		t.Fatalf("unexpected yaml path: %q %v", got, err)
+	}
+	if got, err := planOutPath("p.yaml", "", now); err != nil || got != "p.yaml" {
+		t.Fatalf("expected extension to decide: %q %v", got, err)
+	}
+	if _, err := planOutPath("p.json", "yaml", now); err == nil {
+		t.Fatal("expected mismatch error")
+	}
+	if _, err := planOutPath("", "toml", now); err == nil {
+		t.Fatal("expected unknown format error")
+	}
+}
+
+func TestRunListSourceAndLimit(t *testing.T) {
+	r := scriptRunner{
+		"gh api user/repos?affiliation=collaborator,organization_member&per_page=5&sort=full_name": `[{"name":"x","full_name":"org/x","owner":{"login":"org"},"private":true,"updated_at":"2026-01-01T00:00:00Z"}]`,
+	}
+	var out bytes.Buffer
+	if err := runList(context.Background(), github.NewClient(r), r, []string{"--source", "member", "--limit", "5"}, &out); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if want := "org/x\tprivate\t2026-01-01T00:00:00Z\n1 repos (0 filtered out)\n"; out.String() != want {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	for _, args := range [][]string{
+		{"--source", "member", "--owner", "bob"},
+		{"--source", "friends"},
+		{"--limit", "0"},
+	} {
+		if err := runList(context.Background(), github.NewClient(r), r, args, &out); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestRepoFilterFlagsApplyDateRange(t *testing.T) {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	var rf repoFilterFlags
+	rf.register(fs)
+	if err := fs.Parse([]string{"--updated-before", "2023", "--exclude-forks", "--unknown-updated", "include"}); err != nil {
+		t.Fatal(err)
+	}
+	filters, err := rf.filters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos := []planfile.RepoRecord{
+		{FullName: "alice/stale", UpdatedAt: "2021-05-01T00:00:00Z"},
+		{FullName: "alice/stale-fork", UpdatedAt: "2021-05-01T00:00:00Z", IsFork: true},
+		{FullName: "alice/fresh", UpdatedAt: "2025-01-01T00:00:00Z"},
+		{FullName: "alice/unknown", IsPrivate: true, IsArchived: true},
+	}
+	kept, dropped := planfile.FilterRepos(repos, filters...)
+	if dropped != 2 || len(kept) != 2 {
+		t.Fatalf("unexpected filter result: kept=%v dropped=%d", kept, dropped)
+	}
+	want := "alice/stale\tpublic\t2021-05-01T00:00:00Z\nalice/unknown\tprivate\t-\tarchived\n"
+	if got := listToString(kept); got != want {
+		t.Fatalf("unexpected list output:\n%q", got)
+	}
+
+	for _, args := range [][]string{
+		{"--updated-before", "soon"},
+		{"--updated-after", "2024", "--updated-before", "2023"},
+		{"--updated-before", "2023", "--unknown-updated", "maybe"},
+	} {
+		fs := flag.NewFlagSet("list", flag.ContinueOnError)
+		var bad repoFilterFlags
+		bad.register(fs)
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := bad.filters(); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestRunBackupTaskRejectsUnknownArchiveVisibility(t *testing.T) {
+	r := fakeRunner{err: errors.New("runner must not be called")}
+	err := runBackupTask(context.Background(), github.NewClient(r), r, backupConfig{PlanPath: "plan.json", ArchiveVisibility: "secret"}, strings.NewReader(""), &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "--archive-visibility") {
+		t.Fatalf("expected up-front visibility error, got %v", err)
+	}
+}
+
 func TestCompareSemverLabels(t *testing.T) {
 	if got := compareSemverLabels("v0.1.0", "v0.1.1"); got >= 0 {
 		t.Fatalf("expected v0.1.0 < v0.1.1, got %d", got)
@@ -50,3 +227,972 @@ func TestCheckLatestReleaseError(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestAssumeYesFromEnv(t *testing.T) {
+	t.Setenv("GH_MANAGER_ASSUME_YES", "")
+	if assumeYesFromEnv() {
+		t.Fatal("expected unset env to keep prompts")
+	}
+	t.Setenv("GH_MANAGER_ASSUME_YES", "1")
+	if !assumeYesFromEnv() {
+		t.Fatal("expected GH_MANAGER_ASSUME_YES=1 to bypass")
+	}
+	t.Setenv("GH_MANAGER_ASSUME_YES", "nope")
+	if assumeYesFromEnv() {
+		t.Fatal("expected invalid value to keep prompts")
+	}
+}
+
+func TestWriteRunSummaryJSONIncludesManifestStatuses(t *testing.T) {
+	root := t.TempDir()
+	p := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{FullName: "alice/r1"}, {FullName: "alice/r2"}}, time.Now())
+	m := manifest.New("plan.json", root, p, time.Now(), manifest.NewOptions{Mode: executor.ModeDelete})
+	m.RepoExecutions[0].Status = manifest.StatusDeleted
+	m.RepoExecutions[1].Status = manifest.StatusBackupFailed
+	m.RepoExecutions[1].Error = "clone failed"
+	manifestPath := filepath.Join(root, "manifest.json")
+	if err := manifest.Write(manifestPath, m); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	res := executor.Result{ManifestPath: manifestPath, BackupRoot: root, Deleted: 1, Failed: 1, Total: 2}
+	if err := writeRunSummaryJSON(&out, executor.ModeDelete, false, res); err != nil {
+		t.Fatalf("write summary: %v", err)
+	}
+	var got runSummary
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("summary is not valid JSON: %v\n%s", err, out.String())
+	}
+	if got.Deleted != 1 || got.Failed != 1 || got.Total != 2 || got.BackupRoot != root {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+	if len(got.Repos) != 2 || got.Repos[1].Status != "backup_failed" || got.Repos[1].Error != "clone failed" {
+		t.Fatalf("unexpected repo statuses: %+v", got.Repos)
+	}
+}
+
+func TestProgressWriterRejectsUnknownFormat(t *testing.T) {
+	if _, err := progressWriter("yaml", &bytes.Buffer{}); err == nil {
+		t.Fatal("expected unsupported format error")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[int64]string{
+		0:                      "0 B",
+		1023:                   "1023 B",
+		1024:                   "1.0 KiB",
+		1536:                   "1.5 KiB",
+		100 * 1024 * 1024:      "100.0 MiB",
+		3 * 1024 * 1024 * 1024: "3.0 GiB",
+	}
+	for n, want := range cases {
+		if got := formatBytes(n); got != want {
+			t.Fatalf("formatBytes(%d)=%q want %q", n, got, want)
+		}
+	}
+}
+
+func TestPrintWhoamiReportsUserAndHost(t *testing.T) {
+	r := scriptRunner{"gh api user --jq .login": "alice\n"}
+	var out bytes.Buffer
+	if err := printWhoami(context.Background(), github.NewClient(r), "ghe.example.com", &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "logged in as alice on ghe.example.com\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestPlanAllReposAppliesOwnerAndFilters(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	wd, _ := os.Getwd()
+	if err := os.Chdir(home); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	r := scriptRunner{
+		"gh api user --jq .login": "alice\n",
+		"gh repo list acme --limit 1000 --json name,nameWithOwner,description,updatedAt,isPrivate,visibility,isFork,isArchived,diskUsage,owner,primaryLanguage,repositoryTopics": `[
+			{"name":"a","nameWithOwner":"acme/a","owner":{"login":"acme"}},
+			{"name":"old","nameWithOwner":"acme/old","owner":{"login":"acme"},"isArchived":true},
+			{"name":"fork","nameWithOwner":"acme/fork","owner":{"login":"acme"},"isFork":true}
+		]`,
+	}
+	var out bytes.Buffer
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	planPath, err := planAllRepos(context.Background(), github.NewClient(r), "acme", "github.com", "", []planfile.RepoFilter{planfile.ExcludeArchived}, nil, planGuards{}, now, &out)
+	if err != nil {
+		t.Fatalf("plan all: %v", err)
+	}
+	if !strings.Contains(out.String(), "filtered out 1 repos (2 remaining)") || !strings.Contains(out.String(), "auto-generated plan: "+planPath+" (2 repos)") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	p, err := planfile.Read(planPath)
+	if err != nil {
+		t.Fatalf("read plan: %v", err)
+	}
+	if p.Actor != "alice" || p.Count != 2 || p.Repos[0].FullName != "acme/a" || p.Repos[1].FullName != "acme/fork" {
+		t.Fatalf("unexpected plan: %+v", p)
+	}
+}
+
+func TestRunPruneArchivesDryRunThenConfirm(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GH_MANAGER_ASSUME_YES", "")
+	now := time.Now()
+	for i, name := range []string{"gh-manager-archive-new", "gh-manager-archive-old"} {
+		root := filepath.Join(home, name)
+		if err := os.MkdirAll(root, 0o700); err != nil {
+			t.Fatal(err)
+		}
+		p := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{FullName: "alice/r1"}}, now)
+		m := manifest.New("plan.json", root, p, now, manifest.NewOptions{Mode: executor.ModeBackup})
+		m.RepoExecutions[0].Status = manifest.StatusBackupOK
+		m.RepoExecutions[0].ArchiveStatus = "archived"
+		if err := manifest.Write(manifest.Path(root), m); err != nil {
+			t.Fatal(err)
+		}
+		mod := now.AddDate(0, 0, -10*i)
+		_ = os.Chtimes(manifest.Path(root), mod, mod)
+	}
+
+	var out bytes.Buffer
+	if err := runPruneArchives([]string{"--keep", "1"}, strings.NewReader(""), &out, now); err != nil {
+		t.Fatalf("dry-run: %v", err)
+	}
+	if !strings.Contains(out.String(), "prune "+filepath.Join(home, "gh-manager-archive-old")) || !strings.Contains(out.String(), "dry-run: 1 of 2") {
+		t.Fatalf("unexpected dry-run output: %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(home, "gh-manager-archive-old")); err != nil {
+		t.Fatalf("dry-run must not delete: %v", err)
+	}
+	if err := runPruneArchives([]string{"--older-than", "5d", "--dry-run=false"}, strings.NewReader("2\n"), &out, now); err == nil {
+		t.Fatal("expected wrong count to cancel")
+	}
+	if err := runPruneArchives([]string{"--older-than", "5d", "--dry-run=false"}, strings.NewReader("1\n"), &out, now); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, "gh-manager-archive-old")); !os.IsNotExist(err) {
+		t.Fatalf("expected old root removed, err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, "gh-manager-archive-new")); err != nil {
+		t.Fatalf("expected new root kept: %v", err)
+	}
+	if err := runPruneArchives(nil, strings.NewReader(""), &out, now); err == nil {
+		t.Fatal("expected --older-than/--keep to be required")
+	}
+	if _, err := parseAge("3w"); err == nil {
+		t.Fatal("expected unsupported age unit error")
+	}
+}
+
+func TestEnterpriseHostPlanRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GH_HOST", "")
+	host := app.ResolveHost("ghe.example.com")
+	planPath := filepath.Join(home, "plan.json")
+	if _, _, err := createSignedPlan("alice", host, "", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, planPath, planGuards{}, time.Now()); err != nil {
+		t.Fatalf("create plan: %v", err)
+	}
+	p, err := planfile.Read(planPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Host != "ghe.example.com" {
+		t.Fatalf("expected enterprise host recorded, got %q", p.Host)
+	}
+	configDir, err := app.ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := planfile.EnsureSecret(configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Validate(secret); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if err := checkPlanHost(p, host); err != nil {
+		t.Fatalf("host check: %v", err)
+	}
+	if err := checkPlanHost(p, app.ResolveHost("")); err == nil {
+		t.Fatal("expected host mismatch against github.com")
+	}
+	if got := executionHost("", planPath); got != "ghe.example.com" {
+		t.Fatalf("expected execution routed to the plan's host, got %q", got)
+	}
+	if got := executionHost("other.example.com", planPath); got != "other.example.com" {
+		t.Fatalf("expected --host to win, got %q", got)
+	}
+	t.Setenv("GH_HOST", "env.example.com")
+	if got := executionHost("", planPath); got != "env.example.com" {
+		t.Fatalf("expected GH_HOST to win over the plan, got %q", got)
+	}
+	if got := executionHost("", filepath.Join(home, "missing.json")); got != "env.example.com" {
+		t.Fatalf("unexpected host for missing plan: %q", got)
+	}
+}
+
+func TestCreateSignedPlanRefusesProtectedRepos(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if err := runConfig([]string{"set", "protected_repos", "alice/prod-*"}, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	selected := []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}, {Owner: "alice", Name: "prod-api", FullName: "alice/prod-api"}}
+	planPath := filepath.Join(home, "plan.json")
+	if _, _, err := createSignedPlan("alice", "github.com", "", selected, planPath, planGuards{}, time.Now()); err == nil || !strings.Contains(err.Error(), "alice/prod-api") {
+		t.Fatalf("expected protected repo refusal, got %v", err)
+	}
+	if _, err := os.Stat(planPath); !os.IsNotExist(err) {
+		t.Fatalf("no plan should be written: %v", err)
+	}
+	if _, count, err := createSignedPlan("alice", "github.com", "", selected, planPath, planGuards{AllowProtected: true}, time.Now()); err != nil || count != 2 {
+		t.Fatalf("--allow-protected should include the repo: %d %v", count, err)
+	}
+	if err := refuseProtected("alice/prod-api"); err == nil {
+		t.Fatal("expected direct delete of a protected repo to be refused")
+	}
+	if err := refuseProtected("alice/r1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateSignedPlanEnforcesMaxPlanSize(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	selected := []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}, {Owner: "alice", Name: "r2", FullName: "alice/r2"}, {Owner: "alice", Name: "r3", FullName: "alice/r3"}}
+	planPath := filepath.Join(home, "plan.json")
+	if _, _, err := createSignedPlan("alice", "github.com", "", selected, planPath, planGuards{}, time.Now()); err != nil {
+		t.Fatalf("no limit by default: %v", err)
+	}
+	if err := runConfig([]string{"set", "max_plan_size", "2"}, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := createSignedPlan("alice", "github.com", "", selected, planPath, planGuards{}, time.Now()); err == nil || !strings.Contains(err.Error(), "selected 3 repos") {
+		t.Fatalf("expected max_plan_size error, got %v", err)
+	}
+	if _, count, err := createSignedPlan("alice", "github.com", "", selected, planPath, planGuards{AllowLarge: true}, time.Now()); err != nil || count != 3 {
+		t.Fatalf("--allow-large should lift the limit: %d %v", count, err)
+	}
+	if _, _, err := createSignedPlan("alice", "github.com", "", selected[:2], planPath, planGuards{}, time.Now()); err != nil {
+		t.Fatalf("plan at the limit should pass: %v", err)
+	}
+}
+
+func TestTwoPhaseConfirmFingerprint(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	planPath := filepath.Join(home, "plan.json")
+	repos := []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}
+	if _, _, err := createSignedPlan("alice", "github.com", "", repos, planPath, planGuards{}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	p, err := readVerifiedPlan(planPath, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	printPlanForConfirmation(&out, planPath, p)
+	if !strings.Contains(out.String(), "alice/r1") || !strings.Contains(out.String(), "--confirm-fingerprint "+p.Fingerprint) {
+		t.Fatalf("unexpected first-phase output:\n%s", out.String())
+	}
+	if _, err := readVerifiedPlan(planPath, "", " "+p.Fingerprint+" "); err != nil {
+		t.Fatalf("matching fingerprint should pass: %v", err)
+	}
+
+	// Re-planning with another repo changes the fingerprint under the same path.
+	repos = append(repos, planfile.RepoRecord{Owner: "alice", Name: "r2", FullName: "alice/r2"})
+	if _, _, err := createSignedPlan("alice", "github.com", "", repos, planPath, planGuards{}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readVerifiedPlan(planPath, "", p.Fingerprint); err == nil || !strings.Contains(err.Error(), "fingerprint mismatch") {
+		t.Fatalf("expected fingerprint mismatch, got %v", err)
+	}
+}
+
+// twoPhaseRunner answers the user and repo list lookups and records every
+// other command as a success.
+type twoPhaseRunner struct {
+	calls []string
+}
+
+func (r *twoPhaseRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	call := name + " " + strings.Join(args, " ")
+	r.calls = append(r.calls, call)
+	switch {
+	case call == "gh api user --jq .login":
+		return []byte("alice\n"), nil
+	case strings.HasPrefix(call, "gh repo list alice "):
+		return []byte(`[{"name":"r1","nameWithOwner":"alice/r1","owner":{"login":"alice"}},{"name":"r2","nameWithOwner":"alice/r2","owner":{"login":"alice"}}]`), nil
+	}
+	return nil, nil
+}
+
+func TestRunExecuteTwoPhaseSavesPlanThenExecutes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GH_HOST", "")
+	// doctor.Check only needs gh and git to exist in PATH.
+	bin := filepath.Join(home, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"gh", "git"} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+	wd, _ := os.Getwd()
+	if err := os.Chdir(home); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	selection := filepath.Join(home, "delete.txt")
+	if err := os.WriteFile(selection, []byte("alice/r1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &twoPhaseRunner{}
+	var out bytes.Buffer
+	if err := runExecute(context.Background(), github.NewClient(r), r, []string{"--two-phase", "--from-file", selection}, &out); err != nil {
+		t.Fatalf("first phase: %v", err)
+	}
+	_, next, ok := strings.Cut(out.String(), "then run: gh-manager execute ")
+	if !ok {
+		t.Fatalf("expected the confirm command:\n%s", out.String())
+	}
+	confirmArgs := strings.Fields(next)
+	if len(confirmArgs) != 4 || confirmArgs[0] != "--plan" || confirmArgs[2] != "--confirm-fingerprint" {
+		t.Fatalf("unexpected confirm command: %v", confirmArgs)
+	}
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, "gh repo delete") {
+			t.Fatalf("first phase must not delete: %v", r.calls)
+		}
+	}
+
+	out.Reset()
+	args := append(confirmArgs, "--yes", "--backup-location", filepath.Join(home, "backups"))
+	if err := runExecute(context.Background(), github.NewClient(r), r, args, &out); err != nil {
+		t.Fatalf("second phase: %v\n%s", err, out.String())
+	}
+	var deleted []string
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, "gh repo delete ") {
+			deleted = append(deleted, call)
+		}
+	}
+	if len(deleted) != 1 || deleted[0] != "gh repo delete alice/r1 --yes" {
+		t.Fatalf("expected only the selected repo to be deleted, got %v", deleted)
+	}
+
+	wrong := []string{"--plan", confirmArgs[1], "--confirm-fingerprint", "not-the-fingerprint", "--yes"}
+	if err := runExecute(context.Background(), github.NewClient(r), r, wrong, &out); err == nil || !strings.Contains(err.Error(), "fingerprint mismatch") {
+		t.Fatalf("expected fingerprint mismatch, got %v", err)
+	}
+}
+
+func TestSecretFileSignsAndVerifiesPlans(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	secretFile := filepath.Join(home, "team-secret.hex")
+	if err := os.WriteFile(secretFile, []byte(strings.Repeat("ab", 32)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	planPath := filepath.Join(home, "plan.json")
+	if _, _, err := createSignedPlan("alice", "github.com", secretFile, []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, planPath, planGuards{}, time.Now()); err != nil {
+		t.Fatalf("create plan: %v", err)
+	}
+	out, err := inspectToString(planPath, "", secretFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "signature: valid") {
+		t.Fatalf("expected plan to verify with the shared secret:\n%s", out)
+	}
+	out, err = inspectToString(planPath, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "signature: invalid") {
+		t.Fatalf("expected plan to fail against the local secret:\n%s", out)
+	}
+
+	short := filepath.Join(home, "short.hex")
+	if err := os.WriteFile(short, []byte("abcd\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := createSignedPlan("alice", "github.com", short, []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, planPath, planGuards{}, time.Now()); err == nil {
+		t.Fatal("expected a short secret file to be rejected")
+	}
+}
+
+func TestThemeExportWritesParsableFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	outPath := filepath.Join(home, "exported.json")
+	var out bytes.Buffer
+	if err := runTheme(context.Background(), []string{"export", "current", "--out", outPath}, &out); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	b, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tf, err := themepkg.ParseThemeFile(b)
+	if err != nil {
+		t.Fatalf("exported theme should parse: %v", err)
+	}
+	if tf.ID != "default" {
+		t.Fatalf("expected current theme to resolve to default, got %q", tf.ID)
+	}
+}
+
+func TestThemeDiffAgainstFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	path := filepath.Join(home, "variant.json")
+	if err := os.WriteFile(path, []byte(`{"id":"variant","colors":{"danger":"#010203"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := runTheme(context.Background(), []string{"diff", "default", path}, &out); err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	want := fmt.Sprintf("  %-22s %s  %s\n", "danger", themepkg.DefaultPaletteHex().Danger, "#010203")
+	if !strings.HasPrefix(out.String(), "1 of ") || !strings.Contains(out.String(), want) {
+		t.Fatalf("unexpected diff output:\n%s", out.String())
+	}
+	out.Reset()
+	if err := runTheme(context.Background(), []string{"diff", "default", "current"}, &out); err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if !strings.Contains(out.String(), "identical colors") {
+		t.Fatalf("expected identical palettes, got %q", out.String())
+	}
+}
+
+func TestThemeValidate(t *testing.T) {
+	id, err := themeValidate(strings.NewReader(`{"id":"mine","colors":{"danger":"#ff0000"}}`))
+	if err != nil || id != "mine" {
+		t.Fatalf("expected valid theme, got id=%q err=%v", id, err)
+	}
+	_, err = themeValidate(strings.NewReader(`{"id":"mine","colors":{"danger":"red"}}`))
+	if err == nil || !strings.Contains(err.Error(), "danger") {
+		t.Fatalf("expected error naming the danger field, got %v", err)
+	}
+}
+
+func TestThemeNewRefusesOverwriteWithoutForce(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	path, err := themeNew("my-theme", false)
+	if err != nil {
+		t.Fatalf("new theme: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := themepkg.ParseThemeFile(b); err != nil {
+		t.Fatalf("scaffold should parse: %v", err)
+	}
+	if _, err := themeNew("my-theme", false); err == nil {
+		t.Fatal("expected overwrite refusal")
+	}
+	if _, err := themeNew("my-theme", true); err != nil {
+		t.Fatalf("expected --force overwrite, got %v", err)
+	}
+	for _, bad := range []string{"default", "../evil", "Bad Name", ""} {
+		if _, err := themeNew(bad, true); err == nil {
+			t.Fatalf("expected id %q to be rejected", bad)
+		}
+	}
+}
+
+func TestFindRepoBackupScansArchiveChildren(t *testing.T) {
+	base := t.TempDir()
+	bundles := filepath.Join(base, "gh-manager-archive-2026-02-25-100000", "bundles")
+	if err := os.MkdirAll(bundles, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(bundles, "alice__r1.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path, found, err := findRepoBackup("alice/r1", []string{"", base})
+	if err != nil || !found || path != bundle {
+		t.Fatalf("expected backup at %s, got path=%q found=%t err=%v", bundle, path, found, err)
+	}
+	if _, found, err := findRepoBackup("alice/other", []string{base}); err != nil || found {
+		t.Fatalf("expected no backup for unknown repo, found=%t err=%v", found, err)
+	}
+}
+
+func TestInspectArchiveSummarizesEntries(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "bundles"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "bundles", "alice__b.bundle"), make([]byte, 2048), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "snapshots", "alice__a"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	out, err := inspectArchiveToString(root)
+	if err != nil {
+		t.Fatalf("inspect archive: %v", err)
+	}
+	a := strings.Index(out, "- alice/a bundle=no snapshot=yes")
+	b := strings.Index(out, "- alice/b bundle=yes snapshot=no size=2.0 KiB (bundle)")
+	if a < 0 || b < 0 || a > b {
+		t.Fatalf("unexpected archive summary:\n%s", out)
+	}
+	if !strings.Contains(out, "count: 2") {
+		t.Fatalf("expected count line:\n%s", out)
+	}
+	if _, err := inspectArchiveToString(t.TempDir()); err == nil {
+		t.Fatal("expected error for non-archive dir")
+	}
+}
+
+func TestRunStatusGroupsManifestEntries(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{
+		{FullName: "alice/a"}, {FullName: "alice/b"}, {FullName: "alice/c"}, {FullName: "alice/d"},
+	}, now)
+	m := manifest.New("plan.json", root, plan, now, manifest.NewOptions{Mode: executor.ModeBackup})
+	m.RepoExecutions[0].Status, m.RepoExecutions[0].ArchiveStatus = manifest.StatusBackupOK, "archived"
+	m.RepoExecutions[1].Status, m.RepoExecutions[1].Error = manifest.StatusBackupFailed, "clone failed"
+	m.RepoExecutions[2].Status, m.RepoExecutions[2].ArchiveStatus, m.RepoExecutions[2].Error = manifest.StatusBackupOK, "archive_failed", "push rejected"
+	if err := manifest.Write(manifest.Path(root), m); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runStatus([]string{"--backup-root", root}, &out); err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	for _, want := range []string{
+		"repos:     4 (0 deleted, 2 failed)",
+		"  backup_ok: 2\n",
+		"  pending: 1\n",
+		"  archive_failed: 1\n",
+		"archiveStatus:\n  archive_failed: 1\n  archived: 1\n  pending: 2\n",
+		"- alice/b [backup_failed]: clone failed",
+		"- alice/c [backup_ok, archive_failed]: push rejected",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("missing %q in:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "alice/a [") || strings.Contains(out.String(), "alice/d [") {
+		t.Fatalf("healthy repos listed as failed:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runStatus([]string{"--backup-root", root, "--output", "json"}, &out); err != nil {
+		t.Fatalf("status json: %v", err)
+	}
+	var got manifestStatus
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 4 || got.Status["backup_failed"] != 1 || got.Failed != len(got.FailedRepos) || len(got.FailedRepos) != 2 {
+		t.Fatalf("unexpected json summary: %+v", got)
+	}
+	if err := runStatus(nil, &out); err == nil {
+		t.Fatal("expected --backup-root to be required")
+	}
+}
+
+func TestTaskPlanPathPrefersExplicitPlan(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "plan.json")
+	p := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{FullName: "alice/a"}, {FullName: "alice/b"}}, now)
+	if err := planfile.Write(path, p); err != nil {
+		t.Fatal(err)
+	}
+	selected := []planfile.RepoRecord{{FullName: "alice/stale"}}
+	created := 0
+	create := func(rs []planfile.RepoRecord) (string, error) {
+		created++
+		return "generated.json", nil
+	}
+
+	var out bytes.Buffer
+	got, err := taskPlanPath(" "+path+" ", selected, create, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != path || created != 0 {
+		t.Fatalf("expected explicit plan without generating, got %q created=%d", got, created)
+	}
+	if want := "using repos from plan (2): " + path + "\nignoring current selection (1 repos)\n"; out.String() != want {
+		t.Fatalf("unexpected log:\n%s", out.String())
+	}
+
+	out.Reset()
+	got, err = taskPlanPath("", selected, create, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "generated.json" || created != 1 {
+		t.Fatalf("expected generated plan, got %q created=%d", got, created)
+	}
+	if want := "auto-generating plan from selection (1)\nauto-generated plan: generated.json (1 repos)\n"; out.String() != want {
+		t.Fatalf("unexpected log:\n%s", out.String())
+	}
+
+	if _, err := taskPlanPath(filepath.Join(t.TempDir(), "missing.json"), selected, create, &out); err == nil {
+		t.Fatal("expected missing plan error")
+	}
+}
+
+func TestRestoreNameTemplateFromFlags(t *testing.T) {
+	cases := []struct {
+		targetName, template, prefix, suffix string
+		all                                  bool
+		want                                 string
+	}{
+		{"", "", "", "", false, ""},
+		{"", "", "", "", true, "{name}"},
+		{"", "", "mig-", "", true, "mig-{name}"},
+		{"", "", "", "-old", false, "{name}-old"},
+		{"", "{owner}-{name}", "", "", true, "{owner}-{name}"},
+	}
+	for _, tc := range cases {
+		got, err := restoreNameTemplate(tc.targetName, tc.template, tc.prefix, tc.suffix, tc.all)
+		if err != nil || got != tc.want {
+			t.Fatalf("%+v: got %q, %v", tc, got, err)
+		}
+	}
+	for _, tc := range [][4]string{{"x", "", "p-", ""}, {"", "{name}", "", "-s"}, {"x", "{name}", "", ""}} {
+		if _, err := restoreNameTemplate(tc[0], tc[1], tc[2], tc[3], false); err == nil {
+			t.Fatalf("%v: expected conflicting flags error", tc)
+		}
+	}
+	if _, err := restoreNameTemplate("x", "", "", "", true); err == nil {
+		t.Fatal("expected --target-name with --all to fail")
+	}
+}
+
+func TestRunRestoreAllAppliesPrefixAndSuffix(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "bundles"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"alice__one.bundle", "alice__two.bundle"} {
+		if err := os.WriteFile(filepath.Join(root, "bundles", f), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &restoreRunner{existing: map[string]bool{"bob/mig-two-old": true, "bob/mig-two-old-2": true}}
+	err := runRestore(context.Background(), github.NewClient(r), r, []string{"--archive-root", root, "--all", "--target-owner", "bob", "--name-prefix", "mig-", "--name-suffix", "-old"})
+	if err != nil {
+		t.Fatalf("restore --all: %v", err)
+	}
+	if strings.Join(r.created, ",") != "bob/mig-one-old,bob/mig-two-old-3" {
+		t.Fatalf("unexpected created repos: %v", r.created)
+	}
+
+	r = &restoreRunner{}
+	err = runRestore(context.Background(), github.NewClient(r), r, []string{"--archive-root", root, "--all", "--target-owner", "bob", "--name-prefix", "bad name "})
+	if err == nil || err.Error() != "2 of 2 restores failed" || len(r.created) != 0 {
+		t.Fatalf("expected invalid names to fail every restore, got %v created=%v", err, r.created)
+	}
+	if err := runRestore(context.Background(), github.NewClient(r), r, []string{"--archive-root", root, "--all", "--target-owner", "bob", "--parallel", "0"}); err == nil || !strings.Contains(err.Error(), "--parallel") {
+		t.Fatalf("expected --parallel validation error, got %v", err)
+	}
+}
+
+func TestListRestorableSortsEntries(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "bundles"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"bob__zeta.bundle", "alice__one.bundle"} {
+		if err := os.WriteFile(filepath.Join(root, "bundles", f), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshot := filepath.Join(root, "snapshots", "alice__two")
+	if err := os.MkdirAll(snapshot, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := listRestorable(root, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "alice/one\tbundle\t" + filepath.Join(root, "bundles", "alice__one.bundle") + "\n" +
+		"alice/two\tsnapshot\t" + snapshot + "\n" +
+		"bob/zeta\tbundle\t" + filepath.Join(root, "bundles", "bob__zeta.bundle") + "\n"
+	if out.String() != want {
+		t.Fatalf("unexpected list:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	r := &restoreRunner{}
+	if err := runRestore(context.Background(), github.NewClient(r), r, []string{"--archive-root", t.TempDir(), "--list"}); err != nil || len(r.created) != 0 {
+		t.Fatalf("--list should not restore: %v %v", err, r.created)
+	}
+}
+
+func TestImportListMatchesRepos(t *testing.T) {
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "repos.txt")
+	if err := os.WriteFile(listPath, []byte("# exported\nalice/One\nweb\nhttps://github.com/alice/api.git\ngit@github.com:alice/one.git\nalice/gone\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	names, err := readImportList(listPath, "", "")
+	if err != nil || len(names) != 5 {
+		t.Fatalf("unexpected list entries: %v %v", names, err)
+	}
+	repos := []planfile.RepoRecord{{FullName: "alice/one", UpdatedAt: "2026-01-01T00:00:00Z"}, {FullName: "alice/web", IsPrivate: true}, {FullName: "alice/api"}}
+	matched, unmatched := matchImported(repos, names, "alice")
+	var got []string
+	for _, r := range matched {
+		got = append(got, r.FullName)
+	}
+	if strings.Join(got, ",") != "alice/one,alice/web,alice/api" || !matched[1].IsPrivate || matched[0].UpdatedAt == "" {
+		t.Fatalf("unexpected matches: %+v", matched)
+	}
+	if strings.Join(unmatched, ",") != "alice/gone" {
+		t.Fatalf("unexpected unmatched: %v", unmatched)
+	}
+
+	csvPath := filepath.Join(dir, "inventory.csv")
+	if err := os.WriteFile(csvPath, []byte("owner,Full_Name,notes\nalice,alice/web,keep\nalice,,\nalice,alice/api,\"x, y\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if names, err := readImportList(csvPath, "", ""); err != nil || strings.Join(names, ",") != "alice/web,alice/api" {
+		t.Fatalf("unexpected csv entries: %v %v", names, err)
+	}
+	if names, err := readImportList(csvPath, "csv", "owner"); err != nil || len(names) != 3 {
+		t.Fatalf("unexpected --column entries: %v %v", names, err)
+	}
+	if _, err := readImportList(csvPath, "csv", "missing"); err == nil {
+		t.Fatal("expected missing column error")
+	}
+	if _, err := readImportList(listPath, "csv", ""); err == nil || !strings.Contains(err.Error(), "--column") {
+		t.Fatalf("expected no repo column error, got %v", err)
+	}
+	if _, err := readImportList(listPath, "xml", ""); err == nil {
+		t.Fatal("expected unsupported format error")
+	}
+}
+
+func TestRunVersionOutputs(t *testing.T) {
+	var out bytes.Buffer
+	if err := runVersion(nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != version.Value+"\n" {
+		t.Fatalf("plain version output changed: %q", out.String())
+	}
+	out.Reset()
+	if err := runVersion([]string{"--output", "json"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	var got version.Info
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != version.Current() {
+		t.Fatalf("unexpected json: %+v", got)
+	}
+	want := out.String()
+	out.Reset()
+	if err := runVersion([]string{"--json"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Fatalf("--json differs from --output json: %q", out.String())
+	}
+	if err := runVersion([]string{"--json", "--output", "yaml"}, &out); err == nil {
+		t.Fatal("expected --json to conflict with --output yaml")
+	}
+	if err := runVersion([]string{"--output", "yaml"}, &out); err == nil {
+		t.Fatal("expected unsupported output error")
+	}
+}
+
+func TestCachedUpdateInfoHonoursTTLAndVersion(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	c := configpkg.UpdateCheck{CheckedAt: "2026-10-16T02:00:00Z", CurrentVersion: "v0.1.1", LatestVersion: "v0.1.2", ReleaseURL: "https://example.test/r"}
+
+	info, ok := cachedUpdateInfo(c, "v0.1.1", 24*time.Hour, now)
+	if !ok || !info.UpdateAvailable || info.LatestVersion != "v0.1.2" || info.ReleaseURL != c.ReleaseURL || !info.CheckedAt.Equal(now.Add(-10*time.Hour)) {
+		t.Fatalf("expected fresh cached info, got %+v ok=%t", info, ok)
+	}
+	if _, ok := cachedUpdateInfo(c, "v0.1.1", 10*time.Hour, now); ok {
+		t.Fatal("expected check at the TTL boundary to be stale")
+	}
+	if _, ok := cachedUpdateInfo(c, "v0.1.2", 24*time.Hour, now); ok {
+		t.Fatal("expected a check made by another version to be ignored")
+	}
+	if _, ok := cachedUpdateInfo(c, "v0.1.1", 0, now); ok {
+		t.Fatal("expected zero TTL to disable the cache")
+	}
+	c.CheckedAt = "not a time"
+	if _, ok := cachedUpdateInfo(c, "v0.1.1", 24*time.Hour, now); ok {
+		t.Fatal("expected unparseable timestamp to be stale")
+	}
+}
+
+func TestInspectCSVQuotesDescriptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	p := planfile.DeletionPlanV1{
+		SchemaVersion: "1",
+		Repos: []planfile.RepoRecord{
+			{FullName: "alice/a", IsPrivate: true, UpdatedAt: "2025-01-02T00:00:00Z", Description: "tools, scripts"},
+			{FullName: "alice/b", IsFork: true, IsArchived: true, Description: "line one\nline \"two\""},
+		},
+	}
+	if err := planfile.Write(path, p); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := inspectCSV(path, &out); err != nil {
+		t.Fatalf("inspect csv: %v", err)
+	}
+	want := "fullName,visibility,isFork,isArchived,updatedAt,description\n" +
+		"alice/a,private,false,false,2025-01-02T00:00:00Z,\"tools, scripts\"\n" +
+		"alice/b,public,true,true,,\"line one\nline \"\"two\"\"\"\n"
+	if out.String() != want {
+		t.Fatalf("unexpected csv:\n%s", out.String())
+	}
+}
+
+func TestThemeIndexFallsBackToCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	indexPath := filepath.Join(home, "index.json")
+	if err := os.WriteFile(indexPath, []byte(`{"version":1,"themes":[{"id":"dusk","name":"Dusk","url":"dusk.json"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	indexURL := "file://" + indexPath
+	if _, label, err := fetchThemeIndexWithLocalFallback(context.Background(), []string{indexURL}); err != nil || label != indexURL {
+		t.Fatalf("expected live fetch, label=%q err=%v", label, err)
+	}
+	if err := os.Remove(indexPath); err != nil {
+		t.Fatal(err)
+	}
+	merged, label, err := fetchThemeIndexWithLocalFallback(context.Background(), []string{indexURL})
+	if err != nil {
+		t.Fatalf("expected cache fallback: %v", err)
+	}
+	_, sourceURL, ok := merged.Find("dusk")
+	if !ok || sourceURL != indexURL || !strings.Contains(label, "(cached just now)") {
+		t.Fatalf("unexpected cached result: found=%t source=%q label=%q", ok, sourceURL, label)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	cases := map[time.Duration]string{
+		10 * time.Second: "just now",
+		5 * time.Minute:  "5m ago",
+		3 * time.Hour:    "3h ago",
+		72 * time.Hour:   "3d ago",
+	}
+	for d, want := range cases {
+		if got := formatAge(d); got != want {
+			t.Fatalf("formatAge(%s)=%q want %q", d, got, want)
+		}
+	}
+}
+
+func TestResolveArchiveRepoUsesConfigDefaultAndFlagOverride(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	if got, err := resolveArchiveRepo(""); err != nil || got != "" {
+		t.Fatalf("expected executor default without config, got %q (%v)", got, err)
+	}
+	var out bytes.Buffer
+	if err := runConfig([]string{"set", "archive.default_repo", "team/shared-archive"}, &out); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got, err := resolveArchiveRepo(""); err != nil || got != "team/shared-archive" {
+		t.Fatalf("expected config default, got %q (%v)", got, err)
+	}
+	if got, err := resolveArchiveRepo("alice/mine"); err != nil || got != "alice/mine" {
+		t.Fatalf("expected flag to override config, got %q (%v)", got, err)
+	}
+	if _, err := resolveArchiveRepo("no-slash"); err == nil || !strings.Contains(err.Error(), "--archive-repo") {
+		t.Fatalf("expected malformed flag error, got %v", err)
+	}
+	if err := runConfig([]string{"set", "archive.default_repo", "bad/name/x"}, &out); err == nil {
+		t.Fatal("expected config validation error")
+	}
+}
+
+func TestLoadKeybindingsFallsBackOnConflict(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	cfg := configpkg.Default()
+	cfg.Keybindings = map[string]string{"sort_name": "j"}
+	if err := configpkg.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	var warn bytes.Buffer
+	if got := loadKeybindings(&warn); got != nil {
+		t.Fatalf("expected the defaults (nil), got %v", got)
+	}
+	if !strings.Contains(warn.String(), "conflict") {
+		t.Fatalf("expected a conflict warning, got %q", warn.String())
+	}
+	if _, err := configpkg.Load(); err != nil {
+		t.Fatalf("other commands should still load the config: %v", err)
+	}
+}
+
+func TestRunConfigSetGetPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	var out bytes.Buffer
+	if err := runConfig([]string{"set", "theme.auto_update_index", "false"}, &out); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	out.Reset()
+	if err := runConfig([]string{"get", "theme.auto_update_index"}, &out); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "false" {
+		t.Fatalf("expected persisted false, got %q", out.String())
+	}
+	if err := runConfig([]string{"set", "theme.auto_update_index", "nah"}, &out); err == nil {
+		t.Fatal("expected validation error")
+	}
+	out.Reset()
+	if err := runConfig([]string{"path"}, &out); err != nil {
+		t.Fatalf("path: %v", err)
+	}
+	if want := filepath.Join(home, ".config", "gh-manager", "config.json"); strings.TrimSpace(out.String()) != want {
+		t.Fatalf("unexpected path: %q", out.String())
+	}
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// DryRunner records and echoes commands instead of running them. Read-only
+// queries (see IsReadOnlyCommand) still go to Runner so callers get the real
+// answers they need to decide what they would do next.
+type DryRunner struct {
+	Runner CommandRunner
+	Out    io.Writer
+
+	mu    sync.Mutex
+	calls []string
+	// shared is the runner that records calls for a WithHost copy.
+	shared *DryRunner
+}
+
+func (r *DryRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if r.Runner != nil && IsReadOnlyCommand(name, args...) {
+		return r.Runner.Run(ctx, name, args...)
+	}
+	line := FormatCommand(name, args...)
+	rec := r.recorder()
+	rec.mu.Lock()
+	rec.calls = append(rec.calls, line)
+	rec.mu.Unlock()
+	if r.Out != nil {
+		fmt.Fprintf(r.Out, "+ %s\n", line)
+	}
+	return nil, nil
+}
+
+// Calls returns the commands recorded so far, in order, including those
+// made through WithHost copies.
+func (r *DryRunner) Calls() []string {
+	rec := r.recorder()
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]string(nil), rec.calls...)
+}
+
+func (r *DryRunner) recorder() *DryRunner {
+	if r.shared != nil {
+		return r.shared
+	}
+	return r
+}
+
+// withHost returns a copy that sends read-only gh calls to host and records
+// into r, leaving r itself untouched.
+func (r *DryRunner) withHost(host string) *DryRunner {
+	return &DryRunner{Runner: WithHost(r.Runner, host), Out: r.Out, shared: r.recorder()}
+}
+
+// IsReadOnlyCommand reports whether a gh/git invocation only reads state.
+func IsReadOnlyCommand(name string, args ...string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch name {
+	case "gh":
+		switch args[0] {
+		case "api":
+			for _, a := range args {
+				if a == "-X" || a == "--method" || strings.HasPrefix(a, "--method=") || a == "-f" || a == "-F" || a == "--field" || a == "--raw-field" {
+					return false
+				}
+			}
+			return true
+		case "auth":
+			return len(args) > 1 && args[1] == "status"
+		case "repo":
+			return len(args) > 1 && (args[1] == "view" || args[1] == "list")
+		}
+	case "git":
+		sub := args
+		if len(sub) > 2 && sub[0] == "-C" {
+			sub = sub[2:]
+		}
+		if len(sub) == 0 {
+			return false
+		}
+		switch sub[0] {
+		case "rev-parse", "symbolic-ref", "ls-remote":
+			return true
+		case "lfs":
+			return len(sub) > 1 && sub[1] == "ls-files"
+		}
+	}
+	return false
+}
+
+// FormatCommand renders a command line, quoting arguments that contain
+// whitespace or quotes so it can be pasted into a shell.
+func FormatCommand(name string, args ...string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, name)
+	for _, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		parts = append(parts, a)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+type answerRunner struct {
+	calls []string
+}
+
+func (a *answerRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	a.calls = append(a.calls, FormatCommand(name, args...))
+	return []byte("alice\n"), nil
+}
+
+func TestDryRunnerEchoesMutationsAndPassesReadsThrough(t *testing.T) {
+	inner := &answerRunner{}
+	var out bytes.Buffer
+	r := &DryRunner{Runner: inner, Out: &out}
+	ctx := context.Background()
+
+	if got, _ := r.Run(ctx, "gh", "api", "user", "--jq", ".login"); string(got) != "alice\n" {
+		t.Fatalf("expected read-only call to pass through, got %q", got)
+	}
+	_, _ = r.Run(ctx, "gh", "repo", "view", "alice/demo", "--json", "name")
+	_, _ = r.Run(ctx, "gh", "repo", "delete", "alice/demo", "--yes")
+	_, _ = r.Run(ctx, "git", "-C", "/tmp/x", "commit", "-m", "backup: 2 repos")
+	_, _ = r.Run(ctx, "gh", "api", "-X", "DELETE", "repos/alice/demo")
+
+	want := []string{
+		"gh repo delete alice/demo --yes",
+		"git -C /tmp/x commit -m 'backup: 2 repos'",
+		"gh api -X DELETE repos/alice/demo",
+	}
+	if !reflect.DeepEqual(r.Calls(), want) {
+		t.Fatalf("unexpected recorded calls: %v", r.Calls())
+	}
+	if len(inner.calls) != 2 {
+		t.Fatalf("expected only reads to reach the inner runner, got %v", inner.calls)
+	}
+	if out.String() != "+ "+want[0]+"\n+ "+want[1]+"\n+ "+want[2]+"\n" {
+		t.Fatalf("unexpected echo output: %q", out.String())
+	}
+}
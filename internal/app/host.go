@@ -0,0 +1,25 @@
+package app
+
+import (
+	"os"
+	"strings"
+)
+
+const DefaultHost = "github.com"
+
+func ResolveHost(host string) string {
+	if h := strings.TrimSpace(host); h != "" {
+		return h
+	}
+	if h := strings.TrimSpace(os.Getenv("GH_HOST")); h != "" {
+		return h
+	}
+	return DefaultHost
+}
+
+func SSHRemote(host, fullName string) string {
+	if host == "" {
+		host = DefaultHost
+	}
+	return "git@" + host + ":" + fullName + ".git"
+}
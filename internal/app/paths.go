@@ -3,15 +3,44 @@ package app
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 )
 
+// ConfigDir returns the directory holding config, secrets and themes:
+// $XDG_CONFIG_HOME/gh-manager on Unix (default ~/.config/gh-manager) and
+// os.UserConfigDir()/gh-manager on Windows and macOS. An existing legacy
+// ~/.config/gh-manager keeps being used until the native dir is created.
 func ConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".config", "gh-manager"), nil
+	native := nativeConfigDir(runtime.GOOS, home, os.Getenv("XDG_CONFIG_HOME"))
+	legacy := filepath.Join(home, ".config", "gh-manager")
+	if native != legacy && !dirExists(native) && dirExists(legacy) {
+		return legacy, nil
+	}
+	return native, nil
+}
+
+func nativeConfigDir(goos, home, xdg string) string {
+	switch goos {
+	case "windows", "darwin":
+		if dir, err := os.UserConfigDir(); err == nil {
+			return filepath.Join(dir, "gh-manager")
+		}
+	default:
+		if filepath.IsAbs(xdg) {
+			return filepath.Join(xdg, "gh-manager")
+		}
+	}
+	return filepath.Join(home, ".config", "gh-manager")
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
 
 func DefaultBackupRoot(now time.Time) (string, error) {
@@ -19,5 +48,9 @@ func DefaultBackupRoot(now time.Time) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, "gh-manager-archive-"+now.Format("2006-01-02-150405")), nil
+	return BackupRootIn(home, now), nil
+}
+
+func BackupRootIn(base string, now time.Time) string {
+	return filepath.Join(base, "gh-manager-archive-"+now.Format("2006-01-02-150405"))
 }
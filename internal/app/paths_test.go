@@ -0,0 +1,69 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestConfigDirHonorsXDGConfigHome(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("XDG_CONFIG_HOME only applies on Unix")
+	}
+	home := t.TempDir()
+	xdg := filepath.Join(t.TempDir(), "xdg")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	got, err := ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(xdg, "gh-manager"); got != want {
+		t.Fatalf("ConfigDir() = %q, want %q", got, want)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "relative/dir")
+	got, err = ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, ".config", "gh-manager"); got != want {
+		t.Fatalf("relative XDG_CONFIG_HOME should be ignored: got %q, want %q", got, want)
+	}
+}
+
+func TestConfigDirKeepsLegacyDirUntilNativeExists(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("XDG_CONFIG_HOME only applies on Unix")
+	}
+	home := t.TempDir()
+	xdg := filepath.Join(t.TempDir(), "xdg")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	legacy := filepath.Join(home, ".config", "gh-manager")
+	if err := os.MkdirAll(legacy, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != legacy {
+		t.Fatalf("expected legacy dir %q, got %q", legacy, got)
+	}
+
+	native := filepath.Join(xdg, "gh-manager")
+	if err := os.MkdirAll(native, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	got, err = ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != native {
+		t.Fatalf("expected native dir %q once it exists, got %q", native, got)
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimitRunner throttles gh invocations to PerMinute calls using a token
+// bucket that holds up to Burst tokens. Other commands (git) are not delayed.
+// It is safe for concurrent use; build it with NewRateLimitRunner.
+type RateLimitRunner struct {
+	Runner    CommandRunner
+	PerMinute int
+	Burst     int
+	Now       func() time.Time
+	Sleep     func(time.Duration)
+
+	mu  sync.Mutex
+	tat time.Time
+	// shared is the runner whose bucket a WithHost copy draws from.
+	shared *RateLimitRunner
+}
+
+// NewRateLimitRunner wraps r with a gh rate limit. A non-positive perMinute
+// disables limiting and returns r unchanged.
+func NewRateLimitRunner(r CommandRunner, perMinute int) CommandRunner {
+	if perMinute <= 0 {
+		return r
+	}
+	return &RateLimitRunner{Runner: r, PerMinute: perMinute, Burst: 1}
+}
+
+func (r *RateLimitRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if name == "gh" && r.PerMinute > 0 {
+		if wait := r.reserve(); wait > 0 {
+			if r.Sleep != nil {
+				r.Sleep(wait)
+			} else if err := SleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return r.Runner.Run(ctx, name, args...)
+}
+
+// withHost returns a copy that sends gh calls to host and shares r's bucket,
+// leaving r itself untouched.
+func (r *RateLimitRunner) withHost(host string) *RateLimitRunner {
+	shared := r
+	if r.shared != nil {
+		shared = r.shared
+	}
+	return &RateLimitRunner{
+		Runner:    WithHost(r.Runner, host),
+		PerMinute: r.PerMinute,
+		Burst:     r.Burst,
+		Now:       r.Now,
+		Sleep:     r.Sleep,
+		shared:    shared,
+	}
+}
+
+// reserve claims the next slot and returns how long the caller must wait for it.
+func (r *RateLimitRunner) reserve() time.Duration {
+	if r.shared != nil {
+		return r.shared.reserve()
+	}
+	now := time.Now
+	if r.Now != nil {
+		now = r.Now
+	}
+	burst := r.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	interval := time.Minute / time.Duration(r.PerMinute)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := now()
+	if r.tat.Before(t) {
+		r.tat = t
+	}
+	wait := r.tat.Sub(t) - time.Duration(burst-1)*interval
+	r.tat = r.tat.Add(interval)
+	return wait
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingRunner struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (c *countingRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, name)
+	return nil, nil
+}
+
+func TestRateLimitRunnerDelaysGhCallsBeyondRate(t *testing.T) {
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var delays []time.Duration
+	inner := &countingRunner{}
+	r := &RateLimitRunner{
+		Runner:    inner,
+		PerMinute: 60,
+		Burst:     2,
+		Now:       func() time.Time { return clock },
+		Sleep: func(d time.Duration) {
+			delays = append(delays, d)
+			clock = clock.Add(d)
+		},
+	}
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		if _, err := r.Run(ctx, "gh", "api", "user"); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		if _, err := r.Run(ctx, "git", "status"); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	}
+	if len(inner.calls) != 8 {
+		t.Fatalf("expected all calls to reach the inner runner, got %d", len(inner.calls))
+	}
+	// The burst of two goes through immediately, then one call per second.
+	if len(delays) != 2 || delays[0] != time.Second || delays[1] != time.Second {
+		t.Fatalf("unexpected delays: %v", delays)
+	}
+}
+
+func TestRateLimitRunnerSerializesConcurrentCalls(t *testing.T) {
+	var mu sync.Mutex
+	var total time.Duration
+	r := &RateLimitRunner{
+		Runner:    &countingRunner{},
+		PerMinute: 600,
+		Now:       func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) },
+		Sleep: func(d time.Duration) {
+			mu.Lock()
+			total += d
+			mu.Unlock()
+		},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = r.Run(context.Background(), "gh", "repo", "list")
+		}()
+	}
+	wg.Wait()
+	// Five calls at a frozen clock wait 0+100+200+300+400ms in some order.
+	if total != time.Second {
+		t.Fatalf("expected concurrent callers to get distinct slots, waited %v", total)
+	}
+}
+
+func TestNewRateLimitRunnerDisabledByDefault(t *testing.T) {
+	inner := &countingRunner{}
+	if got := NewRateLimitRunner(inner, 0); got != CommandRunner(inner) {
+		t.Fatalf("expected zero rate to return the runner unchanged, got %T", got)
+	}
+}
+
+func TestRateLimitRunnerWaitEndsOnCancel(t *testing.T) {
+	inner := &countingRunner{}
+	r := &RateLimitRunner{Runner: inner, PerMinute: 1, Burst: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := r.Run(ctx, "gh", "api", "user"); err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := r.Run(ctx, "gh", "api", "user"); err == nil {
+		t.Fatal("expected the canceled wait to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second || len(inner.calls) != 1 {
+		t.Fatalf("expected cancel to end the wait early: %s, %d calls", elapsed, len(inner.calls))
+	}
+}
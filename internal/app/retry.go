@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// rejectedMarkers are transient errors where the request never took effect,
+// so any command may be retried.
+var rejectedMarkers = []string{
+	"rate limit",
+	"connection refused",
+}
+
+// ambiguousMarkers are transient errors where the request may already have
+// been applied; only idempotent commands are retried on them.
+var ambiguousMarkers = []string{
+	"connection reset",
+	"i/o timeout",
+	"timed out",
+	"http 502",
+	"http 503",
+	"http 504",
+}
+
+// RetryRunner retries transient failures with exponential backoff. Sleep
+// overrides the wait in tests; by default it ends early when ctx is done.
+type RetryRunner struct {
+	Runner      CommandRunner
+	MaxAttempts int
+	BaseDelay   time.Duration
+	Sleep       func(time.Duration)
+}
+
+func (r RetryRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	attempts := r.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	idempotent := isIdempotent(name, args)
+	delay := r.BaseDelay
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		out, err := r.Runner.Run(ctx, name, args...)
+		if err == nil {
+			return out, nil
+		}
+		lastErr = err
+		if attempt == attempts || ctx.Err() != nil {
+			break
+		}
+		if !isRejected(err) && !(idempotent && IsTransient(err)) {
+			break
+		}
+		if r.Sleep != nil {
+			r.Sleep(delay)
+		} else if SleepContext(ctx, delay) != nil {
+			break
+		}
+		delay *= 2
+	}
+	return nil, lastErr
+}
+
+// SleepContext waits for d, returning ctx's error early if it is done first.
+func SleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// isIdempotent reports whether running the command twice has the same effect
+// as running it once. gh commands that create, delete or change repos, and
+// gh api calls with a non-GET method, are not.
+func isIdempotent(name string, args []string) bool {
+	if name != "gh" || len(args) == 0 {
+		return true
+	}
+	switch args[0] {
+	case "repo":
+		if len(args) > 1 {
+			switch args[1] {
+			case "create", "delete", "archive", "unarchive", "rename", "edit", "fork":
+				return false
+			}
+		}
+	case "api":
+		for i, a := range args {
+			method := ""
+			switch {
+			case (a == "-X" || a == "--method") && i+1 < len(args):
+				method = args[i+1]
+			case strings.HasPrefix(a, "--method="):
+				method = strings.TrimPrefix(a, "--method=")
+			}
+			if method != "" && !strings.EqualFold(method, "GET") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isRejected(err error) bool {
+	return hasMarker(err, rejectedMarkers)
+}
+
+func IsTransient(err error) bool {
+	return hasMarker(err, rejectedMarkers) || hasMarker(err, ambiguousMarkers)
+}
+
+func hasMarker(err error, markers []string) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range markers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
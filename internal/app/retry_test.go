@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type flakyRunner struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, f.err
+	}
+	return []byte("ok"), nil
+}
+
+func TestRetryRunnerRetriesTransientErrors(t *testing.T) {
+	inner := &flakyRunner{failures: 2, err: errors.New("HTTP 403: API rate limit exceeded")}
+	var delays []time.Duration
+	r := RetryRunner{Runner: inner, MaxAttempts: 4, BaseDelay: time.Second, Sleep: func(d time.Duration) { delays = append(delays, d) }}
+	out, err := r.Run(context.Background(), "gh", "api", "user")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != "ok" || inner.calls != 3 {
+		t.Fatalf("unexpected result: out=%q calls=%d", out, inner.calls)
+	}
+	if len(delays) != 2 || delays[0] != time.Second || delays[1] != 2*time.Second {
+		t.Fatalf("unexpected backoff: %v", delays)
+	}
+}
+
+func TestRetryRunnerGivesUpAfterMaxAttempts(t *testing.T) {
+	inner := &flakyRunner{failures: 5, err: errors.New("read: connection reset by peer")}
+	r := RetryRunner{Runner: inner, MaxAttempts: 3, Sleep: func(time.Duration) {}}
+	if _, err := r.Run(context.Background(), "git", "clone"); err == nil {
+		t.Fatal("expected error after exhausting attempts")
+	}
+	if inner.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", inner.calls)
+	}
+}
+
+func TestRetryRunnerLeavesPermanentErrorsAlone(t *testing.T) {
+	inner := &flakyRunner{failures: 1, err: errors.New("HTTP 404: Not Found")}
+	r := RetryRunner{Runner: inner, MaxAttempts: 3, Sleep: func(time.Duration) { t.Fatal("unexpected sleep") }}
+	if _, err := r.Run(context.Background(), "gh", "repo", "view"); err == nil {
+		t.Fatal("expected permanent error")
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", inner.calls)
+	}
+}
+
+func TestRetryRunnerRetriesMutatingCommandsOnlyWhenRejected(t *testing.T) {
+	for _, tc := range []struct {
+		args  []string
+		err   string
+		calls int
+	}{
+		{[]string{"repo", "create", "alice/r1", "--private"}, "read: connection timed out", 1},
+		{[]string{"repo", "delete", "alice/r1", "--yes"}, "HTTP 502: Bad Gateway", 1},
+		{[]string{"api", "-X", "DELETE", "repos/alice/r1"}, "i/o timeout", 1},
+		{[]string{"repo", "create", "alice/r1", "--private"}, "HTTP 403: API rate limit exceeded", 2},
+		{[]string{"repo", "view", "alice/r1"}, "read: connection timed out", 2},
+		{[]string{"api", "--method=GET", "repos/alice/r1"}, "HTTP 502: Bad Gateway", 2},
+	} {
+		inner := &flakyRunner{failures: 1, err: errors.New(tc.err)}
+		r := RetryRunner{Runner: inner, MaxAttempts: 3, Sleep: func(time.Duration) {}}
+		_, _ = r.Run(context.Background(), "gh", tc.args...)
+		if inner.calls != tc.calls {
+			t.Errorf("gh %v after %q: %d calls, want %d", tc.args, tc.err, inner.calls, tc.calls)
+		}
+	}
+}
+
+func TestRetryRunnerStopsWaitingWhenCanceled(t *testing.T) {
+	inner := &flakyRunner{failures: 5, err: errors.New("HTTP 503: Service Unavailable")}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	r := RetryRunner{Runner: inner, MaxAttempts: 3, BaseDelay: time.Minute}
+	if _, err := r.Run(ctx, "gh", "api", "user"); err == nil {
+		t.Fatal("expected the last error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second || inner.calls != 1 {
+		t.Fatalf("expected cancel to cut the backoff short: %s, %d calls", elapsed, inner.calls)
+	}
+}
+
+func TestWithHostUnwrapsRetryRunner(t *testing.T) {
+	r := WithHost(RetryRunner{Runner: ExecRunner{}, MaxAttempts: 2}, "ghe.example.com")
+	rr, ok := r.(RetryRunner)
+	if !ok {
+		t.Fatalf("expected RetryRunner, got %T", r)
+	}
+	if er, ok := rr.Runner.(ExecRunner); !ok || er.Host != "ghe.example.com" {
+		t.Fatalf("expected inner ExecRunner host to be set, got %#v", rr.Runner)
+	}
+}
+
+func TestWithHostCopiesRateLimitAndDryRunners(t *testing.T) {
+	inner := ExecRunner{}
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rl := &RateLimitRunner{Runner: inner, PerMinute: 60, Now: func() time.Time { return clock }}
+	got, ok := WithHost(rl, "ghe.example.com").(*RateLimitRunner)
+	if !ok || got == rl {
+		t.Fatalf("expected a new RateLimitRunner, got %#v", got)
+	}
+	if er, ok := got.Runner.(ExecRunner); !ok || er.Host != "ghe.example.com" {
+		t.Fatalf("expected inner ExecRunner host to be set, got %#v", got.Runner)
+	}
+	if rl.Runner != CommandRunner(inner) {
+		t.Fatalf("WithHost must not change the original runner, got %#v", rl.Runner)
+	}
+	if rl.reserve() != 0 || got.reserve() != time.Second {
+		t.Fatal("expected the copy to share the original's bucket")
+	}
+
+	dry := &DryRunner{Runner: inner}
+	copied, ok := WithHost(dry, "ghe.example.com").(*DryRunner)
+	if !ok || copied == dry || dry.Runner != CommandRunner(inner) {
+		t.Fatalf("expected a new DryRunner and the original untouched, got %#v", copied)
+	}
+	_, _ = copied.Run(context.Background(), "gh", "repo", "delete", "alice/demo", "--yes")
+	if calls := dry.Calls(); len(calls) != 1 || calls[0] != "gh repo delete alice/demo --yes" {
+		t.Fatalf("expected the copy to record into the original, got %v", calls)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 )
 
@@ -11,10 +12,15 @@ type CommandRunner interface {
 	Run(ctx context.Context, name string, args ...string) ([]byte, error)
 }
 
-type ExecRunner struct{}
+type ExecRunner struct {
+	Host string
+}
 
-func (ExecRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+func (r ExecRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
 	cmd := exec.CommandContext(ctx, name, args...)
+	if name == "gh" && r.Host != "" {
+		cmd.Env = append(os.Environ(), "GH_HOST="+r.Host)
+	}
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -27,3 +33,43 @@ func (ExecRunner) Run(ctx context.Context, name string, args ...string) ([]byte,
 	}
 	return stdout.Bytes(), nil
 }
+
+// InteractiveRunner runs commands attached to the user's terminal, for
+// prompts such as `gh auth login`. It returns no output.
+type InteractiveRunner struct {
+	Host string
+}
+
+func (r InteractiveRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if name == "gh" && r.Host != "" {
+		cmd.Env = append(os.Environ(), "GH_HOST="+r.Host)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return nil, cmd.Run()
+}
+
+// WithHost points gh invocations made through an ExecRunner at host.
+// Wrapping runners are copied rather than modified, so the runner passed in
+// keeps its host and may be in use concurrently. Other runners (such as test
+// fakes) are returned unchanged.
+func WithHost(r CommandRunner, host string) CommandRunner {
+	switch v := r.(type) {
+	case ExecRunner:
+		v.Host = host
+		return v
+	case InteractiveRunner:
+		v.Host = host
+		return v
+	case RetryRunner:
+		v.Runner = WithHost(v.Runner, host)
+		return v
+	case *DryRunner:
+		return v.withHost(host)
+	case *RateLimitRunner:
+		return v.withHost(host)
+	}
+	return r
+}
@@ -1,15 +1,18 @@
 package backup
 
 import (
+	"compress/gzip"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"gh-manager/internal/app"
@@ -19,10 +22,15 @@ import (
 
 type Service struct {
 	runner app.CommandRunner
+	host   string
+	// Refresh runs `git remote update --prune` on an existing mirror instead of reusing it as-is.
+	Refresh bool
+	// Compress gzips each bundle after it is created, leaving only the .bundle.gz.
+	Compress bool
 }
 
-func NewService(r app.CommandRunner) Service {
-	return Service{runner: r}
+func NewService(r app.CommandRunner, host string) Service {
+	return Service{runner: r, host: host}
 }
 
 func MirrorPath(root string, repo planfile.RepoRecord) string {
@@ -42,12 +50,27 @@ func SnapshotPath(root string, repo planfile.RepoRecord) string {
 	return filepath.Join(root, "snapshots", owner+"__"+name)
 }
 
+func LFSPath(root string, repo planfile.RepoRecord) string {
+	name := strings.ReplaceAll(repo.Name, "/", "_")
+	owner := strings.ReplaceAll(repo.Owner, "/", "_")
+	return filepath.Join(root, "lfs", owner+"__"+name)
+}
+
 func (s Service) MirrorBackup(ctx context.Context, repo planfile.RepoRecord, root string) (string, error) {
+	return s.mirror(ctx, repo, root, s.Refresh)
+}
+
+func (s Service) mirror(ctx context.Context, repo planfile.RepoRecord, root string, refresh bool) (string, error) {
 	dst := MirrorPath(root, repo)
 	if _, err := os.Stat(dst); err == nil {
+		if refresh {
+			if _, err := s.runner.Run(ctx, "git", "-C", dst, "remote", "update", "--prune"); err != nil {
+				return "", err
+			}
+		}
 		return dst, nil
 	}
-	url := "git@github.com:" + repo.FullName + ".git"
+	url := app.SSHRemote(s.host, repo.FullName)
 	_, err := s.runner.Run(ctx, "git", "clone", "--mirror", url, dst)
 	if err != nil {
 		return "", err
@@ -56,7 +79,7 @@ func (s Service) MirrorBackup(ctx context.Context, repo planfile.RepoRecord, roo
 }
 
 func (s Service) CreateBundle(ctx context.Context, repo planfile.RepoRecord, root string) (string, error) {
-	mirror, err := s.MirrorBackup(ctx, repo, root)
+	mirror, err := s.mirror(ctx, repo, root, false)
 	if err != nil {
 		return "", err
 	}
@@ -68,11 +91,92 @@ func (s Service) CreateBundle(ctx context.Context, repo planfile.RepoRecord, roo
 	if err != nil {
 		return "", err
 	}
+	if s.Compress {
+		return CompressBundle(bundle)
+	}
 	return bundle, nil
 }
 
+// CompressBundle gzips path to path+".gz" and removes the original.
+func CompressBundle(path string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	dst := path + ".gz"
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return "", err
+	}
+	in.Close()
+	if err := os.Remove(path); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+// VerifyBundle runs `git bundle verify` against the repo's mirror. Gzipped
+// bundles are expanded to a temporary file first.
+func (s Service) VerifyBundle(ctx context.Context, repo planfile.RepoRecord, root, bundlePath string) error {
+	mirror := MirrorPath(root, repo)
+	if _, err := os.Stat(mirror); err != nil {
+		return fmt.Errorf("mirror missing: %w", err)
+	}
+	path := bundlePath
+	if manifest.BundleCompression(bundlePath) == manifest.CompressionGzip {
+		tmp, err := manifest.DecompressBundle(bundlePath, filepath.Dir(bundlePath))
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp)
+		path = tmp
+	}
+	if _, err := s.runner.Run(ctx, "git", "-C", mirror, "bundle", "verify", path); err != nil {
+		return fmt.Errorf("bundle verify failed: %w", err)
+	}
+	return nil
+}
+
+// FetchLFS downloads every LFS object referenced by the mirror into
+// LFSPath, outside the mirror so pruning it keeps the objects. It reports
+// false without fetching when the repo has no LFS pointers.
+func (s Service) FetchLFS(ctx context.Context, repo planfile.RepoRecord, root string) (string, bool, error) {
+	mirror, err := s.mirror(ctx, repo, root, false)
+	if err != nil {
+		return "", false, err
+	}
+	out, err := s.runner.Run(ctx, "git", "-C", mirror, "lfs", "ls-files", "--all", "--name-only")
+	if err != nil {
+		return "", false, err
+	}
+	if strings.TrimSpace(string(out)) == "" {
+		return "", false, nil
+	}
+	dst := LFSPath(root, repo)
+	if err := os.MkdirAll(dst, 0o700); err != nil {
+		return "", false, err
+	}
+	if _, err := s.runner.Run(ctx, "git", "-C", mirror, "-c", "lfs.storage="+dst, "lfs", "fetch", "--all"); err != nil {
+		return "", false, err
+	}
+	return dst, true, nil
+}
+
 func (s Service) CreateBrowsableSnapshot(ctx context.Context, repo planfile.RepoRecord, root string) (string, error) {
-	mirror, err := s.MirrorBackup(ctx, repo, root)
+	mirror, err := s.mirror(ctx, repo, root, false)
 	if err != nil {
 		return "", err
 	}
@@ -93,28 +197,45 @@ func (s Service) CreateBrowsableSnapshot(ctx context.Context, repo planfile.Repo
 type ArchiveService struct {
 	runner app.CommandRunner
 	now    func() time.Time
+	// sleep replaces the ctx-aware wait between push attempts in tests.
+	sleep func(time.Duration)
+	// PushAttempts and PushDelay control how often the final `git push` is
+	// retried; the delay doubles after each failed attempt.
+	PushAttempts int
+	PushDelay    time.Duration
+	// PrintOnly is set when the runner only echoes mutating commands: bundles
+	// missing on disk are listed without hashing or copying, and a commit sha
+	// that cannot be read does not stop the push from being echoed.
+	PrintOnly bool
 }
 
 func NewArchiveService(r app.CommandRunner) ArchiveService {
-	return ArchiveService{runner: r, now: time.Now}
+	return ArchiveService{runner: r, now: time.Now, PushAttempts: 3, PushDelay: 2 * time.Second}
 }
 
-type archiveManifest struct {
-	PlanFingerprint string                 `json:"planFingerprint"`
-	CreatedAt       string                 `json:"createdAt"`
-	Bundles         []archiveManifestEntry `json:"bundles"`
+// PendingArchivePath is where an archive clone whose commit could not be
+// pushed is kept, so a resumed run can push it instead of recommitting.
+func PendingArchivePath(backupRoot string) string {
+	return filepath.Join(backupRoot, "archive-pending")
 }
 
-type archiveManifestEntry struct {
-	FullName   string `json:"fullName"`
-	BundleFile string `json:"bundleFile"`
-	SHA256     string `json:"sha256"`
-	UpdatedAt  string `json:"updatedAt"`
+const pendingMarker = ".gh-manager-pending.json"
+
+type pendingPublish struct {
+	Branch  string `json:"branch"`
+	Commit  string `json:"commit"`
+	Bundles int    `json:"bundles"`
+	Bytes   int64  `json:"bytes"`
 }
 
-func (a ArchiveService) PublishBundles(ctx context.Context, archiveRepo, branch, backupRoot string, bundles []manifest.BundleArtifact, planFingerprint string) (string, error) {
+// publishCopyWorkers bounds how many bundles are copied into the archive
+// clone at once.
+const publishCopyWorkers = 4
+
+func (a ArchiveService) PublishBundles(ctx context.Context, archiveRepo, branch, backupRoot string, bundles []manifest.BundleArtifact, planFingerprint string) (manifest.PublishResult, error) {
+	var res manifest.PublishResult
 	if len(bundles) == 0 {
-		return "", nil
+		return res, nil
 	}
 	if branch == "" {
 		branch = "main"
@@ -123,76 +244,273 @@ func (a ArchiveService) PublishBundles(ctx context.Context, archiveRepo, branch,
 		a.now = time.Now
 	}
 
-	workdir, err := os.MkdirTemp("", "gh-manager-archive-*")
+	cloneDir := PendingArchivePath(backupRoot)
+	prior, err := a.pushPending(ctx, cloneDir, branch)
 	if err != nil {
-		return "", err
+		return res, err
+	}
+	if err := os.RemoveAll(cloneDir); err != nil {
+		return res, err
 	}
-	defer os.RemoveAll(workdir)
+	keepClone := false
+	defer func() {
+		if !keepClone {
+			_ = os.RemoveAll(cloneDir)
+		}
+	}()
 
-	cloneDir := filepath.Join(workdir, "repo")
 	if _, err := a.runner.Run(ctx, "gh", "repo", "clone", archiveRepo, cloneDir); err != nil {
-		return "", err
+		return res, err
 	}
 	if _, err := a.runner.Run(ctx, "git", "-C", cloneDir, "checkout", "-B", branch); err != nil {
-		return "", err
+		return res, err
 	}
 
-	timestamp := a.now().UTC().Format("2006-01-02-150405")
-	archiveRoot := filepath.Join(cloneDir, "archives", timestamp)
+	archiveRoot := filepath.Join(cloneDir, "archives", archiveRunDir(planFingerprint, a.now()))
 	bundlesDir := filepath.Join(archiveRoot, "bundles")
 	if err := os.MkdirAll(bundlesDir, 0o755); err != nil {
-		return "", err
+		return res, err
+	}
+	manPath := filepath.Join(archiveRoot, "manifest.json")
+	man, err := manifest.ReadArchive(manPath)
+	if err != nil {
+		return res, err
+	}
+	if man.CreatedAt == "" {
+		man.CreatedAt = a.now().UTC().Format(time.RFC3339)
 	}
+	man.PlanFingerprint = planFingerprint
+	published := publishedSHAs(man)
 
-	entries := make([]archiveManifestEntry, 0, len(bundles))
 	sort.Slice(bundles, func(i, j int) bool { return bundles[i].FullName < bundles[j].FullName })
-	for _, b := range bundles {
-		dst := filepath.Join(bundlesDir, filepath.Base(b.BundlePath))
-		content, readErr := os.ReadFile(b.BundlePath)
-		if readErr != nil {
-			return "", readErr
+	entries := make([]manifest.ArchiveBundle, len(bundles))
+	sizes := make([]int64, len(bundles))
+	errs := make([]error, len(bundles))
+	sem := make(chan struct{}, publishCopyWorkers)
+	var wg sync.WaitGroup
+	for i, b := range bundles {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, b manifest.BundleArtifact) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if a.PrintOnly {
+				if _, err := os.Stat(b.BundlePath); os.IsNotExist(err) {
+					entries[i] = manifest.ArchiveBundle{
+						FullName:   b.FullName,
+						BundleFile: filepath.ToSlash(filepath.Join("bundles", filepath.Base(b.BundlePath))),
+						SHA256:     "print-only",
+					}
+					return
+				}
+			}
+			// Hash while copying so each bundle is read once. The copy lands
+			// next to dst and is dropped when this repo's entry in the run dir
+			// already has that content.
+			dst := filepath.Join(bundlesDir, filepath.Base(b.BundlePath))
+			sum, n, err := copyWithSHA256(b.BundlePath, dst+".partial")
+			if err == nil && published[b.FullName] == sum {
+				errs[i] = os.Remove(dst + ".partial")
+				return
+			}
+			if err == nil {
+				err = os.Rename(dst+".partial", dst)
+			}
+			if err != nil {
+				_ = os.Remove(dst + ".partial")
+				errs[i] = err
+				return
+			}
+			sizes[i] = n
+			entries[i] = manifest.ArchiveBundle{
+				FullName:    b.FullName,
+				BundleFile:  filepath.ToSlash(filepath.Join("bundles", filepath.Base(b.BundlePath))),
+				SHA256:      sum,
+				UpdatedAt:   b.UpdatedAt,
+				Compression: manifest.BundleCompression(b.BundlePath),
+				Visibility:  b.Visibility,
+			}
+		}(i, b)
+	}
+	wg.Wait()
+	for i := range bundles {
+		if errs[i] != nil {
+			return res, errs[i]
 		}
-		if writeErr := os.WriteFile(dst, content, 0o644); writeErr != nil {
-			return "", writeErr
+		if entries[i].SHA256 == "" {
+			res.Skipped++
+			continue
 		}
-		h := sha256.Sum256(content)
-		entries = append(entries, archiveManifestEntry{
-			FullName:   b.FullName,
-			BundleFile: filepath.ToSlash(filepath.Join("bundles", filepath.Base(b.BundlePath))),
-			SHA256:     hex.EncodeToString(h[:]),
-			UpdatedAt:  b.UpdatedAt,
-		})
+		res.Bundles++
+		res.Bytes += sizes[i]
+		man.Bundles = upsertArchiveEntry(man.Bundles, entries[i])
 	}
 
-	man := archiveManifest{
-		PlanFingerprint: planFingerprint,
-		CreatedAt:       a.now().UTC().Format(time.RFC3339),
-		Bundles:         entries,
+	if res.Bundles == 0 {
+		// Everything is already in the archive (e.g. a resumed run whose push
+		// landed); report the current head instead of an empty commit.
+		sha, err := a.runner.Run(ctx, "git", "-C", cloneDir, "rev-parse", "HEAD")
+		if err != nil {
+			return res, err
+		}
+		res.Commit = strings.TrimSpace(string(sha))
+		return withPrior(res, prior), nil
 	}
+
 	manBytes, err := json.MarshalIndent(man, "", "  ")
 	if err != nil {
-		return "", err
+		return res, &manifest.CommitError{Err: err}
 	}
 	manBytes = append(manBytes, '\n')
-	if err := os.WriteFile(filepath.Join(archiveRoot, "manifest.json"), manBytes, 0o644); err != nil {
-		return "", err
+	if err := os.WriteFile(manPath, manBytes, 0o644); err != nil {
+		return res, &manifest.CommitError{Err: err}
 	}
 
 	if _, err := a.runner.Run(ctx, "git", "-C", cloneDir, "add", "."); err != nil {
-		return "", err
+		return res, &manifest.CommitError{Err: err}
 	}
-	msg := fmt.Sprintf("backup: %d repos from plan %s", len(entries), shortFingerprint(planFingerprint))
+	msg := fmt.Sprintf("backup: %d repos from plan %s", res.Bundles, shortFingerprint(planFingerprint))
 	if _, err := a.runner.Run(ctx, "git", "-C", cloneDir, "commit", "-m", msg); err != nil {
-		return "", err
-	}
-	if _, err := a.runner.Run(ctx, "git", "-C", cloneDir, "push", "origin", branch); err != nil {
-		return "", err
+		return res, &manifest.CommitError{Err: err}
 	}
 	sha, err := a.runner.Run(ctx, "git", "-C", cloneDir, "rev-parse", "HEAD")
+	if err != nil && !a.PrintOnly {
+		return res, &manifest.CommitError{Err: err}
+	}
+	res.Commit = strings.TrimSpace(string(sha))
+	if err := a.push(ctx, cloneDir, branch); err != nil {
+		marker := pendingPublish{Branch: branch, Commit: res.Commit, Bundles: res.Bundles, Bytes: res.Bytes}
+		if b, merr := json.Marshal(marker); merr == nil && os.WriteFile(filepath.Join(cloneDir, pendingMarker), b, 0o600) == nil {
+			keepClone = true
+		}
+		return res, &manifest.PushError{Commit: res.Commit, Err: err}
+	}
+	return withPrior(res, prior), nil
+}
+
+// pushPending pushes a commit left behind by an earlier failed push and
+// reports what it carried. It returns a zero result when nothing is pending.
+func (a ArchiveService) pushPending(ctx context.Context, cloneDir, branch string) (manifest.PublishResult, error) {
+	b, err := os.ReadFile(filepath.Join(cloneDir, pendingMarker))
 	if err != nil {
-		return "", err
+		if os.IsNotExist(err) {
+			return manifest.PublishResult{}, nil
+		}
+		return manifest.PublishResult{}, err
+	}
+	var p pendingPublish
+	if err := json.Unmarshal(b, &p); err != nil || p.Branch != branch {
+		// Unreadable or for another branch: drop it and publish from scratch.
+		return manifest.PublishResult{}, nil
+	}
+	// Remove the marker first so the pushed tree matches the original commit.
+	if err := os.Remove(filepath.Join(cloneDir, pendingMarker)); err != nil {
+		return manifest.PublishResult{}, err
+	}
+	if err := a.push(ctx, cloneDir, branch); err != nil {
+		_ = os.WriteFile(filepath.Join(cloneDir, pendingMarker), b, 0o600)
+		return manifest.PublishResult{}, &manifest.PushError{Commit: p.Commit, Err: err}
+	}
+	return manifest.PublishResult{Commit: p.Commit, Bundles: p.Bundles, Bytes: p.Bytes}, nil
+}
+
+// push runs `git push`, retrying with exponential backoff.
+func (a ArchiveService) push(ctx context.Context, cloneDir, branch string) error {
+	attempts := a.PushAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := a.PushDelay
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if _, err = a.runner.Run(ctx, "git", "-C", cloneDir, "push", "origin", branch); err == nil {
+			return nil
+		}
+		if attempt == attempts || ctx.Err() != nil {
+			break
+		}
+		if a.sleep != nil {
+			a.sleep(delay)
+		} else if app.SleepContext(ctx, delay) != nil {
+			break
+		}
+		delay *= 2
+	}
+	return err
+}
+
+// withPrior folds a pending commit pushed at the start of a publish into the
+// result. Its bundles were counted as already archived, so they are moved
+// from Skipped to Bundles.
+func withPrior(res, prior manifest.PublishResult) manifest.PublishResult {
+	if prior.Commit == "" {
+		return res
+	}
+	res.Bundles += prior.Bundles
+	res.Bytes += prior.Bytes
+	res.Skipped -= prior.Bundles
+	if res.Skipped < 0 {
+		res.Skipped = 0
+	}
+	return res
+}
+
+// archiveRunDir names the archive folder for a publish. Keying it off the
+// plan fingerprint lets a resumed run add to the same folder.
+func archiveRunDir(planFingerprint string, now time.Time) string {
+	if planFingerprint == "" {
+		return now.UTC().Format("2006-01-02-150405")
+	}
+	return "plan-" + shortFingerprint(planFingerprint)
+}
+
+// publishedSHAs maps each repo already listed in a run dir's manifest to its
+// bundle hash. Matching on the repo as well as the hash keeps a repo whose
+// content equals another one (an unmodified fork, or a copy published under
+// another plan) listed under its own name.
+func publishedSHAs(man manifest.ArchiveManifest) map[string]string {
+	out := make(map[string]string, len(man.Bundles))
+	for _, e := range man.Bundles {
+		if e.SHA256 != "" {
+			out[e.FullName] = e.SHA256
+		}
+	}
+	return out
+}
+
+func upsertArchiveEntry(entries []manifest.ArchiveBundle, e manifest.ArchiveBundle) []manifest.ArchiveBundle {
+	for i := range entries {
+		if entries[i].FullName == e.FullName {
+			entries[i] = e
+			return entries
+		}
+	}
+	entries = append(entries, e)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].FullName < entries[j].FullName })
+	return entries
+}
+
+// copyWithSHA256 streams src to dst, hashing as it copies, and returns the
+// hex digest and byte count.
+func copyWithSHA256(src, dst string) (string, int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", 0, err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return "", 0, err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, h), in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", 0, err
 	}
-	return strings.TrimSpace(string(sha)), nil
+	return hex.EncodeToString(h.Sum(nil)), n, nil
 }
 
 func shortFingerprint(fp string) string {
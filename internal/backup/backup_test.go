@@ -1,9 +1,20 @@
 package backup
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"gh-manager/internal/manifest"
 	"gh-manager/internal/planfile"
 )
 
@@ -24,3 +35,433 @@ func TestSnapshotPath(t *testing.T) {
 		t.Fatalf("snapshot path mismatch: got=%s want=%s", got, want)
 	}
 }
+
+type recordingRunner struct {
+	calls []string
+}
+
+func (r *recordingRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	r.calls = append(r.calls, name+" "+strings.Join(args, " "))
+	return nil, nil
+}
+
+func TestMirrorBackupUsesHost(t *testing.T) {
+	r := &recordingRunner{}
+	root := t.TempDir()
+	repo := planfile.RepoRecord{Owner: "alice", Name: "demo", FullName: "alice/demo"}
+	if _, err := NewService(r, "ghe.example.com").MirrorBackup(context.Background(), repo, root); err != nil {
+		t.Fatal(err)
+	}
+	want := "git clone --mirror git@ghe.example.com:alice/demo.git " + MirrorPath(root, repo)
+	if len(r.calls) != 1 || r.calls[0] != want {
+		t.Fatalf("unexpected calls: %v", r.calls)
+	}
+}
+
+func TestCompressBundleRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alice__demo.bundle")
+	content := bytes.Repeat([]byte("# v2 git bundle\nPACK"), 500)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gz, err := CompressBundle(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gz != path+".gz" || manifest.BundleCompression(gz) != manifest.CompressionGzip {
+		t.Fatalf("unexpected compressed path %q", gz)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected original bundle removed, stat err=%v", err)
+	}
+	f, err := os.Open(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if _, err := got.ReadFrom(zr); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), content) {
+		t.Fatal("decompressed bundle differs from original")
+	}
+}
+
+type lfsRunner struct {
+	recordingRunner
+	lfsFiles string
+}
+
+func (r *lfsRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	_, _ = r.recordingRunner.Run(ctx, name, args...)
+	if strings.Contains(strings.Join(args, " "), "lfs ls-files") {
+		return []byte(r.lfsFiles), nil
+	}
+	return nil, nil
+}
+
+func TestFetchLFSSkipsReposWithoutLFS(t *testing.T) {
+	root := t.TempDir()
+	repo := planfile.RepoRecord{Owner: "alice", Name: "demo", FullName: "alice/demo"}
+	mirror := MirrorPath(root, repo)
+	if err := os.MkdirAll(mirror, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &lfsRunner{}
+	path, used, err := NewService(r, "github.com").FetchLFS(context.Background(), repo, root)
+	if err != nil || used || path != "" {
+		t.Fatalf("expected no LFS, got path=%q used=%v err=%v", path, used, err)
+	}
+	if len(r.calls) != 1 {
+		t.Fatalf("expected only ls-files, got %v", r.calls)
+	}
+
+	r = &lfsRunner{lfsFiles: "assets/big.bin\n"}
+	path, used, err = NewService(r, "github.com").FetchLFS(context.Background(), repo, root)
+	if err != nil || !used || path != LFSPath(root, repo) {
+		t.Fatalf("expected LFS fetch, got path=%q used=%v err=%v", path, used, err)
+	}
+	want := "git -C " + mirror + " -c lfs.storage=" + LFSPath(root, repo) + " lfs fetch --all"
+	if len(r.calls) != 2 || r.calls[1] != want {
+		t.Fatalf("unexpected calls: %v", r.calls)
+	}
+}
+
+func TestMirrorBackupRefreshesExistingMirror(t *testing.T) {
+	root := t.TempDir()
+	repo := planfile.RepoRecord{Owner: "alice", Name: "demo", FullName: "alice/demo"}
+	mirror := MirrorPath(root, repo)
+	if err := os.MkdirAll(mirror, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recordingRunner{}
+	if _, err := NewService(r, "github.com").MirrorBackup(context.Background(), repo, root); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.calls) != 0 {
+		t.Fatalf("expected existing mirror to be reused without refresh, got %v", r.calls)
+	}
+
+	svc := NewService(r, "github.com")
+	svc.Refresh = true
+	if _, err := svc.MirrorBackup(context.Background(), repo, root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateBundle(context.Background(), repo, root); err != nil {
+		t.Fatal(err)
+	}
+	want := "git -C " + mirror + " remote update --prune"
+	if len(r.calls) != 2 || r.calls[0] != want || !strings.Contains(r.calls[1], "bundle create") {
+		t.Fatalf("expected a single remote update before bundling, got %v", r.calls)
+	}
+}
+
+type publishRunner struct {
+	recordingRunner
+	manifest []byte
+}
+
+func (r *publishRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	_, _ = r.recordingRunner.Run(ctx, name, args...)
+	if len(args) == 4 && args[2] == "add" {
+		matches, _ := filepath.Glob(filepath.Join(args[1], "archives", "*", "manifest.json"))
+		if len(matches) == 1 {
+			r.manifest, _ = os.ReadFile(matches[0])
+		}
+	}
+	if len(args) > 0 && args[len(args)-1] == "HEAD" {
+		return []byte("cafef00d\n"), nil
+	}
+	return nil, nil
+}
+
+func TestPublishBundlesStreamsAndSummarizes(t *testing.T) {
+	src := t.TempDir()
+	var bundles []manifest.BundleArtifact
+	var total int64
+	for i, name := range []string{"zeta", "alpha", "mid", "beta", "gamma"} {
+		path := filepath.Join(src, "alice__"+name+".bundle")
+		content := strings.Repeat(name, 100*(i+1))
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		total += int64(len(content))
+		bundles = append(bundles, manifest.BundleArtifact{FullName: "alice/" + name, BundlePath: path})
+	}
+
+	r := &publishRunner{}
+	svc := NewArchiveService(r)
+	svc.now = func() time.Time { return time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC) }
+	res, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles, "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Commit != "cafef00d" || res.Bundles != 5 || res.Bytes != total {
+		t.Fatalf("unexpected result: %+v (want bytes %d)", res, total)
+	}
+	var man manifest.ArchiveManifest
+	if err := json.Unmarshal(r.manifest, &man); err != nil {
+		t.Fatalf("parse archive manifest: %v", err)
+	}
+	if len(man.Bundles) != 5 || man.Bundles[0].FullName != "alice/alpha" || man.Bundles[4].FullName != "alice/zeta" {
+		t.Fatalf("expected bundles sorted by name: %+v", man.Bundles)
+	}
+	content, _ := os.ReadFile(filepath.Join(src, "alice__alpha.bundle"))
+	sum := sha256.Sum256(content)
+	if man.Bundles[0].SHA256 != hex.EncodeToString(sum[:]) || man.Bundles[0].BundleFile != "bundles/alice__alpha.bundle" {
+		t.Fatalf("unexpected manifest entry: %+v", man.Bundles[0])
+	}
+}
+
+// echoRunner records calls and, like a print-only runner whose clone never ran,
+// fails git rev-parse.
+type echoRunner struct {
+	recordingRunner
+}
+
+func (r *echoRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	_, _ = r.recordingRunner.Run(ctx, name, args...)
+	if len(args) > 0 && args[len(args)-1] == "HEAD" {
+		return nil, errors.New("fatal: not a git repository")
+	}
+	return nil, nil
+}
+
+func TestPublishBundlesPrintOnlyEchoesPushForMissingBundles(t *testing.T) {
+	src := t.TempDir()
+	bundles := []manifest.BundleArtifact{
+		{FullName: "alice/demo", BundlePath: filepath.Join(src, "alice__demo.bundle")},
+	}
+	r := &echoRunner{}
+	svc := NewArchiveService(r)
+	svc.PrintOnly = true
+	res, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles, "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Bundles != 1 {
+		t.Fatalf("expected the missing bundle to be listed: %+v", res)
+	}
+	var pushed bool
+	for _, call := range r.calls {
+		pushed = pushed || strings.Contains(call, " push ")
+	}
+	if !pushed {
+		t.Fatalf("expected push to be echoed: %v", r.calls)
+	}
+}
+
+// remoteArchiveRunner keeps the archive repo in a directory: clone copies it
+// into the work tree and push copies the work tree back.
+type remoteArchiveRunner struct {
+	recordingRunner
+	remote    string
+	commits   int
+	pushes    int
+	pushFails int
+}
+
+func (r *remoteArchiveRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	_, _ = r.recordingRunner.Run(ctx, name, args...)
+	switch {
+	case name == "gh" && args[1] == "clone":
+		return nil, copyTree(r.remote, args[3])
+	case len(args) > 2 && args[2] == "commit":
+		r.commits++
+	case len(args) > 2 && args[2] == "push":
+		r.pushes++
+		if r.pushFails > 0 {
+			r.pushFails--
+			return nil, errors.New("connection reset by peer")
+		}
+		return nil, copyTree(args[1], r.remote)
+	}
+	return []byte("head\n"), nil
+}
+
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, b, 0o644)
+	})
+}
+
+func TestPublishBundlesTwiceDoesNotDuplicate(t *testing.T) {
+	src := t.TempDir()
+	var bundles []manifest.BundleArtifact
+	for _, name := range []string{"a", "b"} {
+		path := filepath.Join(src, "alice__"+name+".bundle")
+		if err := os.WriteFile(path, []byte("bundle "+name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		bundles = append(bundles, manifest.BundleArtifact{FullName: "alice/" + name, BundlePath: path})
+	}
+	r := &remoteArchiveRunner{remote: t.TempDir()}
+	clock := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	svc := NewArchiveService(r)
+	svc.now = func() time.Time { clock = clock.Add(time.Hour); return clock }
+
+	first, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles[:1], "fingerprint-123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles, "fingerprint-123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Bundles != 1 || second.Bundles != 1 || second.Skipped != 1 {
+		t.Fatalf("expected resume to publish only the new bundle: first=%+v second=%+v", first, second)
+	}
+	third, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles, "fingerprint-123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.Bundles != 0 || third.Skipped != 2 || r.commits != 2 {
+		t.Fatalf("expected no new commit when everything is archived: third=%+v commits=%d", third, r.commits)
+	}
+
+	dirs, _ := os.ReadDir(filepath.Join(r.remote, "archives"))
+	if len(dirs) != 1 || dirs[0].Name() != "plan-fingerprin" {
+		t.Fatalf("expected one stable run dir, got %v", dirs)
+	}
+	published, _ := os.ReadDir(filepath.Join(r.remote, "archives", dirs[0].Name(), "bundles"))
+	if len(published) != 2 || published[0].Name() != "alice__a.bundle" || published[1].Name() != "alice__b.bundle" {
+		t.Fatalf("expected both bundles to stay published and no partial copies, got %v", published)
+	}
+	b, err := os.ReadFile(filepath.Join(r.remote, "archives", dirs[0].Name(), "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var man manifest.ArchiveManifest
+	if err := json.Unmarshal(b, &man); err != nil {
+		t.Fatal(err)
+	}
+	if len(man.Bundles) != 2 || man.Bundles[0].FullName != "alice/a" || man.Bundles[1].FullName != "alice/b" {
+		t.Fatalf("expected each bundle listed once: %+v", man.Bundles)
+	}
+}
+
+func TestPublishBundlesListsReposSharingContent(t *testing.T) {
+	src := t.TempDir()
+	var bundles []manifest.BundleArtifact
+	for _, name := range []string{"demo", "demo-fork"} {
+		path := filepath.Join(src, "alice__"+name+".bundle")
+		if err := os.WriteFile(path, []byte("same bundle"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		bundles = append(bundles, manifest.BundleArtifact{FullName: "alice/" + name, BundlePath: path})
+	}
+	r := &remoteArchiveRunner{remote: t.TempDir()}
+	svc := NewArchiveService(r)
+
+	if _, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles[:1], "first-plan-fp"); err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles, "second-plan-fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Bundles != 2 || second.Skipped != 0 {
+		t.Fatalf("expected both repos published under the new plan: %+v", second)
+	}
+	b, err := os.ReadFile(filepath.Join(r.remote, "archives", "plan-second-pla", "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var man manifest.ArchiveManifest
+	if err := json.Unmarshal(b, &man); err != nil {
+		t.Fatal(err)
+	}
+	if len(man.Bundles) != 2 || man.Bundles[0].FullName != "alice/demo" || man.Bundles[1].FullName != "alice/demo-fork" || man.Bundles[0].SHA256 != man.Bundles[1].SHA256 {
+		t.Fatalf("expected each repo listed under its own name: %+v", man.Bundles)
+	}
+	for _, e := range man.Bundles {
+		if _, err := os.Stat(filepath.Join(r.remote, "archives", "plan-second-pla", filepath.FromSlash(e.BundleFile))); err != nil {
+			t.Fatalf("bundle for %s missing: %v", e.FullName, err)
+		}
+	}
+	again, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles, "second-plan-fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Bundles != 0 || again.Skipped != 2 {
+		t.Fatalf("expected a resumed publish to skip both repos: %+v", again)
+	}
+}
+
+func TestPublishBundlesRetriesFailedPush(t *testing.T) {
+	src := t.TempDir()
+	path := filepath.Join(src, "alice__a.bundle")
+	if err := os.WriteFile(path, []byte("bundle a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bundles := []manifest.BundleArtifact{{FullName: "alice/a", BundlePath: path}}
+	r := &remoteArchiveRunner{remote: t.TempDir(), pushFails: 1}
+	var slept []time.Duration
+	svc := NewArchiveService(r)
+	svc.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	res, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles, "fingerprint-123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Bundles != 1 || r.commits != 1 || r.pushes != 2 || len(slept) != 1 {
+		t.Fatalf("expected one commit pushed on the second try: res=%+v commits=%d pushes=%d slept=%v", res, r.commits, r.pushes, slept)
+	}
+	if _, err := os.Stat(PendingArchivePath(src)); !os.IsNotExist(err) {
+		t.Fatalf("expected clone removed after push, stat err=%v", err)
+	}
+}
+
+func TestPublishBundlesResumePushesPendingCommit(t *testing.T) {
+	src := t.TempDir()
+	path := filepath.Join(src, "alice__a.bundle")
+	if err := os.WriteFile(path, []byte("bundle a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bundles := []manifest.BundleArtifact{{FullName: "alice/a", BundlePath: path}}
+	r := &remoteArchiveRunner{remote: t.TempDir(), pushFails: 3}
+	svc := NewArchiveService(r)
+	svc.sleep = func(time.Duration) {}
+
+	_, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles, "fingerprint-123456")
+	var pushErr *manifest.PushError
+	if !errors.As(err, &pushErr) || pushErr.Commit != "head" {
+		t.Fatalf("expected push error carrying the commit, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(PendingArchivePath(src), pendingMarker)); err != nil {
+		t.Fatalf("expected pending clone kept: %v", err)
+	}
+
+	res, err := svc.PublishBundles(context.Background(), "alice/archive", "", src, bundles, "fingerprint-123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.commits != 1 {
+		t.Fatalf("expected resume to push without recommitting, commits=%d", r.commits)
+	}
+	if res.Bundles != 1 || res.Skipped != 0 || res.Commit != "head" {
+		t.Fatalf("expected resume to report the pushed bundle: %+v", res)
+	}
+	if _, err := os.Stat(filepath.Join(r.remote, "archives", "plan-fingerprin", "bundles", "alice__a.bundle")); err != nil {
+		t.Fatalf("expected bundle in remote after resume: %v", err)
+	}
+}
@@ -5,21 +5,95 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gh-manager/internal/app"
 )
 
 const CurrentVersion = 1
 
+// defaultUpdateCheckHours is used when cache.update_check_hours is unset.
+const defaultUpdateCheckHours = 24
+
 type Config struct {
-	Version int         `json:"version"`
-	Theme   ThemeConfig `json:"theme"`
+	Version int           `json:"version"`
+	Theme   ThemeConfig   `json:"theme"`
+	Retry   RetryConfig   `json:"retry"`
+	Backup  BackupConfig  `json:"backup"`
+	Archive ArchiveConfig `json:"archive"`
+	// RateLimit throttles gh calls; zero disables it.
+	RateLimit RateLimitConfig `json:"rate_limit"`
+	Cache     CacheConfig     `json:"cache"`
+	Update    UpdateConfig    `json:"update"`
+	// ProtectedRepos lists owner/name patterns (globs or /regex/ allowed)
+	// that plans refuse to include and execute never deletes.
+	ProtectedRepos []string `json:"protected_repos,omitempty"`
+	// MaxPlanSize caps how many repos a plan may hold without --allow-large;
+	// 0 turns the check off.
+	MaxPlanSize int `json:"max_plan_size,omitempty"`
+	// Keybindings overrides TUI keys by action name; see DefaultKeybindings.
+	Keybindings map[string]string `json:"keybindings,omitempty"`
 }
 
 type ThemeConfig struct {
-	Active          string `json:"active"`
-	IndexURL        string `json:"index_url"`
-	AutoUpdateIndex bool   `json:"auto_update_index"`
+	Active   string `json:"active"`
+	IndexURL string `json:"index_url"`
+	// IndexURLs, when set, replaces IndexURL; later entries win on duplicate theme ids.
+	IndexURLs       []string `json:"index_urls,omitempty"`
+	AutoUpdateIndex bool     `json:"auto_update_index"`
+	// AutoScheme picks Light or Dark by the terminal background instead of Active.
+	AutoScheme bool   `json:"auto_scheme,omitempty"`
+	Light      string `json:"light,omitempty"`
+	Dark       string `json:"dark,omitempty"`
+}
+
+type RetryConfig struct {
+	Enabled     bool `json:"enabled"`
+	MaxAttempts int  `json:"max_attempts"`
+	BaseDelayMS int  `json:"base_delay_ms"`
+}
+
+type RateLimitConfig struct {
+	GHRequestsPerMinute int `json:"gh_requests_per_minute"`
+}
+
+type CacheConfig struct {
+	// ReposTTLMinutes keeps repo listings on disk for reuse; zero disables it.
+	ReposTTLMinutes int `json:"repos_ttl_minutes"`
+	// UpdateCheckHours reuses the last TUI update check for this long; unset
+	// means 24 and zero checks on every launch.
+	UpdateCheckHours *int `json:"update_check_hours,omitempty"`
+}
+
+// UpdateCheckTTL is how long a saved TUI update check is reused.
+func (c CacheConfig) UpdateCheckTTL() time.Duration {
+	if c.UpdateCheckHours == nil {
+		return defaultUpdateCheckHours * time.Hour
+	}
+	return time.Duration(*c.UpdateCheckHours) * time.Hour
+}
+
+type UpdateConfig struct {
+	// Enabled turns the TUI release check off when false; unset means on.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// CheckEnabled reports whether the TUI should check for new releases.
+func (u UpdateConfig) CheckEnabled() bool {
+	return u.Enabled == nil || *u.Enabled
+}
+
+type BackupConfig struct {
+	DefaultDir string `json:"default_dir,omitempty"`
+	// ScanArchives marks repos found in local archives when the TUI starts.
+	ScanArchives bool `json:"scan_archives,omitempty"`
+}
+
+type ArchiveConfig struct {
+	// DefaultRepo is the owner/name archive repo used when --archive-repo is
+	// not passed; empty means <actor>/gh-manager-archive.
+	DefaultRepo string `json:"default_repo,omitempty"`
 }
 
 func Default() Config {
@@ -30,9 +104,27 @@ func Default() Config {
 			IndexURL:        "https://raw.githubusercontent.com/pabumake/gh-manager/main/themes/index.json",
 			AutoUpdateIndex: true,
 		},
+		Retry: RetryConfig{
+			MaxAttempts: 3,
+			BaseDelayMS: 1000,
+		},
 	}
 }
 
+// IndexSources returns the theme index URLs to merge, in priority order.
+func (t ThemeConfig) IndexSources() []string {
+	out := make([]string, 0, len(t.IndexURLs))
+	for _, u := range t.IndexURLs {
+		if u = strings.TrimSpace(u); u != "" {
+			out = append(out, u)
+		}
+	}
+	if len(out) == 0 && t.IndexURL != "" {
+		out = append(out, t.IndexURL)
+	}
+	return out
+}
+
 func EnsureDefaults(cfg *Config) {
 	if cfg.Version <= 0 {
 		cfg.Version = CurrentVersion
@@ -43,6 +135,16 @@ func EnsureDefaults(cfg *Config) {
 	if cfg.Theme.IndexURL == "" {
 		cfg.Theme.IndexURL = Default().Theme.IndexURL
 	}
+	if cfg.Retry.MaxAttempts <= 0 {
+		cfg.Retry.MaxAttempts = Default().Retry.MaxAttempts
+	}
+	if cfg.Retry.BaseDelayMS <= 0 {
+		cfg.Retry.BaseDelayMS = Default().Retry.BaseDelayMS
+	}
+	if cfg.Cache.UpdateCheckHours == nil {
+		hours := defaultUpdateCheckHours
+		cfg.Cache.UpdateCheckHours = &hours
+	}
 }
 
 func Dir() (string, error) {
@@ -3,12 +3,15 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadCreatesDefaultConfig(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
 
 	cfg, err := Load()
 	if err != nil {
@@ -26,6 +29,7 @@ func TestLoadCreatesDefaultConfig(t *testing.T) {
 func TestSaveAndLoadRoundTrip(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
 
 	cfg := Default()
 	cfg.Theme.Active = "catppuccin-mocha"
@@ -40,3 +44,186 @@ func TestSaveAndLoadRoundTrip(t *testing.T) {
 		t.Fatalf("active theme mismatch: %q", loaded.Theme.Active)
 	}
 }
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Retry.Enabled {
+		t.Fatal("expected retries to be opt-in")
+	}
+	if cfg.Retry.MaxAttempts != 3 || cfg.Retry.BaseDelayMS != 1000 {
+		t.Fatalf("unexpected retry defaults: %+v", cfg.Retry)
+	}
+}
+
+func TestSelectionRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	got, err := LoadSelection()
+	if err != nil || got != nil {
+		t.Fatalf("expected empty selection before save: %v %v", got, err)
+	}
+	if err := SaveSelection([]string{"alice/b", "alice/a"}, time.Now()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err = LoadSelection()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != 2 || got[0] != "alice/a" || got[1] != "alice/b" {
+		t.Fatalf("unexpected selection: %v", got)
+	}
+}
+
+func TestUpdateCheckRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	if _, ok, err := LoadUpdateCheck(); err != nil || ok {
+		t.Fatalf("expected no saved check: ok=%t err=%v", ok, err)
+	}
+	want := UpdateCheck{CheckedAt: "2026-10-16T08:00:00Z", CurrentVersion: "v0.1.1", LatestVersion: "v0.1.2", ReleaseURL: "https://example.test/r"}
+	if err := SaveUpdateCheck(want); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, ok, err := LoadUpdateCheck()
+	if err != nil || !ok || got != want {
+		t.Fatalf("unexpected load: %+v ok=%t err=%v", got, ok, err)
+	}
+}
+
+func TestUpdateCheckHoursDefaultsAndKeepsZero(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A config written before cache.update_check_hours existed.
+	if err := os.WriteFile(path, []byte(`{"version":1,"cache":{"repos_ttl_minutes":5}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Cache.UpdateCheckTTL() != 24*time.Hour || cfg.Cache.UpdateCheckHours == nil || *cfg.Cache.UpdateCheckHours != 24 {
+		t.Fatalf("expected the 24h default to be backfilled, got %v", cfg.Cache.UpdateCheckTTL())
+	}
+	if err := Set(&cfg, "cache.update_check_hours", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err = Load(); err != nil || cfg.Cache.UpdateCheckTTL() != 0 {
+		t.Fatalf("expected 0 to survive a reload: %v %v", cfg.Cache.UpdateCheckTTL(), err)
+	}
+}
+
+func TestGetSetKeys(t *testing.T) {
+	cfg := Default()
+	if err := Set(&cfg, "theme.auto_update_index", "false"); err != nil {
+		t.Fatalf("set bool: %v", err)
+	}
+	if v, _ := Get(cfg, "theme.auto_update_index"); v != "false" {
+		t.Fatalf("unexpected value: %s", v)
+	}
+	if err := Set(&cfg, "theme.auto_update_index", "maybe"); err == nil {
+		t.Fatal("expected boolean parse error")
+	}
+	if err := Set(&cfg, "retry.max_attempts", "0"); err == nil {
+		t.Fatal("expected positive integer error")
+	}
+	if v, _ := Get(cfg, "rate_limit.gh_requests_per_minute"); v != "0" {
+		t.Fatalf("expected rate limit disabled by default, got %s", v)
+	}
+	if err := Set(&cfg, "rate_limit.gh_requests_per_minute", "-1"); err == nil {
+		t.Fatal("expected non-negative integer error")
+	}
+	if v, _ := Get(cfg, "update.enabled"); v != "true" {
+		t.Fatalf("expected update checks on by default, got %q", v)
+	}
+	if err := Set(&cfg, "update.enabled", "false"); err != nil || cfg.Update.CheckEnabled() {
+		t.Fatalf("expected update.enabled=false to disable checks: %v", err)
+	}
+	if err := Set(&cfg, "protected_repos", "alice/infra, alice/prod-*"); err != nil || len(cfg.ProtectedRepos) != 2 {
+		t.Fatalf("set protected_repos: %v %v", cfg.ProtectedRepos, err)
+	}
+	if v, _ := Get(cfg, "protected_repos"); v != "alice/infra,alice/prod-*" {
+		t.Fatalf("unexpected protected_repos: %q", v)
+	}
+	if err := Set(&cfg, "protected_repos", "alice/[x"); err == nil {
+		t.Fatal("expected invalid protected pattern error")
+	}
+	if err := Set(&cfg, "max_plan_size", "50"); err != nil || cfg.MaxPlanSize != 50 {
+		t.Fatalf("set max_plan_size: %d %v", cfg.MaxPlanSize, err)
+	}
+	if err := Set(&cfg, "max_plan_size", "-1"); err == nil {
+		t.Fatal("expected negative max_plan_size error")
+	}
+	if _, err := Get(cfg, "nope"); err == nil {
+		t.Fatal("expected unknown key error")
+	}
+}
+
+func TestThemeIndexSourcesFallsBackToIndexURL(t *testing.T) {
+	cfg := Default()
+	if got := cfg.Theme.IndexSources(); len(got) != 1 || got[0] != cfg.Theme.IndexURL {
+		t.Fatalf("expected index_url alias, got %v", got)
+	}
+	if err := Set(&cfg, "theme.index_urls", "https://a.example/index.json, ,file:///srv/themes/index.json"); err != nil {
+		t.Fatalf("set index_urls: %v", err)
+	}
+	got := cfg.Theme.IndexSources()
+	if len(got) != 2 || got[0] != "https://a.example/index.json" || got[1] != "file:///srv/themes/index.json" {
+		t.Fatalf("unexpected sources: %v", got)
+	}
+}
+
+func TestResolveKeybindings(t *testing.T) {
+	got, err := ResolveKeybindings(map[string]string{"move_top": "t", "move_bottom": "b"})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got["move_top"] != "t" || got["move_bottom"] != "b" || got["toggle"] != "space" {
+		t.Fatalf("unexpected bindings: %v", got)
+	}
+	if _, err := ResolveKeybindings(map[string]string{"select_filtered": "x"}); err == nil || !strings.Contains(err.Error(), "conflict") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if _, err := ResolveKeybindings(map[string]string{"toggle": "q"}); err == nil {
+		t.Fatal("expected reserved key error")
+	}
+	if _, err := ResolveKeybindings(map[string]string{"launch": "l"}); err == nil {
+		t.Fatal("expected unknown action error")
+	}
+}
+
+func TestLoadToleratesConflictingKeybindings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	cfg := Default()
+	cfg.Keybindings = map[string]string{"sort_name": "j"}
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load()
+	if err != nil {
+		t.Fatalf("a bad keybinding should not break load: %v", err)
+	}
+	if got.Keybindings["sort_name"] != "j" {
+		t.Fatalf("expected keybindings to be kept for the TUI to validate: %v", got.Keybindings)
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Keybinding actions map to the TUI key that triggers them. "space" names
+// the space bar.
+var defaultKeybindings = map[string]string{
+	"move_up":          "k",
+	"move_down":        "j",
+	"move_top":         "g",
+	"move_bottom":      "G",
+	"page_up":          "pgup",
+	"page_down":        "pgdown",
+	"half_page_up":     "ctrl+u",
+	"half_page_down":   "ctrl+d",
+	"toggle":           "space",
+	"select_filtered":  "a",
+	"clear_filtered":   "x",
+	"invert_filtered":  "i",
+	"regex_filter":     "ctrl+r",
+	"backup_filter":    "B",
+	"sort_name":        "n",
+	"sort_updated":     "u",
+	"sort_visibility":  "v",
+	"sort_description": "d",
+	"sort_fork":        "f",
+	"sort_archived":    "r",
+	"sort_size":        "z",
+	"open_browser":     "o",
+	"scroll_left":      "h",
+	"scroll_right":     "l",
+}
+
+// reservedKeys are handled before keybindings and cannot be rebound.
+var reservedKeys = map[string]bool{
+	"1": true, "2": true, "3": true, "tab": true, "q": true, "ctrl+c": true,
+	"enter": true, "esc": true, "backspace": true, "up": true, "down": true,
+	"home": true, "end": true, "left": true, "right": true, "?": true, "/": true,
+}
+
+func DefaultKeybindings() map[string]string {
+	out := make(map[string]string, len(defaultKeybindings))
+	for action, key := range defaultKeybindings {
+		out[action] = key
+	}
+	return out
+}
+
+// ResolveKeybindings applies overrides on top of the defaults and rejects
+// unknown actions, reserved keys, and keys bound to more than one action.
+func ResolveKeybindings(overrides map[string]string) (map[string]string, error) {
+	out := DefaultKeybindings()
+	for action, key := range overrides {
+		if _, ok := defaultKeybindings[action]; !ok {
+			return nil, fmt.Errorf("unknown keybinding action: %s", action)
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("keybinding %s cannot be empty", action)
+		}
+		out[action] = key
+	}
+	actions := make([]string, 0, len(out))
+	for action := range out {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	owner := map[string]string{}
+	for _, action := range actions {
+		key := out[action]
+		if reservedKeys[key] {
+			return nil, fmt.Errorf("keybinding %s: %q is reserved", action, key)
+		}
+		if prev, ok := owner[key]; ok {
+			return nil, fmt.Errorf("keybinding conflict: %q is bound to both %s and %s", key, prev, action)
+		}
+		owner[key] = action
+	}
+	return out, nil
+}
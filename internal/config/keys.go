@@ -0,0 +1,251 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gh-manager/internal/github"
+	"gh-manager/internal/planfile"
+)
+
+type keySpec struct {
+	get func(cfg Config) string
+	set func(cfg *Config, value string) error
+}
+
+var keys = map[string]keySpec{
+	"theme.active": {
+		get: func(cfg Config) string { return cfg.Theme.Active },
+		set: func(cfg *Config, v string) error {
+			if v == "" {
+				return fmt.Errorf("theme.active cannot be empty")
+			}
+			cfg.Theme.Active = v
+			return nil
+		},
+	},
+	"theme.index_url": {
+		get: func(cfg Config) string { return cfg.Theme.IndexURL },
+		set: func(cfg *Config, v string) error {
+			if v == "" {
+				return fmt.Errorf("theme.index_url cannot be empty")
+			}
+			cfg.Theme.IndexURL = v
+			return nil
+		},
+	},
+	"theme.index_urls": {
+		get: func(cfg Config) string { return strings.Join(cfg.Theme.IndexURLs, ",") },
+		set: func(cfg *Config, v string) error {
+			cfg.Theme.IndexURLs = nil
+			for _, u := range strings.Split(v, ",") {
+				if u = strings.TrimSpace(u); u != "" {
+					cfg.Theme.IndexURLs = append(cfg.Theme.IndexURLs, u)
+				}
+			}
+			return nil
+		},
+	},
+	"theme.auto_update_index": {
+		get: func(cfg Config) string { return strconv.FormatBool(cfg.Theme.AutoUpdateIndex) },
+		set: func(cfg *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("theme.auto_update_index must be a boolean: %q", v)
+			}
+			cfg.Theme.AutoUpdateIndex = b
+			return nil
+		},
+	},
+	"theme.auto_scheme": {
+		get: func(cfg Config) string { return strconv.FormatBool(cfg.Theme.AutoScheme) },
+		set: func(cfg *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("theme.auto_scheme must be a boolean: %q", v)
+			}
+			cfg.Theme.AutoScheme = b
+			return nil
+		},
+	},
+	"theme.light": {
+		get: func(cfg Config) string { return cfg.Theme.Light },
+		set: func(cfg *Config, v string) error {
+			cfg.Theme.Light = v
+			return nil
+		},
+	},
+	"theme.dark": {
+		get: func(cfg Config) string { return cfg.Theme.Dark },
+		set: func(cfg *Config, v string) error {
+			cfg.Theme.Dark = v
+			return nil
+		},
+	},
+	"backup.default_dir": {
+		get: func(cfg Config) string { return cfg.Backup.DefaultDir },
+		set: func(cfg *Config, v string) error {
+			if v != "" && !filepath.IsAbs(v) {
+				return fmt.Errorf("backup.default_dir must be an absolute path: %q", v)
+			}
+			cfg.Backup.DefaultDir = v
+			return nil
+		},
+	},
+	"backup.scan_archives": {
+		get: func(cfg Config) string { return strconv.FormatBool(cfg.Backup.ScanArchives) },
+		set: func(cfg *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("backup.scan_archives must be a boolean: %q", v)
+			}
+			cfg.Backup.ScanArchives = b
+			return nil
+		},
+	},
+	"archive.default_repo": {
+		get: func(cfg Config) string { return cfg.Archive.DefaultRepo },
+		set: func(cfg *Config, v string) error {
+			if v != "" {
+				if err := github.ValidateFullName(v); err != nil {
+					return fmt.Errorf("archive.default_repo: %w", err)
+				}
+			}
+			cfg.Archive.DefaultRepo = v
+			return nil
+		},
+	},
+	"retry.enabled": {
+		get: func(cfg Config) string { return strconv.FormatBool(cfg.Retry.Enabled) },
+		set: func(cfg *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("retry.enabled must be a boolean: %q", v)
+			}
+			cfg.Retry.Enabled = b
+			return nil
+		},
+	},
+	"retry.max_attempts": {
+		get: func(cfg Config) string { return strconv.Itoa(cfg.Retry.MaxAttempts) },
+		set: func(cfg *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return fmt.Errorf("retry.max_attempts must be a positive integer: %q", v)
+			}
+			cfg.Retry.MaxAttempts = n
+			return nil
+		},
+	},
+	"rate_limit.gh_requests_per_minute": {
+		get: func(cfg Config) string { return strconv.Itoa(cfg.RateLimit.GHRequestsPerMinute) },
+		set: func(cfg *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("rate_limit.gh_requests_per_minute must be a non-negative integer: %q", v)
+			}
+			cfg.RateLimit.GHRequestsPerMinute = n
+			return nil
+		},
+	},
+	"cache.repos_ttl_minutes": {
+		get: func(cfg Config) string { return strconv.Itoa(cfg.Cache.ReposTTLMinutes) },
+		set: func(cfg *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("cache.repos_ttl_minutes must be a non-negative integer: %q", v)
+			}
+			cfg.Cache.ReposTTLMinutes = n
+			return nil
+		},
+	},
+	"cache.update_check_hours": {
+		get: func(cfg Config) string { return strconv.Itoa(int(cfg.Cache.UpdateCheckTTL() / time.Hour)) },
+		set: func(cfg *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("cache.update_check_hours must be a non-negative integer: %q", v)
+			}
+			cfg.Cache.UpdateCheckHours = &n
+			return nil
+		},
+	},
+	"update.enabled": {
+		get: func(cfg Config) string { return strconv.FormatBool(cfg.Update.CheckEnabled()) },
+		set: func(cfg *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("update.enabled must be a boolean: %q", v)
+			}
+			cfg.Update.Enabled = &b
+			return nil
+		},
+	},
+	"max_plan_size": {
+		get: func(cfg Config) string { return strconv.Itoa(cfg.MaxPlanSize) },
+		set: func(cfg *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("max_plan_size must be a non-negative integer (0 disables): %q", v)
+			}
+			cfg.MaxPlanSize = n
+			return nil
+		},
+	},
+	"protected_repos": {
+		get: func(cfg Config) string { return strings.Join(cfg.ProtectedRepos, ",") },
+		set: func(cfg *Config, v string) error {
+			var patterns []string
+			for _, p := range strings.Split(v, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					patterns = append(patterns, p)
+				}
+			}
+			if _, err := planfile.ProtectedMatcher(patterns); err != nil {
+				return err
+			}
+			cfg.ProtectedRepos = patterns
+			return nil
+		},
+	},
+	"retry.base_delay_ms": {
+		get: func(cfg Config) string { return strconv.Itoa(cfg.Retry.BaseDelayMS) },
+		set: func(cfg *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return fmt.Errorf("retry.base_delay_ms must be a positive integer: %q", v)
+			}
+			cfg.Retry.BaseDelayMS = n
+			return nil
+		},
+	},
+}
+
+func Keys() []string {
+	out := make([]string, 0, len(keys))
+	for k := range keys {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func Get(cfg Config, key string) (string, error) {
+	spec, ok := keys[key]
+	if !ok {
+		return "", fmt.Errorf("unknown config key: %s (known: %s)", key, strings.Join(Keys(), ", "))
+	}
+	return spec.get(cfg), nil
+}
+
+func Set(cfg *Config, key, value string) error {
+	spec, ok := keys[key]
+	if !ok {
+		return fmt.Errorf("unknown config key: %s (known: %s)", key, strings.Join(Keys(), ", "))
+	}
+	return spec.set(cfg, strings.TrimSpace(value))
+}
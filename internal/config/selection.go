@@ -0,0 +1,60 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type Selection struct {
+	SavedAt string   `json:"saved_at"`
+	Repos   []string `json:"repos"`
+}
+
+func SelectionPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "selection.json"), nil
+}
+
+func SaveSelection(fullNames []string, now time.Time) error {
+	p, err := SelectionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	repos := append([]string(nil), fullNames...)
+	sort.Strings(repos)
+	b, err := json.MarshalIndent(Selection{SavedAt: now.UTC().Format(time.RFC3339), Repos: repos}, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	return os.WriteFile(p, b, 0o600)
+}
+
+func LoadSelection() ([]string, error) {
+	p, err := SelectionPath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Selection
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	return s.Repos, nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// UpdateCheck is the last successful release check. The TUI reuses it until
+// it is older than cache.update_check_hours.
+type UpdateCheck struct {
+	CheckedAt      string `json:"checked_at"`
+	CurrentVersion string `json:"current_version"`
+	LatestVersion  string `json:"latest_version"`
+	ReleaseURL     string `json:"release_url,omitempty"`
+}
+
+func UpdateCheckPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "update-check.json"), nil
+}
+
+func SaveUpdateCheck(c UpdateCheck) error {
+	p, err := UpdateCheckPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	return os.WriteFile(p, b, 0o600)
+}
+
+// LoadUpdateCheck returns the saved check; ok is false when none exists.
+func LoadUpdateCheck() (UpdateCheck, bool, error) {
+	p, err := UpdateCheckPath()
+	if err != nil {
+		return UpdateCheck{}, false, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return UpdateCheck{}, false, nil
+	}
+	if err != nil {
+		return UpdateCheck{}, false, err
+	}
+	var c UpdateCheck
+	if err := json.Unmarshal(b, &c); err != nil {
+		return UpdateCheck{}, false, err
+	}
+	return c, true, nil
+}
@@ -1,13 +1,39 @@
 package doctor
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os/exec"
+	"strings"
 
 	"gh-manager/internal/app"
 )
 
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Result is the outcome of a single doctor check.
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+	// Fix is the command `doctor --fix` offers to run for this result.
+	Fix []string `json:"fix,omitempty"`
+}
+
+// RequiredScopes are the classic token scopes gh-manager needs to list,
+// archive and delete repositories.
+var RequiredScopes = []string{"repo", "delete_repo"}
+
 func Check(ctx context.Context, runner app.CommandRunner) error {
 	for _, bin := range []string{"gh", "git"} {
 		if _, err := exec.LookPath(bin); err != nil {
@@ -19,3 +45,137 @@ func Check(ctx context.Context, runner app.CommandRunner) error {
 	}
 	return nil
 }
+
+// Diagnose runs every check and reports each one instead of stopping at the
+// first failure. Checks that depend on a failed one are skipped.
+func Diagnose(ctx context.Context, runner app.CommandRunner) []Result {
+	var results []Result
+	depsOK := true
+	for _, bin := range []string{"gh", "git"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			depsOK = false
+			results = append(results, Result{Name: bin, Status: StatusFail, Message: fmt.Sprintf("missing dependency %q in PATH", bin)})
+			continue
+		}
+		results = append(results, Result{Name: bin, Status: StatusOK, Message: "found in PATH"})
+	}
+	if !depsOK {
+		return results
+	}
+	results = append(results, checkLFS())
+	if _, err := runner.Run(ctx, "gh", "auth", "status"); err != nil {
+		return append(results, Result{Name: "auth", Status: StatusFail, Message: fmt.Sprintf("gh auth status failed: %v", err), Hint: "run `gh auth login`", Fix: []string{"gh", "auth", "login"}})
+	}
+	results = append(results, Result{Name: "auth", Status: StatusOK, Message: "gh is authenticated"})
+	return append(results, checkScopes(ctx, runner))
+}
+
+// Failed reports whether any result has failed.
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+func checkScopes(ctx context.Context, runner app.CommandRunner) Result {
+	res := Result{Name: "token-scopes"}
+	out, err := runner.Run(ctx, "gh", "api", "-i", "user")
+	if err != nil {
+		res.Status = StatusWarn
+		res.Message = fmt.Sprintf("could not read token scopes: %v", err)
+		return res
+	}
+	scopes, ok := parseScopes(out)
+	if !ok {
+		res.Status = StatusWarn
+		res.Message = "token scopes not reported (fine-grained or app token); delete permission is checked at execution time"
+		return res
+	}
+	var missing []string
+	for _, want := range RequiredScopes {
+		if !scopes[want] {
+			missing = append(missing, want)
+		}
+	}
+	if len(missing) > 0 {
+		res.Status = StatusWarn
+		res.Message = fmt.Sprintf("token is missing scope(s): %s", strings.Join(missing, ", "))
+		res.Hint = "run `gh auth refresh -s " + strings.Join(missing, ",") + "`"
+		res.Fix = []string{"gh", "auth", "refresh", "-s", strings.Join(missing, ",")}
+		return res
+	}
+	res.Status = StatusOK
+	res.Message = "token has " + strings.Join(RequiredScopes, ", ")
+	return res
+}
+
+func checkLFS() Result {
+	if GitLFSAvailable() {
+		return Result{Name: "git-lfs", Status: StatusOK, Message: "found in PATH"}
+	}
+	return Result{Name: "git-lfs", Status: StatusWarn, Message: "git-lfs not in PATH; --include-lfs will skip LFS objects", Hint: "install git-lfs (https://git-lfs.com), then run `git lfs install`"}
+}
+
+// Fix walks results that are not ok and offers each remedy: a result with a
+// Fix command is run through runner after the user answers y, one with only
+// a Hint is printed as a manual step. It reports how many commands ran so
+// the caller knows whether to re-check.
+func Fix(ctx context.Context, runner app.CommandRunner, results []Result, in io.Reader, out io.Writer) int {
+	reader := bufio.NewReader(in)
+	ran := 0
+	for _, r := range results {
+		if r.Status == StatusOK {
+			continue
+		}
+		if len(r.Fix) == 0 {
+			if r.Hint != "" {
+				fmt.Fprintf(out, "%s: no automatic fix; %s\n", r.Name, r.Hint)
+			}
+			continue
+		}
+		fmt.Fprintf(out, "%s: %s\nRun `%s`? [y/N]: ", r.Name, r.Message, strings.Join(r.Fix, " "))
+		answer, _ := reader.ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Fprintln(out, "skipped")
+			continue
+		}
+		ran++
+		if _, err := runner.Run(ctx, r.Fix[0], r.Fix[1:]...); err != nil {
+			fmt.Fprintf(out, "%s fix failed: %v\n", r.Name, err)
+		}
+	}
+	return ran
+}
+
+// parseScopes reads the X-OAuth-Scopes header from `gh api -i` output. ok is
+// false when the header is absent.
+func parseScopes(out []byte) (map[string]bool, bool) {
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			break
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), "X-OAuth-Scopes") {
+			continue
+		}
+		scopes := map[string]bool{}
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				scopes[s] = true
+			}
+		}
+		return scopes, true
+	}
+	return nil, false
+}
+
+// GitLFSAvailable reports whether the git-lfs extension is in PATH.
+func GitLFSAvailable() bool {
+	_, err := exec.LookPath("git-lfs")
+	return err == nil
+}
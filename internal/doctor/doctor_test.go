@@ -0,0 +1,87 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeRunner struct {
+	out string
+}
+
+func (f fakeRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	return []byte(f.out), nil
+}
+
+func TestCheckScopesReportsMissingDeleteRepo(t *testing.T) {
+	r := fakeRunner{out: "HTTP/2.0 200 OK\r\nX-Oauth-Scopes: gist, read:org, repo\r\nContent-Type: application/json\r\n\r\n{\"login\":\"alice\"}"}
+	res := checkScopes(context.Background(), r)
+	if res.Status != StatusWarn {
+		t.Fatalf("expected warn, got %+v", res)
+	}
+	if !strings.Contains(res.Message, "delete_repo") || strings.Contains(res.Message, "repo,") {
+		t.Fatalf("unexpected message: %q", res.Message)
+	}
+	if res.Hint != "run `gh auth refresh -s delete_repo`" {
+		t.Fatalf("unexpected hint: %q", res.Hint)
+	}
+	if strings.Join(res.Fix, " ") != "gh auth refresh -s delete_repo" {
+		t.Fatalf("unexpected fix command: %v", res.Fix)
+	}
+}
+
+func TestCheckScopesOK(t *testing.T) {
+	r := fakeRunner{out: "HTTP/2.0 200 OK\nX-OAuth-Scopes: delete_repo, repo\n\n{}"}
+	if res := checkScopes(context.Background(), r); res.Status != StatusOK {
+		t.Fatalf("expected ok, got %+v", res)
+	}
+}
+
+func TestCheckScopesWithoutHeaderWarns(t *testing.T) {
+	r := fakeRunner{out: "HTTP/2.0 200 OK\nContent-Type: application/json\n\n{\"X-OAuth-Scopes\": \"repo\"}"}
+	res := checkScopes(context.Background(), r)
+	if res.Status != StatusWarn || !strings.Contains(res.Message, "not reported") {
+		t.Fatalf("expected not-reported warning, got %+v", res)
+	}
+}
+
+type recordingRunner struct {
+	calls []string
+	err   error
+}
+
+func (r *recordingRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	r.calls = append(r.calls, strings.Join(append([]string{name}, args...), " "))
+	return nil, r.err
+}
+
+func TestFixPromptsBeforeRunningEachFix(t *testing.T) {
+	results := []Result{
+		{Name: "gh", Status: StatusOK, Message: "found in PATH"},
+		{Name: "git-lfs", Status: StatusWarn, Message: "git-lfs not in PATH", Hint: "install git-lfs"},
+		{Name: "auth", Status: StatusFail, Message: "not logged in", Fix: []string{"gh", "auth", "login"}},
+		{Name: "token-scopes", Status: StatusWarn, Message: "missing delete_repo", Fix: []string{"gh", "auth", "refresh", "-s", "delete_repo"}},
+	}
+	runner := &recordingRunner{}
+	var out strings.Builder
+	ran := Fix(context.Background(), runner, results, strings.NewReader("y\nn\n"), &out)
+	if ran != 1 || len(runner.calls) != 1 || runner.calls[0] != "gh auth login" {
+		t.Fatalf("expected only the accepted fix to run, ran=%d calls=%v", ran, runner.calls)
+	}
+	for _, want := range []string{"git-lfs: no automatic fix; install git-lfs", "Run `gh auth login`? [y/N]", "Run `gh auth refresh -s delete_repo`? [y/N]", "skipped"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("missing %q in:\n%s", want, out.String())
+		}
+	}
+
+	runner = &recordingRunner{err: errors.New("exit status 1")}
+	out.Reset()
+	if ran := Fix(context.Background(), runner, results, strings.NewReader(""), &out); ran != 0 || len(runner.calls) != 0 {
+		t.Fatalf("no answer must not run anything, ran=%d calls=%v", ran, runner.calls)
+	}
+	if ran := Fix(context.Background(), runner, results[2:3], strings.NewReader("yes\n"), &out); ran != 1 || !strings.Contains(out.String(), "auth fix failed: exit status 1") {
+		t.Fatalf("expected failed fix to be reported, ran=%d out=%s", ran, out.String())
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +21,8 @@ import (
 const (
 	ModeDelete                      = "delete"
 	ModeBackup                      = "backup"
+	ConfirmPhrase                   = "phrase"
+	ConfirmCount                    = "count"
 	archiveMaxBundleSizeBytes int64 = 100 * 1024 * 1024
 )
 
@@ -34,20 +37,71 @@ type Config struct {
 	ArchiveBranch     string
 	ArchiveVisibility string
 	NoArchive         bool
+	// ConfirmationMode selects the prompt gate: "phrase" (default) or "count".
+	ConfirmationMode string
+	// ConfirmationPhrase replaces ACCEPT/CONFIRM in phrase mode when set.
+	ConfirmationPhrase string
+	// AssumeYes skips the interactive prompt (set only via --yes or GH_MANAGER_ASSUME_YES).
+	AssumeYes bool
+	// DefaultBackupBase replaces $HOME as the parent of timestamped backup roots.
+	DefaultBackupBase string
+	// ResumeSearchDirs are extra places to look for a matching manifest on resume.
+	// Each may be a backup root itself or a parent of gh-manager-archive-* roots.
+	ResumeSearchDirs []string
+	// ProtectedRepos are protected_repos patterns; delete mode skips matching
+	// repos with a warning even if a plan lists them.
+	ProtectedRepos []string
+	// PrintCommands marks a run whose runner only echoes mutating commands, so
+	// no bundle files exist and the archive size check is skipped.
+	PrintCommands bool
+	// OnlyFailed restricts a resumed run to entries that failed last time
+	// (backup_failed, delete_failed or archive_failed); it needs an existing manifest.
+	OnlyFailed bool
+	// PruneMirror removes a repo's mirror clone once its bundle and snapshot exist (backup mode only).
+	PruneMirror bool
+	// NoSnapshot skips the browsable working clone; restore falls back to the bundle.
+	NoSnapshot bool
+	// IncludeLFS fetches Git LFS objects after mirroring when the backup provider supports it.
+	IncludeLFS bool
+	// ExtraManifestPath, when set, receives a copy of the manifest after every write.
+	ExtraManifestPath string
+	// LogPath appends per-repo JSONL events to this file; relative paths live in the backup root.
+	LogPath string
+	// BackupThenDelete requires a mirror and a bundle that passes `git bundle
+	// verify` before each delete; repos that fail are left in place.
+	BackupThenDelete bool
+	// PerRepoTimeout bounds each backup, snapshot, bundle and delete call for
+	// a repo; a call that runs over fails that repo and the run moves on.
+	PerRepoTimeout time.Duration
+	// RiskActiveWindow is how recently a source repo must have been updated to
+	// count as high risk in delete mode (default DefaultRiskActiveWindow).
+	RiskActiveWindow time.Duration
 }
 
+// ErrInterrupted is returned, with the partial Result, when ctx is cancelled
+// between repos. The manifest is up to date, so the run can be resumed.
+var ErrInterrupted = errors.New("interrupted")
+
 type Result struct {
 	ManifestPath        string
 	BackupRoot          string
 	Deleted             int
 	Failed              int
+	SkippedNoBackup     int
+	SkippedProtected    int
 	ArchiveFailed       int
 	ArchiveSkippedSize  int
 	Total               int
 	ArchiveCommit       string
+	ArchiveBundles      int
+	ArchiveBytes        int64
+	ArchiveDeduped      int
 	ArchiveRepo         string
 	ArchiveBranch       string
 	ArchiveSkippedRepos []string
+	BytesMirrored       int64
+	BytesBundled        int64
+	BytesSkippedSize    int64
 }
 
 type Executor struct {
@@ -58,8 +112,24 @@ type Executor struct {
 	Now     func() time.Time
 	In      io.Reader
 	Out     io.Writer
+	// Progress, when set, is called as each repo enters a new stage.
+	Progress func(ProgressEvent)
+}
+
+type ProgressEvent struct {
+	Index    int
+	Total    int
+	FullName string
+	Stage    string
 }
 
+const (
+	StageBackup   = "backup"
+	StageSnapshot = "snapshot"
+	StageBundle   = "bundle"
+	StageDelete   = "delete"
+)
+
 type RepoDeleter interface {
 	DeleteRepo(ctx context.Context, fullName string) error
 }
@@ -74,8 +144,19 @@ type BackupProvider interface {
 	CreateBundle(ctx context.Context, repo planfile.RepoRecord, root string) (string, error)
 }
 
+// LFSFetcher is implemented by backup providers that can store Git LFS objects.
+type LFSFetcher interface {
+	FetchLFS(ctx context.Context, repo planfile.RepoRecord, root string) (string, bool, error)
+}
+
+// BundleVerifier is implemented by backup providers that can check a bundle
+// against its mirror.
+type BundleVerifier interface {
+	VerifyBundle(ctx context.Context, repo planfile.RepoRecord, root, bundlePath string) error
+}
+
 type ArchivePublisher interface {
-	PublishBundles(ctx context.Context, archiveRepo, branch, backupRoot string, bundles []manifest.BundleArtifact, planFingerprint string) (string, error)
+	PublishBundles(ctx context.Context, archiveRepo, branch, backupRoot string, bundles []manifest.BundleArtifact, planFingerprint string) (manifest.PublishResult, error)
 }
 
 func (e Executor) Execute(ctx context.Context, cfg Config, plan planfile.DeletionPlanV1) (Result, error) {
@@ -97,47 +178,129 @@ func (e Executor) Execute(ctx context.Context, cfg Config, plan planfile.Deletio
 	if cfg.Mode != ModeDelete && cfg.Mode != ModeBackup {
 		return Result{}, fmt.Errorf("unsupported mode: %s", cfg.Mode)
 	}
+	if cfg.ConfirmationMode == "" {
+		cfg.ConfirmationMode = ConfirmPhrase
+	}
+	if cfg.ConfirmationMode != ConfirmPhrase && cfg.ConfirmationMode != ConfirmCount {
+		return Result{}, fmt.Errorf("unsupported confirmation mode: %s", cfg.ConfirmationMode)
+	}
 	if e.Backup == nil {
 		return Result{}, errors.New("executor backup service is nil")
 	}
 	if cfg.Mode == ModeDelete && e.GH == nil {
 		return Result{}, errors.New("executor GH client is nil")
 	}
-
-	backupRoot, err := e.resolveBackupRoot(plan.Fingerprint, cfg.BackupDir, cfg.Resume)
+	isProtected, err := planfile.ProtectedMatcher(cfg.ProtectedRepos)
 	if err != nil {
 		return Result{}, err
 	}
 
-	if err := requireConfirmation(e.In, e.Out, len(plan.Repos), cfg.Mode); err != nil {
+	backupRoot, err := e.resolveBackupRoot(plan.Fingerprint, cfg)
+	if err != nil {
 		return Result{}, err
 	}
 
 	if cfg.DryRun {
-		return e.simulate(cfg, plan, backupRoot), nil
+		return e.simulate(cfg, plan, backupRoot, isProtected), nil
+	}
+
+	manifestPath := manifest.Path(backupRoot)
+	m, resumed, err := readManifest(cfg, plan, manifestPath)
+	if err != nil {
+		return Result{}, err
+	}
+	if cfg.Mode == ModeDelete {
+		high := printRiskBreakdown(e.Out, deleteCandidates(cfg, plan.Repos, m, isProtected), e.Now(), cfg.RiskActiveWindow)
+		if high > 0 && cfg.ConfirmationMode == ConfirmPhrase && !cfg.AssumeYes {
+			fmt.Fprintf(e.Out, "Confirmation escalated: %d high-risk repos require typing the repository count.\n", high)
+			cfg.ConfirmationMode = ConfirmCount
+		}
+	}
+	if err := requireConfirmation(e.In, e.Out, len(plan.Repos), cfg); err != nil {
+		return Result{}, err
 	}
 
 	if err := os.MkdirAll(backupRoot, 0o700); err != nil {
 		return Result{}, err
 	}
-	manifestPath := manifest.Path(backupRoot)
-	m, err := loadOrCreateManifest(cfg, plan, backupRoot, manifestPath, e.Now)
+	if !resumed {
+		if m, err = createManifest(cfg, plan, backupRoot, manifestPath, e.Now); err != nil {
+			return Result{}, err
+		}
+	}
+	// The audit copy is best effort: a failed write is reported once and
+	// never stops the run.
+	extraFailed := false
+	writeManifest := func() error {
+		if err := manifest.Write(manifestPath, m); err != nil {
+			return err
+		}
+		if cfg.ExtraManifestPath != "" {
+			if err := manifest.Write(cfg.ExtraManifestPath, m); err != nil && !extraFailed {
+				extraFailed = true
+				fmt.Fprintf(e.Out, "warning: could not write manifest copy to %s: %v\n", cfg.ExtraManifestPath, err)
+			}
+		}
+		return nil
+	}
+	rlog, err := openRunLog(cfg.LogPath, backupRoot, e.Now)
 	if err != nil {
-		return Result{}, err
+		return Result{}, fmt.Errorf("open run log: %w", err)
 	}
+	defer rlog.Close()
+	rlog.write(LogEvent{Event: "run_start", Mode: cfg.Mode, Total: len(m.RepoExecutions)})
 
 	repoByFullName := make(map[string]planfile.RepoRecord, len(plan.Repos))
 	for _, r := range plan.Repos {
 		repoByFullName[r.FullName] = r
 	}
 
+	// --backup-then-delete verifies a standalone backup of the same plan
+	// before making a new one.
+	var prior priorBackup
+	if cfg.Mode == ModeDelete && cfg.BackupThenDelete {
+		prior = findPriorBackup(plan.Fingerprint, backupSearchBases(cfg), backupRoot)
+	}
+
 	archiveBundles := make([]manifest.BundleArtifact, 0)
+	total := len(m.RepoExecutions)
 
+	// The outcome of each processed repo is logged once the loop moves on,
+	// so every early continue below is covered.
+	pending := -1
+	logPending := func() {
+		if pending >= 0 {
+			rlog.repoDone(pending+1, total, m.RepoExecutions[pending])
+			pending = -1
+		}
+	}
+	interrupted := false
 	for i := range m.RepoExecutions {
+		logPending()
+		if ctx.Err() != nil {
+			interrupted = true
+			break
+		}
 		entry := &m.RepoExecutions[i]
+		step := func(stage, line string) {
+			rlog.write(LogEvent{Event: "stage", Repo: entry.FullName, Index: i + 1, Total: total, Stage: stage})
+			e.reportProgress(ProgressEvent{Index: i + 1, Total: total, FullName: entry.FullName, Stage: stage}, line)
+		}
+		if cfg.OnlyFailed && !FailedEntry(*entry) {
+			continue
+		}
 		if shouldSkipEntry(cfg.Mode, *entry) {
+			if cfg.Mode == ModeBackup && needsArchive(*entry) {
+				archiveBundles = append(archiveBundles, manifest.BundleArtifact{
+					FullName:   entry.FullName,
+					BundlePath: entry.BundlePath,
+					UpdatedAt:  repoByFullName[entry.FullName].UpdatedAt,
+					Visibility: manifest.RepoVisibility(repoByFullName[entry.FullName]),
+				})
+			}
 			continue
 		}
+		pending = i
 		repo, ok := repoByFullName[entry.FullName]
 		if !ok {
 			entry.Status = manifest.StatusDeleteFailed
@@ -145,20 +308,43 @@ func (e Executor) Execute(ctx context.Context, cfg Config, plan planfile.Deletio
 			entry.Attempts++
 			entry.LastAttemptAt = e.Now().UTC().Format(time.RFC3339)
 			m.Touch(e.Now())
-			_ = manifest.Write(manifestPath, m)
+			_ = writeManifest()
 			continue
 		}
+		if cfg.Mode == ModeDelete && isProtected(repo.FullName) {
+			entry.Status = manifest.StatusSkippedProtected
+			entry.Error = "matches protected_repos"
+			m.Touch(e.Now())
+			if err := writeManifest(); err != nil {
+				return Result{}, err
+			}
+			fmt.Fprintf(e.Out, "warning: skipping protected repo %s (matches protected_repos)\n", repo.FullName)
+			continue
+		}
+		verifyRoot := backupRoot
+		if p, ok := prior.entries[entry.FullName]; ok && (entry.BackupPath == "" || entry.BackupPath == p.BackupPath) {
+			if entry.BackupPath == "" {
+				adoptBackup(entry, p)
+				fmt.Fprintf(e.Out, "Using existing backup of %s from %s\n", repo.FullName, prior.root)
+			}
+			verifyRoot = prior.root
+		}
+		reused := verifyRoot != backupRoot
 
-		if entry.BackupPath == "" || entry.Status == manifest.StatusPending || entry.Status == manifest.StatusBackupFailed {
-			fmt.Fprintf(e.Out, "Backing up %s...\n", repo.FullName)
-			backupPath, berr := e.Backup.MirrorBackup(ctx, repo, backupRoot)
+		if (entry.BackupPath == "" && !mirrorPruned(*entry)) || entry.Status == manifest.StatusPending || entry.Status == manifest.StatusBackupFailed {
+			step(StageBackup, "Backing up "+repo.FullName+"...")
+			var backupPath string
+			berr := withOpTimeout(ctx, cfg.PerRepoTimeout, func(ctx context.Context) (err error) {
+				backupPath, err = e.Backup.MirrorBackup(ctx, repo, backupRoot)
+				return err
+			})
 			entry.Attempts++
 			entry.LastAttemptAt = e.Now().UTC().Format(time.RFC3339)
 			if berr != nil {
 				entry.Status = manifest.StatusBackupFailed
 				entry.Error = berr.Error()
 				m.Touch(e.Now())
-				_ = manifest.Write(manifestPath, m)
+				_ = writeManifest()
 				fmt.Fprintf(e.Out, "Backup failed for %s: %v\n", repo.FullName, berr)
 				continue
 			}
@@ -166,71 +352,137 @@ func (e Executor) Execute(ctx context.Context, cfg Config, plan planfile.Deletio
 			entry.Status = manifest.StatusBackupOK
 			entry.Error = ""
 			m.Touch(e.Now())
-			if err := manifest.Write(manifestPath, m); err != nil {
+			if err := writeManifest(); err != nil {
+				return Result{}, err
+			}
+		}
+		if lfs, ok := e.Backup.(LFSFetcher); ok && cfg.IncludeLFS && !reused && entry.BackupPath != "" && entry.LFSPath == "" {
+			step(StageBackup, "Fetching LFS objects "+repo.FullName+"...")
+			var lfsPath string
+			var used bool
+			lerr := withOpTimeout(ctx, cfg.PerRepoTimeout, func(ctx context.Context) (err error) {
+				lfsPath, used, err = lfs.FetchLFS(ctx, repo, backupRoot)
+				return err
+			})
+			if lerr != nil {
+				entry.Status = manifest.StatusBackupFailed
+				entry.Error = lerr.Error()
+				m.Touch(e.Now())
+				_ = writeManifest()
+				fmt.Fprintf(e.Out, "LFS fetch failed for %s: %v\n", repo.FullName, lerr)
+				continue
+			}
+			entry.LFS = used
+			entry.LFSPath = lfsPath
+			m.Touch(e.Now())
+			if err := writeManifest(); err != nil {
 				return Result{}, err
 			}
 		}
-		if entry.BrowsablePath == "" {
-			fmt.Fprintf(e.Out, "Creating browsable snapshot %s...\n", repo.FullName)
-			snapshotPath, serr := e.Backup.CreateBrowsableSnapshot(ctx, repo, backupRoot)
+		if cfg.NoSnapshot {
+			entry.SnapshotSkipped = true
+		} else if entry.BrowsablePath == "" && !reused {
+			step(StageSnapshot, "Creating browsable snapshot "+repo.FullName+"...")
+			var snapshotPath string
+			serr := withOpTimeout(ctx, cfg.PerRepoTimeout, func(ctx context.Context) (err error) {
+				snapshotPath, err = e.Backup.CreateBrowsableSnapshot(ctx, repo, backupRoot)
+				return err
+			})
 			entry.Attempts++
 			entry.LastAttemptAt = e.Now().UTC().Format(time.RFC3339)
 			if serr != nil {
 				entry.Status = manifest.StatusBackupFailed
 				entry.Error = serr.Error()
 				m.Touch(e.Now())
-				_ = manifest.Write(manifestPath, m)
+				_ = writeManifest()
 				fmt.Fprintf(e.Out, "Browsable snapshot failed for %s: %v\n", repo.FullName, serr)
 				continue
 			}
 			entry.BrowsablePath = snapshotPath
+			entry.SnapshotSkipped = false
 			entry.Error = ""
 			m.Touch(e.Now())
-			if err := manifest.Write(manifestPath, m); err != nil {
+			if err := writeManifest(); err != nil {
 				return Result{}, err
 			}
 		}
 
 		if cfg.Mode == ModeBackup {
 			if entry.BundlePath == "" {
-				fmt.Fprintf(e.Out, "Creating bundle %s...\n", repo.FullName)
-				bundlePath, berr := e.Backup.CreateBundle(ctx, repo, backupRoot)
+				step(StageBundle, "Creating bundle "+repo.FullName+"...")
+				var bundlePath string
+				berr := withOpTimeout(ctx, cfg.PerRepoTimeout, func(ctx context.Context) (err error) {
+					bundlePath, err = e.Backup.CreateBundle(ctx, repo, backupRoot)
+					return err
+				})
 				entry.Attempts++
 				entry.LastAttemptAt = e.Now().UTC().Format(time.RFC3339)
 				if berr != nil {
 					entry.Status = manifest.StatusBackupFailed
 					entry.Error = berr.Error()
 					m.Touch(e.Now())
-					_ = manifest.Write(manifestPath, m)
+					_ = writeManifest()
 					fmt.Fprintf(e.Out, "Bundle failed for %s: %v\n", repo.FullName, berr)
 					continue
 				}
 				entry.BundlePath = bundlePath
+				entry.Compression = manifest.BundleCompression(bundlePath)
 				entry.Error = ""
 				m.Touch(e.Now())
-				if err := manifest.Write(manifestPath, m); err != nil {
+				if err := writeManifest(); err != nil {
 					return Result{}, err
 				}
 			}
+			if cfg.PruneMirror && entry.BackupPath != "" {
+				if err := pruneMirror(backupRoot, entry.BackupPath); err != nil {
+					fmt.Fprintf(e.Out, "Mirror prune failed for %s: %v\n", repo.FullName, err)
+				} else {
+					entry.BackupPath = ""
+					m.Touch(e.Now())
+					if err := writeManifest(); err != nil {
+						return Result{}, err
+					}
+				}
+			}
 			archiveBundles = append(archiveBundles, manifest.BundleArtifact{
 				FullName:   repo.FullName,
 				BundlePath: entry.BundlePath,
 				UpdatedAt:  repo.UpdatedAt,
+				Visibility: manifest.RepoVisibility(repo),
 			})
 			continue
 		}
 
-		fmt.Fprintf(e.Out, "Deleting %s...\n", repo.FullName)
+		if cfg.BackupThenDelete {
+			if reason := e.verifyBackup(ctx, cfg.PerRepoTimeout, repo, entry, verifyRoot, step); reason != "" {
+				entry.Status = manifest.StatusSkippedNoBackup
+				entry.Error = reason
+				m.Touch(e.Now())
+				if err := writeManifest(); err != nil {
+					return Result{}, err
+				}
+				fmt.Fprintf(e.Out, "Skipping delete of %s: %s\n", repo.FullName, reason)
+				continue
+			}
+		}
+
+		step(StageDelete, "Deleting "+repo.FullName+"...")
 		var derr error
 		for attempt := 1; attempt <= cfg.MaxDeleteRetries; attempt++ {
-			derr = e.GH.DeleteRepo(ctx, repo.FullName)
-			if derr == nil {
+			derr = withOpTimeout(ctx, cfg.PerRepoTimeout, func(ctx context.Context) error {
+				return e.GH.DeleteRepo(ctx, repo.FullName)
+			})
+			if derr == nil || repoNotFound(derr) {
 				break
 			}
 		}
 		entry.Attempts++
 		entry.LastAttemptAt = e.Now().UTC().Format(time.RFC3339)
-		if derr != nil {
+		if derr != nil && repoNotFound(derr) {
+			entry.Status = manifest.StatusDeleted
+			entry.Error = ""
+			fmt.Fprintf(e.Out, "Already deleted %s (not found)\n", repo.FullName)
+		} else if derr != nil {
 			entry.Status = manifest.StatusDeleteFailed
 			entry.Error = derr.Error()
 			fmt.Fprintf(e.Out, "Delete failed for %s: %v\n", repo.FullName, derr)
@@ -240,13 +492,19 @@ func (e Executor) Execute(ctx context.Context, cfg Config, plan planfile.Deletio
 			fmt.Fprintf(e.Out, "Deleted %s\n", repo.FullName)
 		}
 		m.Touch(e.Now())
-		if err := manifest.Write(manifestPath, m); err != nil {
+		if err := writeManifest(); err != nil {
 			return Result{}, err
 		}
 	}
+	logPending()
 
-	archiveCommit := ""
-	if cfg.Mode == ModeBackup {
+	var published manifest.PublishResult
+	var runErr error
+	if interrupted {
+		// Entries not reached stay pending (and unpublished) for resume.
+		runErr = ErrInterrupted
+		fmt.Fprintln(e.Out, "Interrupted; remaining repos left pending.")
+	} else if cfg.Mode == ModeBackup {
 		if !cfg.NoArchive && len(archiveBundles) > 0 {
 			if cfg.ArchiveRepo == "" {
 				cfg.ArchiveRepo = plan.Actor + "/gh-manager-archive"
@@ -257,9 +515,12 @@ func (e Executor) Execute(ctx context.Context, cfg Config, plan planfile.Deletio
 			if cfg.ArchiveVisibility == "" {
 				cfg.ArchiveVisibility = "private"
 			}
-			eligibleBundles, sizeSkipped := filterArchiveBundlesBySize(backupRoot, archiveBundles, &m, archiveMaxBundleSizeBytes, e.Out)
+			eligibleBundles, sizeSkipped := archiveBundles, []string(nil)
+			if !cfg.PrintCommands {
+				eligibleBundles, sizeSkipped = filterArchiveBundlesBySize(backupRoot, archiveBundles, &m, archiveMaxBundleSizeBytes, e.Out)
+			}
 			m.Touch(e.Now())
-			_ = manifest.Write(manifestPath, m)
+			_ = writeManifest()
 			if len(sizeSkipped) > 0 {
 				fmt.Fprintf(e.Out, "Archive size-skip: %d bundle(s) moved to %s\n", len(sizeSkipped), filepath.Join(backupRoot, "archive-skipped-size"))
 			}
@@ -271,43 +532,195 @@ func (e Executor) Execute(ctx context.Context, cfg Config, plan planfile.Deletio
 				}
 				if err := e.RepoMgr.EnsureRepo(ctx, cfg.ArchiveRepo, cfg.ArchiveVisibility); err != nil {
 					markArchiveFailure(&m, err, eligibleBundles)
-					_ = manifest.Write(manifestPath, m)
+					_ = writeManifest()
 					return Result{}, err
 				}
-				archiveCommit, err = e.Archive.PublishBundles(ctx, cfg.ArchiveRepo, cfg.ArchiveBranch, backupRoot, eligibleBundles, plan.Fingerprint)
+				published, err = e.Archive.PublishBundles(ctx, cfg.ArchiveRepo, cfg.ArchiveBranch, backupRoot, eligibleBundles, plan.Fingerprint)
 				if err != nil {
 					markArchiveFailure(&m, err, eligibleBundles)
 					m.Touch(e.Now())
-					_ = manifest.Write(manifestPath, m)
+					_ = writeManifest()
 					fmt.Fprintf(e.Out, "Archive publish failed: %v\n", err)
+					rlog.write(LogEvent{Event: "archive", Status: "failed", Error: err.Error()})
 				} else {
-					markArchiveSuccess(&m, archiveCommit, eligibleBundles)
+					rlog.write(LogEvent{Event: "archive", Status: "published", Commit: published.Commit})
+					markArchiveSuccess(&m, published.Commit, eligibleBundles)
 					m.Touch(e.Now())
-					_ = manifest.Write(manifestPath, m)
+					_ = writeManifest()
 				}
 			}
 		} else {
 			markArchiveSkipped(&m)
-			_ = manifest.Write(manifestPath, m)
+			_ = writeManifest()
 		}
 	}
 
 	m.RecomputeCounters()
-	_ = manifest.Write(manifestPath, m)
+	_ = writeManifest()
+	mirrored, bundled, skippedSize := sizeStats(m)
+	endEvent := LogEvent{Event: "run_end", Mode: cfg.Mode, Total: len(m.RepoExecutions)}
+	if interrupted {
+		endEvent.Status = "interrupted"
+	}
+	rlog.write(endEvent)
 
 	return Result{
 		ManifestPath:        manifestPath,
 		BackupRoot:          backupRoot,
 		Deleted:             m.DeletedCount,
 		Failed:              m.FailedCount,
+		SkippedNoBackup:     countStatus(m, manifest.StatusSkippedNoBackup),
+		SkippedProtected:    countStatus(m, manifest.StatusSkippedProtected),
 		ArchiveFailed:       countArchiveFailures(m),
 		ArchiveSkippedSize:  countArchiveSkippedSize(m),
 		Total:               len(m.RepoExecutions),
-		ArchiveCommit:       archiveCommit,
+		ArchiveCommit:       published.Commit,
+		ArchiveBundles:      published.Bundles,
+		ArchiveBytes:        published.Bytes,
+		ArchiveDeduped:      published.Skipped,
 		ArchiveRepo:         cfg.ArchiveRepo,
 		ArchiveBranch:       cfg.ArchiveBranch,
 		ArchiveSkippedRepos: listArchiveSkippedSizeRepos(m),
-	}, nil
+		BytesMirrored:       mirrored,
+		BytesBundled:        bundled,
+		BytesSkippedSize:    skippedSize,
+	}, runErr
+}
+
+// verifyBackup makes sure entry has a mirror and a bundle that verifies,
+// creating the bundle if needed. root is the backup root holding the mirror,
+// which is a standalone backup's root when one was adopted. It returns why the
+// repo must not be deleted, or "" when the backup is good.
+func (e Executor) verifyBackup(ctx context.Context, timeout time.Duration, repo planfile.RepoRecord, entry *manifest.RepoExecutionEntry, root string, step func(stage, line string)) string {
+	if entry.BackupPath == "" {
+		return "no mirror backup recorded"
+	}
+	if _, err := os.Stat(entry.BackupPath); err != nil {
+		return "mirror backup missing: " + entry.BackupPath
+	}
+	if entry.BundlePath != "" {
+		if _, err := os.Stat(entry.BundlePath); err != nil {
+			entry.BundlePath = ""
+		}
+	}
+	if entry.BundlePath == "" {
+		step(StageBundle, "Creating bundle "+repo.FullName+"...")
+		var bundlePath string
+		err := withOpTimeout(ctx, timeout, func(ctx context.Context) (err error) {
+			bundlePath, err = e.Backup.CreateBundle(ctx, repo, root)
+			return err
+		})
+		if err != nil {
+			return "bundle failed: " + err.Error()
+		}
+		entry.BundlePath = bundlePath
+		entry.Compression = manifest.BundleCompression(bundlePath)
+	}
+	v, ok := e.Backup.(BundleVerifier)
+	if !ok {
+		return "backup provider cannot verify bundles"
+	}
+	err := withOpTimeout(ctx, timeout, func(ctx context.Context) error {
+		return v.VerifyBundle(ctx, repo, root, entry.BundlePath)
+	})
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// priorBackup holds the entries of a standalone backup run that
+// --backup-then-delete can verify instead of cloning again.
+type priorBackup struct {
+	root    string
+	entries map[string]manifest.RepoExecutionEntry
+}
+
+// findPriorBackup looks for the latest backup-mode root for fingerprint other
+// than backupRoot and keeps the entries whose mirror and bundle are still on
+// disk.
+func findPriorBackup(fingerprint string, bases []string, backupRoot string) priorBackup {
+	root := findExistingBackupRoot(fingerprint, ModeBackup, bases)
+	if root == "" || filepath.Clean(root) == filepath.Clean(backupRoot) {
+		return priorBackup{}
+	}
+	m, err := manifest.Read(manifest.Path(root))
+	if err != nil || m.Mode != ModeBackup {
+		return priorBackup{}
+	}
+	out := priorBackup{root: root, entries: map[string]manifest.RepoExecutionEntry{}}
+	for _, entry := range m.RepoExecutions {
+		if entry.BackupPath == "" || entry.BundlePath == "" {
+			continue
+		}
+		if _, err := os.Stat(entry.BackupPath); err != nil {
+			continue
+		}
+		if _, err := os.Stat(entry.BundlePath); err != nil {
+			continue
+		}
+		out.entries[entry.FullName] = entry
+	}
+	return out
+}
+
+// adoptBackup records a prior run's backup on entry so it is verified rather
+// than recreated.
+func adoptBackup(entry *manifest.RepoExecutionEntry, prior manifest.RepoExecutionEntry) {
+	entry.BackupPath = prior.BackupPath
+	entry.BundlePath = prior.BundlePath
+	entry.Compression = prior.Compression
+	entry.BrowsablePath = prior.BrowsablePath
+	entry.SnapshotSkipped = prior.SnapshotSkipped
+	entry.LFS = prior.LFS
+	entry.LFSPath = prior.LFSPath
+	entry.Status = manifest.StatusBackupOK
+	entry.Error = ""
+}
+
+// withOpTimeout runs op under its own deadline when timeout is positive and
+// says so in the error when the deadline is what stopped it.
+func withOpTimeout(ctx context.Context, timeout time.Duration, op func(context.Context) error) error {
+	if timeout <= 0 {
+		return op(ctx)
+	}
+	octx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := op(octx)
+	if err != nil && errors.Is(octx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	return err
+}
+
+// repoNotFound reports whether a delete failed because the repo is already
+// gone, e.g. it was deleted out of band after planning.
+// Only GitHub's own answers count: a missing gh binary or a DNS failure
+// ("Could not resolve host") is a real failure.
+func repoNotFound(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "HTTP 404") || strings.Contains(msg, "Could not resolve to a Repository")
+}
+
+// needsArchive reports whether a finished backup entry still has to reach the
+// archive repo on resume.
+func needsArchive(entry manifest.RepoExecutionEntry) bool {
+	switch entry.ArchiveStatus {
+	case "pending", "archive_failed", "archive_push_pending":
+		return true
+	}
+	return false
+}
+
+// FailedEntry reports whether an entry failed: its backup or delete failed,
+// or its archive push did. --only-failed retries exactly these entries, and
+// status lists them.
+func FailedEntry(entry manifest.RepoExecutionEntry) bool {
+	switch entry.Status {
+	case manifest.StatusBackupFailed, manifest.StatusDeleteFailed:
+		return true
+	}
+	return entry.ArchiveStatus == "archive_failed"
 }
 
 func shouldSkipEntry(mode string, entry manifest.RepoExecutionEntry) bool {
@@ -317,6 +730,21 @@ func shouldSkipEntry(mode string, entry manifest.RepoExecutionEntry) bool {
 	return entry.Status == manifest.StatusBackupOK && entry.BundlePath != ""
 }
 
+// mirrorPruned reports whether an entry's mirror was removed on purpose after
+// its bundle and snapshot were recorded (or the snapshot was skipped on
+// purpose), so resume must not re-clone it.
+func mirrorPruned(entry manifest.RepoExecutionEntry) bool {
+	return entry.Status == manifest.StatusBackupOK && entry.BundlePath != "" && (entry.BrowsablePath != "" || entry.SnapshotSkipped)
+}
+
+func pruneMirror(backupRoot, mirror string) error {
+	rel, err := filepath.Rel(backupRoot, mirror)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("mirror %s is outside backup root %s", mirror, backupRoot)
+	}
+	return os.RemoveAll(mirror)
+}
+
 func markArchiveSuccess(m *manifest.ExecutionManifestV1, commit string, targets []manifest.BundleArtifact) {
 	targetsSet := make(map[string]struct{}, len(targets))
 	for _, t := range targets {
@@ -328,13 +756,24 @@ func markArchiveSuccess(m *manifest.ExecutionManifestV1, commit string, targets
 			continue
 		}
 		if entry.Status == manifest.StatusBackupOK && entry.BundlePath != "" {
+			if needsArchive(*entry) {
+				entry.Error = ""
+			}
 			entry.ArchiveStatus = "archived"
 			entry.ArchiveCommit = commit
 		}
 	}
 }
 
+// markArchiveFailure records a failed publish. When the commit was created
+// but not pushed the entries are marked archive_push_pending with that commit,
+// so the next resume knows only the push is left.
 func markArchiveFailure(m *manifest.ExecutionManifestV1, err error, targets []manifest.BundleArtifact) {
+	status, commit := "archive_failed", ""
+	var pushErr *manifest.PushError
+	if errors.As(err, &pushErr) {
+		status, commit = "archive_push_pending", pushErr.Commit
+	}
 	targetsSet := make(map[string]struct{}, len(targets))
 	for _, t := range targets {
 		targetsSet[t.FullName] = struct{}{}
@@ -345,7 +784,8 @@ func markArchiveFailure(m *manifest.ExecutionManifestV1, err error, targets []ma
 			continue
 		}
 		if entry.Status == manifest.StatusBackupOK && entry.BundlePath != "" {
-			entry.ArchiveStatus = "archive_failed"
+			entry.ArchiveStatus = status
+			entry.ArchiveCommit = commit
 			entry.Error = err.Error()
 		}
 	}
@@ -360,35 +800,85 @@ func markArchiveSkipped(m *manifest.ExecutionManifestV1) {
 	}
 }
 
-func requireConfirmation(in io.Reader, out io.Writer, count int, mode string) error {
-	_ = count
-	_ = mode
-	fmt.Fprint(out, "Type ACCEPT or CONFIRM to continue: ")
+func requireConfirmation(in io.Reader, out io.Writer, count int, cfg Config) error {
+	action := "delete"
+	if cfg.Mode == ModeBackup {
+		action = "back up"
+	}
+	fmt.Fprintf(out, "About to %s %d repositories.\n", action, count)
+	if cfg.AssumeYes {
+		fmt.Fprintln(out, "WARNING: confirmation bypassed via --yes")
+		return nil
+	}
+	if cfg.ConfirmationMode == ConfirmCount {
+		fmt.Fprint(out, "Type the number of repositories to continue: ")
+	} else if cfg.ConfirmationPhrase != "" {
+		fmt.Fprintf(out, "Type %s to continue: ", cfg.ConfirmationPhrase)
+	} else {
+		fmt.Fprint(out, "Type ACCEPT or CONFIRM to continue: ")
+	}
 	r := bufio.NewReader(in)
 	text, err := r.ReadString('\n')
 	if err != nil && !errors.Is(err, io.EOF) {
 		return err
 	}
-	input := strings.TrimSpace(strings.ToUpper(text))
+	input := strings.TrimSpace(text)
+	if cfg.ConfirmationMode == ConfirmCount {
+		if input != strconv.Itoa(count) {
+			return fmt.Errorf("confirmation count mismatch: expected %d", count)
+		}
+		return nil
+	}
+	if cfg.ConfirmationPhrase != "" {
+		if input != cfg.ConfirmationPhrase {
+			return errors.New("confirmation phrase mismatch")
+		}
+		return nil
+	}
+	input = strings.ToUpper(input)
 	if input != "ACCEPT" && input != "CONFIRM" {
 		return errors.New("confirmation phrase mismatch")
 	}
 	return nil
 }
 
-func (e Executor) simulate(cfg Config, plan planfile.DeletionPlanV1, backupRoot string) Result {
+func (e Executor) reportProgress(ev ProgressEvent, line string) {
+	fmt.Fprintf(e.Out, "[%d/%d] %s\n", ev.Index, ev.Total, line)
+	if e.Progress != nil {
+		e.Progress(ev)
+	}
+}
+
+func (e Executor) simulate(cfg Config, plan planfile.DeletionPlanV1, backupRoot string, isProtected func(string) bool) Result {
 	archiveRepo := cfg.ArchiveRepo
 	archiveBranch := cfg.ArchiveBranch
 	if archiveBranch == "" {
 		archiveBranch = "main"
 	}
+	skippedProtected := 0
 	for _, repo := range plan.Repos {
+		if cfg.Mode == ModeDelete && isProtected(repo.FullName) {
+			fmt.Fprintf(e.Out, "[dry-run] Would skip protected repo %s\n", repo.FullName)
+			skippedProtected++
+			continue
+		}
 		fmt.Fprintf(e.Out, "[dry-run] Would mirror backup %s to %s\n", repo.FullName, backupRoot)
-		fmt.Fprintf(e.Out, "[dry-run] Would create browsable snapshot for %s\n", repo.FullName)
+		if cfg.IncludeLFS {
+			fmt.Fprintf(e.Out, "[dry-run] Would fetch LFS objects for %s\n", repo.FullName)
+		}
+		if !cfg.NoSnapshot {
+			fmt.Fprintf(e.Out, "[dry-run] Would create browsable snapshot for %s\n", repo.FullName)
+		}
 		if cfg.Mode == ModeBackup {
 			fmt.Fprintf(e.Out, "[dry-run] Would create bundle for %s\n", repo.FullName)
+			if cfg.PruneMirror {
+				fmt.Fprintf(e.Out, "[dry-run] Would prune mirror for %s after bundling\n", repo.FullName)
+			}
 		}
 		if cfg.Mode == ModeDelete {
+			if cfg.BackupThenDelete {
+				fmt.Fprintf(e.Out, "[dry-run] Would create and verify bundle for %s\n", repo.FullName)
+			}
 			fmt.Fprintf(e.Out, "[dry-run] Would delete %s\n", repo.FullName)
 		}
 	}
@@ -403,6 +893,7 @@ func (e Executor) simulate(cfg Config, plan planfile.DeletionPlanV1, backupRoot
 		BackupRoot:          backupRoot,
 		Deleted:             0,
 		Failed:              0,
+		SkippedProtected:    skippedProtected,
 		ArchiveFailed:       0,
 		ArchiveSkippedSize:  0,
 		Total:               len(plan.Repos),
@@ -415,7 +906,17 @@ func (e Executor) simulate(cfg Config, plan planfile.DeletionPlanV1, backupRoot
 func countArchiveFailures(m manifest.ExecutionManifestV1) int {
 	count := 0
 	for _, entry := range m.RepoExecutions {
-		if entry.ArchiveStatus == "archive_failed" {
+		if entry.ArchiveStatus == "archive_failed" || entry.ArchiveStatus == "archive_push_pending" {
+			count++
+		}
+	}
+	return count
+}
+
+func countStatus(m manifest.ExecutionManifestV1, status manifest.RepoExecutionStatus) int {
+	count := 0
+	for _, entry := range m.RepoExecutions {
+		if entry.Status == status {
 			count++
 		}
 	}
@@ -443,6 +944,44 @@ func listArchiveSkippedSizeRepos(m manifest.ExecutionManifestV1) []string {
 	return out
 }
 
+// sizeStats totals the on-disk mirror bytes, the bytes of bundles published to
+// the archive repo, and the bytes of bundles held back by the size limit.
+func sizeStats(m manifest.ExecutionManifestV1) (mirrored, bundled, skippedSize int64) {
+	for _, entry := range m.RepoExecutions {
+		if entry.BackupPath != "" {
+			mirrored += PathSize(entry.BackupPath)
+		}
+		if entry.BundlePath == "" {
+			continue
+		}
+		switch entry.ArchiveStatus {
+		case "archived":
+			bundled += PathSize(entry.BundlePath)
+		case "archive_skipped_size_limit":
+			skippedSize += PathSize(entry.BundlePath)
+		}
+	}
+	return mirrored, bundled, skippedSize
+}
+
+// PathSize is the total size of the regular files under path (or of path
+// itself when it is a file); unreadable entries are skipped.
+func PathSize(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
+
 func filterArchiveBundlesBySize(backupRoot string, bundles []manifest.BundleArtifact, m *manifest.ExecutionManifestV1, maxBytes int64, out io.Writer) ([]manifest.BundleArtifact, []string) {
 	eligible := make([]manifest.BundleArtifact, 0, len(bundles))
 	skipped := make([]string, 0)
@@ -513,23 +1052,33 @@ func moveFile(src, dst string) error {
 	return os.Remove(src)
 }
 
-func loadOrCreateManifest(cfg Config, plan planfile.DeletionPlanV1, backupRoot, manifestPath string, now func() time.Time) (manifest.ExecutionManifestV1, error) {
-	if _, err := os.Stat(manifestPath); err == nil {
-		if !cfg.Resume {
-			return manifest.ExecutionManifestV1{}, errors.New("manifest already exists and --resume=false")
-		}
-		m, err := manifest.Read(manifestPath)
-		if err != nil {
-			return manifest.ExecutionManifestV1{}, err
+// readManifest loads the manifest a resumed run continues from, reporting
+// false when the backup root has none yet. It never writes.
+func readManifest(cfg Config, plan planfile.DeletionPlanV1, manifestPath string) (manifest.ExecutionManifestV1, bool, error) {
+	if _, err := os.Stat(manifestPath); err != nil {
+		if cfg.OnlyFailed {
+			return manifest.ExecutionManifestV1{}, false, errors.New("--only-failed requires an existing manifest to resume")
 		}
-		if m.PlanFingerprint != plan.Fingerprint {
-			return manifest.ExecutionManifestV1{}, errors.New("manifest plan fingerprint mismatch")
-		}
-		if m.Mode != "" && cfg.Mode != "" && m.Mode != cfg.Mode {
-			return manifest.ExecutionManifestV1{}, errors.New("manifest mode mismatch")
-		}
-		return m, nil
+		return manifest.ExecutionManifestV1{}, false, nil
+	}
+	if !cfg.Resume {
+		return manifest.ExecutionManifestV1{}, false, errors.New("manifest already exists and --resume=false")
+	}
+	m, err := manifest.Read(manifestPath)
+	if err != nil {
+		return manifest.ExecutionManifestV1{}, false, err
+	}
+	if m.PlanFingerprint != plan.Fingerprint {
+		return manifest.ExecutionManifestV1{}, false, errors.New("manifest plan fingerprint mismatch")
 	}
+	if m.Mode != "" && cfg.Mode != "" && m.Mode != cfg.Mode {
+		return manifest.ExecutionManifestV1{}, false, errors.New("manifest mode mismatch")
+	}
+	return m, true, nil
+}
+
+// createManifest writes a fresh manifest for plan.
+func createManifest(cfg Config, plan planfile.DeletionPlanV1, backupRoot, manifestPath string, now func() time.Time) (manifest.ExecutionManifestV1, error) {
 	m := manifest.New(cfg.PlanPath, backupRoot, plan, now(), manifest.NewOptions{
 		Mode:          cfg.Mode,
 		ArchiveRepo:   cfg.ArchiveRepo,
@@ -541,44 +1090,63 @@ func loadOrCreateManifest(cfg Config, plan planfile.DeletionPlanV1, backupRoot,
 	return m, nil
 }
 
-func (e Executor) resolveBackupRoot(fingerprint, explicit string, resume bool) (string, error) {
-	if explicit != "" {
-		return explicit, nil
+func (e Executor) resolveBackupRoot(fingerprint string, cfg Config) (string, error) {
+	if cfg.BackupDir != "" {
+		return cfg.BackupDir, nil
 	}
-	if resume {
-		if found := findExistingBackupRoot(fingerprint); found != "" {
+	if cfg.Resume {
+		if found := findExistingBackupRoot(fingerprint, cfg.Mode, backupSearchBases(cfg)); found != "" {
 			return found, nil
 		}
 	}
+	if cfg.DefaultBackupBase != "" {
+		return app.BackupRootIn(cfg.DefaultBackupBase, e.Now()), nil
+	}
 	return app.DefaultBackupRoot(e.Now())
 }
 
-func findExistingBackupRoot(fingerprint string) string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return ""
+// backupSearchBases lists where earlier runs' backup roots are looked for.
+func backupSearchBases(cfg Config) []string {
+	bases := make([]string, 0, 2)
+	if home, err := os.UserHomeDir(); err == nil {
+		bases = append(bases, home)
 	}
-	entries, err := os.ReadDir(home)
-	if err != nil {
-		return ""
+	if cfg.DefaultBackupBase != "" {
+		bases = append(bases, cfg.DefaultBackupBase)
 	}
-	candidates := make([]string, 0)
-	for _, entry := range entries {
-		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "gh-manager-archive-") {
-			continue
+	return append(bases, cfg.ResumeSearchDirs...)
+}
+
+// findExistingBackupRoot returns the most recently updated root whose
+// manifest is for fingerprint and, when mode is set, from a run of that mode.
+func findExistingBackupRoot(fingerprint, mode string, bases []string) string {
+	best := ""
+	var bestMod time.Time
+	consider := func(root string) {
+		path := manifest.Path(root)
+		m, err := manifest.Read(path)
+		if err != nil || m.PlanFingerprint != fingerprint {
+			return
 		}
-		root := filepath.Join(home, entry.Name())
-		m, err := manifest.Read(manifest.Path(root))
+		if mode != "" && m.Mode != "" && m.Mode != mode {
+			return
+		}
+		st, err := os.Stat(path)
 		if err != nil {
-			continue
+			return
 		}
-		if m.PlanFingerprint == fingerprint {
-			candidates = append(candidates, root)
+		if best == "" || st.ModTime().After(bestMod) || (st.ModTime().Equal(bestMod) && root > best) {
+			best = root
+			bestMod = st.ModTime()
 		}
 	}
-	if len(candidates) == 0 {
-		return ""
+	seen := map[string]bool{}
+	for _, base := range bases {
+		if base != "" && !seen[base] {
+			seen[base] = true
+			consider(base)
+		}
 	}
-	sort.Strings(candidates)
-	return candidates[len(candidates)-1]
+	walkBackupRoots(bases, consider)
+	return best
 }
@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
@@ -51,13 +52,21 @@ type fakeBackup struct {
 	failFor    map[string]error
 	snapFail   map[string]error
 	bundleFail map[string]error
+	lfsRepos   map[string]bool
+	verifyFail map[string]error
+	hang       map[string]bool
 	mirrorN    int
 	snapshotN  int
 	bundleN    int
+	lfsN       int
 }
 
-func (f *fakeBackup) MirrorBackup(_ context.Context, repo planfile.RepoRecord, _ string) (string, error) {
+func (f *fakeBackup) MirrorBackup(ctx context.Context, repo planfile.RepoRecord, _ string) (string, error) {
 	f.mirrorN++
+	if f.hang[repo.FullName] {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
 	if err := f.failFor[repo.FullName]; err != nil {
 		return "", err
 	}
@@ -90,21 +99,34 @@ func (f *fakeBackup) CreateBrowsableSnapshot(_ context.Context, repo planfile.Re
 	return "/tmp/" + strings.ReplaceAll(repo.Name, "/", "_"), nil
 }
 
+func (f *fakeBackup) FetchLFS(_ context.Context, repo planfile.RepoRecord, root string) (string, bool, error) {
+	f.lfsN++
+	if !f.lfsRepos[repo.FullName] {
+		return "", false, nil
+	}
+	return filepath.Join(root, "lfs", repo.Name), true, nil
+}
+
+func (f *fakeBackup) VerifyBundle(_ context.Context, repo planfile.RepoRecord, _, _ string) error {
+	return f.verifyFail[repo.FullName]
+}
+
 type fakeArchive struct {
 	commit string
 	err    error
 	calls  int
 }
 
-func (f *fakeArchive) PublishBundles(_ context.Context, _ string, _ string, _ string, _ []manifest.BundleArtifact, _ string) (string, error) {
+func (f *fakeArchive) PublishBundles(_ context.Context, _ string, _ string, _ string, bundles []manifest.BundleArtifact, _ string) (manifest.PublishResult, error) {
 	f.calls++
 	if f.err != nil {
-		return "", f.err
+		return manifest.PublishResult{}, f.err
 	}
-	if f.commit != "" {
-		return f.commit, nil
+	commit := f.commit
+	if commit == "" {
+		commit = "abc123"
 	}
-	return "abc123", nil
+	return manifest.PublishResult{Commit: commit, Bundles: len(bundles)}, nil
 }
 
 func TestExecuteDeleteDryRunHasNoSideEffects(t *testing.T) {
@@ -154,26 +176,190 @@ func TestExecuteBackupDryRunHasNoSideEffects(t *testing.T) {
 }
 
 func TestRequireConfirmation(t *testing.T) {
-	if err := requireConfirmation(strings.NewReader("ACCEPT\n"), &strings.Builder{}, 2, ModeDelete); err != nil {
+	if err := requireConfirmation(strings.NewReader("ACCEPT\n"), &strings.Builder{}, 2, Config{Mode: ModeDelete}); err != nil {
 		t.Fatalf("expected success, got %v", err)
 	}
-	if err := requireConfirmation(strings.NewReader("CONFIRM\n"), &strings.Builder{}, 2, ModeBackup); err != nil {
+	if err := requireConfirmation(strings.NewReader("CONFIRM\n"), &strings.Builder{}, 2, Config{Mode: ModeBackup}); err != nil {
 		t.Fatalf("expected confirm success, got %v", err)
 	}
 }
 
-func TestLoadOrCreateManifest(t *testing.T) {
+func TestRequireConfirmationCountMode(t *testing.T) {
+	cfg := Config{Mode: ModeDelete, ConfirmationMode: ConfirmCount}
+	if err := requireConfirmation(strings.NewReader("42\n"), &strings.Builder{}, 42, cfg); err != nil {
+		t.Fatalf("expected count success, got %v", err)
+	}
+	if err := requireConfirmation(strings.NewReader("41\n"), &strings.Builder{}, 42, cfg); err == nil {
+		t.Fatal("expected count mismatch error")
+	}
+	if err := requireConfirmation(strings.NewReader("CONFIRM\n"), &strings.Builder{}, 42, cfg); err == nil {
+		t.Fatal("expected phrase to be rejected in count mode")
+	}
+}
+
+func TestRequireConfirmationCustomPhrase(t *testing.T) {
+	cfg := Config{Mode: ModeDelete, ConfirmationPhrase: "delete everything"}
+	if err := requireConfirmation(strings.NewReader("delete everything\n"), &strings.Builder{}, 1, cfg); err != nil {
+		t.Fatalf("expected custom phrase success, got %v", err)
+	}
+	if err := requireConfirmation(strings.NewReader("ACCEPT\n"), &strings.Builder{}, 1, cfg); err == nil {
+		t.Fatal("expected default phrase to be rejected when custom phrase is set")
+	}
+}
+
+func TestRequireConfirmationAssumeYes(t *testing.T) {
+	out := &strings.Builder{}
+	if err := requireConfirmation(strings.NewReader(""), out, 3, Config{Mode: ModeDelete, AssumeYes: true}); err != nil {
+		t.Fatalf("expected bypass success, got %v", err)
+	}
+	if !strings.Contains(out.String(), "confirmation bypassed via --yes") {
+		t.Fatalf("expected bypass notice, got: %s", out.String())
+	}
+	if strings.Contains(out.String(), "to continue:") {
+		t.Fatalf("expected no prompt when bypassed, got: %s", out.String())
+	}
+}
+
+func TestExecuteDryRunSkipsConfirmation(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, now)
+	plan.Fingerprint = "fp-dry-noprompt"
+	out := &strings.Builder{}
+	ex := Executor{GH: &fakeGH{}, Backup: &fakeBackup{}, Now: func() time.Time { return now }, In: strings.NewReader(""), Out: out}
+	if _, err := ex.Execute(context.Background(), Config{PlanPath: "plan.json", BackupDir: t.TempDir(), Mode: ModeDelete, DryRun: true}, plan); err != nil {
+		t.Fatalf("dry-run should not require confirmation: %v", err)
+	}
+	if strings.Contains(out.String(), "to continue:") {
+		t.Fatalf("expected no prompt in dry-run, got: %s", out.String())
+	}
+}
+
+func TestExecuteCountMismatchAbortsBeforeSideEffects(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}, {Owner: "alice", Name: "r2", FullName: "alice/r2"}}, now)
+	plan.Fingerprint = "fp-count"
+	gh := &fakeGH{}
+	bk := &fakeBackup{bundlePath: map[string]string{}}
+	ex := Executor{GH: gh, Backup: bk, Now: func() time.Time { return now }, In: strings.NewReader("3\n"), Out: &strings.Builder{}}
+	_, err := ex.Execute(context.Background(), Config{PlanPath: "plan.json", Resume: true, BackupDir: t.TempDir(), Mode: ModeDelete, ConfirmationMode: ConfirmCount}, plan)
+	if err == nil || !strings.Contains(err.Error(), "count mismatch") {
+		t.Fatalf("expected count mismatch error, got %v", err)
+	}
+	if len(gh.deleted) != 0 || bk.mirrorN != 0 {
+		t.Fatalf("expected no side effects: deleted=%v mirror=%d", gh.deleted, bk.mirrorN)
+	}
+}
+
+func TestClassifyRisk(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, -1, 0).Format(time.RFC3339)
+	stale := now.AddDate(-2, 0, 0).Format(time.RFC3339)
+	cases := []struct {
+		repo planfile.RepoRecord
+		want Risk
+	}{
+		{planfile.RepoRecord{UpdatedAt: recent}, RiskHigh},
+		{planfile.RepoRecord{UpdatedAt: recent, IsFork: true}, RiskLow},
+		{planfile.RepoRecord{UpdatedAt: recent, IsArchived: true}, RiskLow},
+		{planfile.RepoRecord{UpdatedAt: stale}, RiskMedium},
+		{planfile.RepoRecord{}, RiskMedium},
+	}
+	for _, c := range cases {
+		if got := ClassifyRisk(c.repo, now, 0); got != c.want {
+			t.Fatalf("ClassifyRisk(%+v)=%s want %s", c.repo, got, c.want)
+		}
+	}
+	if got := ClassifyRisk(planfile.RepoRecord{UpdatedAt: recent}, now, 7*24*time.Hour); got != RiskMedium {
+		t.Fatalf("expected a shorter window to downgrade, got %s", got)
+	}
+}
+
+func TestExecuteEscalatesConfirmationForHighRiskDeletes(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{
+		{Owner: "alice", Name: "active", FullName: "alice/active", UpdatedAt: now.AddDate(0, 0, -3).Format(time.RFC3339)},
+		{Owner: "alice", Name: "fork", FullName: "alice/fork", IsFork: true},
+	}, now)
+	plan.Fingerprint = "fp-risk"
+	gh := &fakeGH{}
+	out := &strings.Builder{}
+	ex := Executor{GH: gh, Backup: &fakeBackup{}, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: out}
+	_, err := ex.Execute(context.Background(), Config{PlanPath: "plan.json", BackupDir: t.TempDir(), Mode: ModeDelete}, plan)
+	if err == nil || !strings.Contains(err.Error(), "count mismatch") {
+		t.Fatalf("expected escalated count gate to reject the phrase, got %v", err)
+	}
+	for _, want := range []string{"Risk: 1 high (active source repos), 0 medium (idle or unknown activity), 1 low (archived/forks)", "high: alice/active", "Confirmation escalated"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if len(gh.deleted) != 0 {
+		t.Fatalf("expected no deletes, got %v", gh.deleted)
+	}
+
+	ex.In = strings.NewReader("2\n")
+	ex.Out = &strings.Builder{}
+	if _, err := ex.Execute(context.Background(), Config{PlanPath: "plan.json", BackupDir: t.TempDir(), Mode: ModeDelete}, plan); err != nil {
+		t.Fatalf("expected count confirmation to pass: %v", err)
+	}
+	if len(gh.deleted) != 2 {
+		t.Fatalf("expected both repos deleted, got %v", gh.deleted)
+	}
+}
+
+func TestExecuteRiskCountsOnlyReposItWillDelete(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -3).Format(time.RFC3339)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{
+		{Owner: "alice", Name: "keep", FullName: "alice/keep", UpdatedAt: recent},
+		{Owner: "alice", Name: "done", FullName: "alice/done", UpdatedAt: recent},
+		{Owner: "alice", Name: "fork", FullName: "alice/fork", IsFork: true},
+	}, now)
+	plan.Fingerprint = "fp-risk-resume"
+	root := t.TempDir()
+	m := manifest.New("plan.json", root, plan, now, manifest.NewOptions{Mode: ModeDelete})
+	for i := range m.RepoExecutions {
+		if m.RepoExecutions[i].FullName == "alice/done" {
+			m.RepoExecutions[i].Status = manifest.StatusDeleted
+		}
+	}
+	if err := manifest.Write(manifest.Path(root), m); err != nil {
+		t.Fatal(err)
+	}
+	gh := &fakeGH{}
+	out := &strings.Builder{}
+	ex := Executor{GH: gh, Backup: &fakeBackup{}, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: out}
+	cfg := Config{PlanPath: "plan.json", BackupDir: root, Mode: ModeDelete, Resume: true, ProtectedRepos: []string{"alice/keep"}}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("expected the phrase to suffice without high-risk deletes: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Risk: 0 high (active source repos), 0 medium (idle or unknown activity), 1 low (archived/forks)") || strings.Contains(out.String(), "Confirmation escalated") {
+		t.Fatalf("expected protected and already-deleted repos left out of the breakdown:\n%s", out.String())
+	}
+	if len(gh.deleted) != 1 || gh.deleted[0] != "alice/fork" {
+		t.Fatalf("expected only the fork deleted, got %v", gh.deleted)
+	}
+}
+
+func TestReadAndCreateManifest(t *testing.T) {
 	d := t.TempDir()
 	p := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{FullName: "alice/r1"}}, time.Now())
 	p.Fingerprint = "fp"
-	m, err := loadOrCreateManifest(Config{PlanPath: "plan.json", Resume: true, Mode: ModeDelete}, p, d, manifest.Path(d), time.Now)
-	if err != nil {
+	cfg := Config{PlanPath: "plan.json", Resume: true, Mode: ModeDelete}
+	if _, ok, err := readManifest(cfg, p, manifest.Path(d)); err != nil || ok {
+		t.Fatalf("expected no manifest yet: ok=%v err=%v", ok, err)
+	}
+	if _, err := createManifest(cfg, p, d, manifest.Path(d), time.Now); err != nil {
 		t.Fatalf("create: %v", err)
 	}
+	m, ok, err := readManifest(cfg, p, manifest.Path(d))
+	if err != nil || !ok {
+		t.Fatalf("read: ok=%v err=%v", ok, err)
+	}
 	if m.PlanFingerprint != "fp" {
 		t.Fatalf("bad fingerprint")
 	}
-	if _, err := loadOrCreateManifest(Config{PlanPath: "plan.json", Resume: false, Mode: ModeDelete}, p, d, manifest.Path(d), time.Now); err == nil {
+	if _, _, err := readManifest(Config{PlanPath: "plan.json", Resume: false, Mode: ModeDelete}, p, manifest.Path(d)); err == nil {
 		t.Fatal("expected resume=false failure")
 	}
 }
@@ -211,6 +397,147 @@ func TestExecuteDeleteSuccessWithRetryAndResumeSkip(t *testing.T) {
 	}
 }
 
+func TestExecuteDeleteTreatsNotFoundAsDeleted(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}, {Owner: "alice", Name: "r2", FullName: "alice/r2"}}, now)
+	plan.Fingerprint = "fp-gone"
+	gh := &fakeGH{failFor: map[string]error{
+		"alice/r1": errors.New("HTTP 404: Not Found (https://api.github.com/repos/alice/r1)"),
+		"alice/r2": errors.New("HTTP 403: Must have admin rights to Repository."),
+	}}
+	out := &strings.Builder{}
+	ex := Executor{GH: gh, Backup: &fakeBackup{}, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: out}
+	res, err := ex.Execute(context.Background(), Config{PlanPath: "plan.json", BackupDir: t.TempDir(), Mode: ModeDelete, MaxDeleteRetries: 3}, plan)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if res.Deleted != 1 || res.Failed != 1 {
+		t.Fatalf("404 should count as deleted and 403 as failed: %+v", res)
+	}
+	if gh.attempts["alice/r1"] != 1 {
+		t.Fatalf("a missing repo should not be retried, got %d attempts", gh.attempts["alice/r1"])
+	}
+	if !strings.Contains(out.String(), "Already deleted alice/r1 (not found)") {
+		t.Fatalf("missing already-deleted line:\n%s", out.String())
+	}
+}
+
+func TestRepoNotFound(t *testing.T) {
+	for msg, want := range map[string]bool{
+		"HTTP 404: Not Found (https://api.github.com/repos/alice/r1)":         true,
+		"GraphQL: Could not resolve to a Repository with the name 'alice/r1'": true,
+		`exec: "gh": executable file not found in $PATH`:                      false,
+		"error connecting to api.github.com: Could not resolve host":          false,
+		"HTTP 403: Must have admin rights to Repository.":                     false,
+	} {
+		if got := repoNotFound(errors.New(msg)); got != want {
+			t.Errorf("repoNotFound(%q) = %t, want %t", msg, got, want)
+		}
+	}
+}
+
+func TestExecuteBackupThenDeleteSkipsUnverifiedRepos(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{
+		{Owner: "alice", Name: "r1", FullName: "alice/r1"},
+		{Owner: "alice", Name: "r2", FullName: "alice/r2"},
+		{Owner: "alice", Name: "r3", FullName: "alice/r3"},
+	}, now)
+	plan.Fingerprint = "fp-verify"
+
+	backupRoot := t.TempDir()
+	mirrors := map[string]string{}
+	for _, name := range []string{"alice/r1", "alice/r2"} {
+		dir := filepath.Join(backupRoot, "repos", strings.ReplaceAll(name, "/", "_")+".git")
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+		mirrors[name] = dir
+	}
+	mirrors["alice/r3"] = filepath.Join(backupRoot, "repos", "gone.git")
+	gh := &fakeGH{}
+	bk := &fakeBackup{paths: mirrors, verifyFail: map[string]error{"alice/r2": errors.New("bundle verify failed: exit status 1")}}
+	out := &strings.Builder{}
+	ex := Executor{GH: gh, Backup: bk, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: out}
+	res, err := ex.Execute(context.Background(), Config{PlanPath: "plan.json", BackupDir: backupRoot, Mode: ModeDelete, MaxDeleteRetries: 1, BackupThenDelete: true}, plan)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if !slices.Equal(gh.deleted, []string{"alice/r1"}) {
+		t.Fatalf("only the verified repo should be deleted: %#v", gh.deleted)
+	}
+	if res.Deleted != 1 || res.SkippedNoBackup != 2 || res.Failed != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if bk.bundleN != 2 {
+		t.Fatalf("bundles should be created only for repos with a mirror, got %d", bk.bundleN)
+	}
+	m, err := manifest.Read(res.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range m.RepoExecutions[1:] {
+		if entry.Status != manifest.StatusSkippedNoBackup || entry.Error == "" {
+			t.Fatalf("expected %s to be skipped_no_backup with a reason: %+v", entry.FullName, entry)
+		}
+	}
+	if !strings.Contains(out.String(), "Skipping delete of alice/r2: bundle verify failed") {
+		t.Fatalf("missing skip line:\n%s", out.String())
+	}
+}
+
+func TestExecuteBackupThenDeleteVerifiesStandaloneBackup(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, now)
+	plan.Fingerprint = "fp-standalone"
+	base := t.TempDir()
+	artifacts := t.TempDir()
+	mirror := filepath.Join(artifacts, "alice_r1.git")
+	if err := os.MkdirAll(mirror, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(artifacts, "alice_r1.bundle")
+	if err := os.WriteFile(bundle, []byte("bundle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bk := &fakeBackup{paths: map[string]string{"alice/r1": mirror}, bundlePath: map[string]string{"alice/r1": bundle}}
+
+	backupRun := Executor{Backup: bk, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	if _, err := backupRun.Execute(context.Background(), Config{PlanPath: "plan.json", Mode: ModeBackup, Resume: true, NoArchive: true, DefaultBackupBase: base}, plan); err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	if bk.mirrorN != 1 || bk.bundleN != 1 {
+		t.Fatalf("expected the backup run to clone and bundle once: mirror=%d bundle=%d", bk.mirrorN, bk.bundleN)
+	}
+
+	gh := &fakeGH{}
+	out := &strings.Builder{}
+	later := now.Add(time.Hour)
+	deleteRun := Executor{GH: gh, Backup: bk, Now: func() time.Time { return later }, In: strings.NewReader("ACCEPT\n"), Out: out}
+	res, err := deleteRun.Execute(context.Background(), Config{PlanPath: "plan.json", Mode: ModeDelete, Resume: true, BackupThenDelete: true, MaxDeleteRetries: 1, DefaultBackupBase: base}, plan)
+	if err != nil {
+		t.Fatalf("execute: %v\n%s", err, out.String())
+	}
+	if !slices.Equal(gh.deleted, []string{"alice/r1"}) || res.SkippedNoBackup != 0 {
+		t.Fatalf("expected the verified repo deleted: deleted=%v res=%+v", gh.deleted, res)
+	}
+	if bk.mirrorN != 1 || bk.bundleN != 1 || bk.snapshotN != 1 {
+		t.Fatalf("expected no new clone, bundle or snapshot: mirror=%d bundle=%d snapshot=%d", bk.mirrorN, bk.bundleN, bk.snapshotN)
+	}
+	if !strings.Contains(out.String(), "Using existing backup of alice/r1") {
+		t.Fatalf("expected the reused backup to be reported:\n%s", out.String())
+	}
+	m, err := manifest.Read(res.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := m.RepoExecutions[0]; e.Status != manifest.StatusDeleted || e.BackupPath != mirror || e.BundlePath != bundle {
+		t.Fatalf("expected the delete manifest to point at the standalone backup: %+v", e)
+	}
+}
+
 func TestExecuteBackupSuccessWithArchive(t *testing.T) {
 	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
 	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1", UpdatedAt: now.Format(time.RFC3339)}}, now)
@@ -321,6 +648,52 @@ func TestExecuteBackupArchivePublishFailureCounted(t *testing.T) {
 	}
 }
 
+func TestExecuteBackupResumePushesPendingArchiveCommit(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, now)
+	plan.Fingerprint = "fp-archive-push"
+	backupRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(backupRoot, "r1.bundle"), []byte("bundle"), 0o644); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+	archive := &fakeArchive{err: &manifest.PushError{Commit: "c0ffee", Err: errors.New("connection reset")}}
+	ex := Executor{
+		RepoMgr: &fakeGH{},
+		Backup:  &fakeBackup{bundlePath: map[string]string{"alice/r1": filepath.Join(backupRoot, "r1.bundle")}},
+		Archive: archive,
+		Now:     func() time.Time { return now },
+		In:      strings.NewReader("ACCEPT\n"),
+		Out:     &strings.Builder{},
+	}
+	cfg := Config{PlanPath: "plan.json", Resume: true, BackupDir: backupRoot, Mode: ModeBackup, ArchiveRepo: "alice/gh-manager-archive"}
+	res, err := ex.Execute(context.Background(), cfg, plan)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	m, err := manifest.Read(filepath.Join(backupRoot, "manifest.json"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if e := m.RepoExecutions[0]; e.ArchiveStatus != "archive_push_pending" || e.ArchiveCommit != "c0ffee" || res.ArchiveFailed != 1 {
+		t.Fatalf("expected push-pending entry, got %+v (archiveFailed=%d)", e, res.ArchiveFailed)
+	}
+
+	archive.err = nil
+	archive.commit = "c0ffee"
+	ex.In = strings.NewReader("ACCEPT\n")
+	res, err = ex.Execute(context.Background(), cfg, plan)
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if archive.calls != 2 || res.ArchiveFailed != 0 {
+		t.Fatalf("expected resume to publish again, calls=%d res=%+v", archive.calls, res)
+	}
+	m, _ = manifest.Read(filepath.Join(backupRoot, "manifest.json"))
+	if e := m.RepoExecutions[0]; e.ArchiveStatus != "archived" || e.Error != "" {
+		t.Fatalf("expected archived entry after resume, got %+v", e)
+	}
+}
+
 func TestExecuteBackupArchiveSkipsOversizedBundles(t *testing.T) {
 	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
 	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "small", FullName: "alice/small"}, {Owner: "alice", Name: "big", FullName: "alice/big"}}, now)
@@ -397,4 +770,542 @@ func TestExecuteBackupArchiveSkipsOversizedBundles(t *testing.T) {
 	if !sawSkipped {
 		t.Fatal("did not find skipped entry")
 	}
+	if res.BytesBundled != 2 {
+		t.Fatalf("expected 2 bundled bytes, got %d", res.BytesBundled)
+	}
+	if res.BytesSkippedSize != archiveMaxBundleSizeBytes+2 {
+		t.Fatalf("expected %d size-skipped bytes, got %d", archiveMaxBundleSizeBytes+2, res.BytesSkippedSize)
+	}
+}
+
+func TestExecuteBackupReportsMirroredBytes(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, now)
+	plan.Fingerprint = "fp-bytes"
+	backupRoot := t.TempDir()
+
+	mirror := filepath.Join(backupRoot, "r1.git")
+	if err := os.MkdirAll(filepath.Join(mirror, "objects"), 0o755); err != nil {
+		t.Fatalf("mkdir mirror: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(mirror, "HEAD"), []byte("ref: main\n"), 0o644); err != nil {
+		t.Fatalf("write HEAD: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(mirror, "objects", "pack"), make([]byte, 100), 0o644); err != nil {
+		t.Fatalf("write pack: %v", err)
+	}
+	ex := Executor{
+		Backup: &fakeBackup{paths: map[string]string{"alice/r1": mirror}},
+		Now:    func() time.Time { return now },
+		In:     strings.NewReader("ACCEPT\n"),
+		Out:    &strings.Builder{},
+	}
+	res, err := ex.Execute(context.Background(), Config{
+		PlanPath:  "plan.json",
+		Resume:    true,
+		BackupDir: backupRoot,
+		Mode:      ModeBackup,
+		NoArchive: true,
+	}, plan)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if res.BytesMirrored != 110 {
+		t.Fatalf("expected 110 mirrored bytes, got %d", res.BytesMirrored)
+	}
+	if res.BytesBundled != 0 || res.BytesSkippedSize != 0 {
+		t.Fatalf("expected no bundle bytes, got bundled=%d skipped=%d", res.BytesBundled, res.BytesSkippedSize)
+	}
+}
+
+func TestExecutePerRepoTimeoutFailsHungRepoAndResumeRetries(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}, {Owner: "alice", Name: "r2", FullName: "alice/r2"}}, now)
+	plan.Fingerprint = "fp-timeout"
+	backupRoot := t.TempDir()
+	gh := &fakeGH{}
+	bk := &fakeBackup{hang: map[string]bool{"alice/r1": true}}
+	ex := Executor{GH: gh, Backup: bk, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	cfg := Config{PlanPath: "plan.json", Resume: true, BackupDir: backupRoot, Mode: ModeDelete, MaxDeleteRetries: 1, PerRepoTimeout: 20 * time.Millisecond}
+	res, err := ex.Execute(context.Background(), cfg, plan)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if res.Deleted != 1 || res.Failed != 1 || !slices.Equal(gh.deleted, []string{"alice/r2"}) {
+		t.Fatalf("hung repo should fail and the run continue: %+v deleted=%v", res, gh.deleted)
+	}
+	m, err := manifest.Read(res.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := m.RepoExecutions[0]
+	if entry.Status != manifest.StatusBackupFailed || !strings.Contains(entry.Error, "timed out after 20ms") {
+		t.Fatalf("expected a timeout error in the manifest: %+v", entry)
+	}
+
+	bk.hang = nil
+	ex.In = strings.NewReader("ACCEPT\n")
+	res, err = ex.Execute(context.Background(), cfg, plan)
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if res.Deleted != 2 || res.Failed != 0 {
+		t.Fatalf("resume should retry the timed-out repo: %+v", res)
+	}
+}
+
+func TestExecuteOnlyFailedRetriesFailedEntries(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	repos := []planfile.RepoRecord{
+		{Owner: "alice", Name: "r1", FullName: "alice/r1"},
+		{Owner: "alice", Name: "r2", FullName: "alice/r2"},
+		{Owner: "alice", Name: "r3", FullName: "alice/r3"},
+		{Owner: "alice", Name: "r4", FullName: "alice/r4"},
+	}
+	plan := planfile.New("alice", "github.com", "test", repos, now)
+	plan.Fingerprint = "fp-only-failed"
+	backupRoot := t.TempDir()
+	gh := &fakeGH{}
+	ex := Executor{GH: gh, Backup: &fakeBackup{}, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	cfg := Config{PlanPath: "plan.json", Resume: true, OnlyFailed: true, BackupDir: backupRoot, Mode: ModeDelete, MaxDeleteRetries: 1}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err == nil || !strings.Contains(err.Error(), "requires an existing manifest") {
+		t.Fatalf("expected missing manifest error, got %v", err)
+	}
+
+	m := manifest.New("plan.json", backupRoot, plan, now, manifest.NewOptions{Mode: ModeDelete})
+	m.RepoExecutions[1].Status = manifest.StatusBackupFailed
+	m.RepoExecutions[2].Status = manifest.StatusDeleteFailed
+	m.RepoExecutions[2].BackupPath = "/tmp/r3.git"
+	m.RepoExecutions[3].Status = manifest.StatusDeleted
+	if err := manifest.Write(manifest.Path(backupRoot), m); err != nil {
+		t.Fatal(err)
+	}
+	ex.In = strings.NewReader("ACCEPT\n")
+	res, err := ex.Execute(context.Background(), cfg, plan)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if !slices.Equal(gh.deleted, []string{"alice/r2", "alice/r3"}) {
+		t.Fatalf("expected only failed entries retried, deleted=%v", gh.deleted)
+	}
+	got, err := manifest.Read(res.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RepoExecutions[0].Status != manifest.StatusPending {
+		t.Fatalf("pending entry should be left alone: %+v", got.RepoExecutions[0])
+	}
+
+	plan.Fingerprint = "fp-other"
+	ex.In = strings.NewReader("ACCEPT\n")
+	if _, err := ex.Execute(context.Background(), cfg, plan); err == nil || !strings.Contains(err.Error(), "fingerprint mismatch") {
+		t.Fatalf("expected fingerprint mismatch, got %v", err)
+	}
+}
+
+func TestExecuteSkipsProtectedReposOnDelete(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{
+		{Owner: "alice", Name: "scratch", FullName: "alice/scratch"},
+		{Owner: "alice", Name: "prod-api", FullName: "alice/prod-api"},
+	}, now)
+	plan.Fingerprint = "fp-protected"
+	gh := &fakeGH{}
+	out := &strings.Builder{}
+	ex := Executor{GH: gh, Backup: &fakeBackup{}, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: out}
+	cfg := Config{PlanPath: "plan.json", BackupDir: t.TempDir(), Mode: ModeDelete, MaxDeleteRetries: 1, ProtectedRepos: []string{"alice/prod-*"}}
+	res, err := ex.Execute(context.Background(), cfg, plan)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if !slices.Equal(gh.deleted, []string{"alice/scratch"}) {
+		t.Fatalf("protected repo must not be deleted: %v", gh.deleted)
+	}
+	if res.SkippedProtected != 1 || !strings.Contains(out.String(), "skipping protected repo alice/prod-api") {
+		t.Fatalf("expected protected skip warning, got %+v\n%s", res, out.String())
+	}
+	m, err := manifest.Read(res.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range m.RepoExecutions {
+		if entry.FullName == "alice/prod-api" && entry.Status != manifest.StatusSkippedProtected {
+			t.Fatalf("unexpected protected entry: %+v", entry)
+		}
+	}
+
+	cfg.ProtectedRepos = []string{"alice/[x"}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err == nil {
+		t.Fatal("expected invalid protected pattern error")
+	}
+}
+
+func TestExecuteInterruptBetweenReposSavesManifestForResume(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}, {Owner: "alice", Name: "r2", FullName: "alice/r2"}}, now)
+	plan.Fingerprint = "fp-interrupt"
+	backupRoot := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gh := &fakeGH{}
+	ex := Executor{
+		GH:     gh,
+		Backup: &fakeBackup{},
+		Now:    func() time.Time { return now },
+		In:     strings.NewReader("ACCEPT\n"),
+		Out:    &strings.Builder{},
+		Progress: func(ev ProgressEvent) {
+			if ev.FullName == "alice/r1" && ev.Stage == StageDelete {
+				cancel()
+			}
+		},
+	}
+	cfg := Config{PlanPath: "plan.json", Resume: true, BackupDir: backupRoot, Mode: ModeDelete, MaxDeleteRetries: 1}
+	res, err := ex.Execute(ctx, cfg, plan)
+	if !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("expected ErrInterrupted, got %v", err)
+	}
+	if res.ManifestPath == "" || res.Deleted != 1 {
+		t.Fatalf("expected partial result with the finished repo: %+v", res)
+	}
+	m, err := manifest.Read(res.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.RepoExecutions[0].Status != manifest.StatusDeleted || m.RepoExecutions[1].Status != manifest.StatusPending {
+		t.Fatalf("unexpected statuses after interrupt: %+v", m.RepoExecutions)
+	}
+
+	ex.Progress = nil
+	ex.In = strings.NewReader("ACCEPT\n")
+	res, err = ex.Execute(context.Background(), cfg, plan)
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if res.Deleted != 2 || !slices.Equal(gh.deleted, []string{"alice/r1", "alice/r2"}) {
+		t.Fatalf("resume should finish the remaining repo: %+v %v", res, gh.deleted)
+	}
+}
+
+func TestExecuteWritesExtraManifestCopy(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, now)
+	plan.Fingerprint = "fp-extra"
+	backupRoot := t.TempDir()
+	extra := filepath.Join(t.TempDir(), "audit-manifest.json")
+	ex := Executor{GH: &fakeGH{}, Backup: &fakeBackup{}, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	res, err := ex.Execute(context.Background(), Config{PlanPath: "plan.json", BackupDir: backupRoot, Mode: ModeDelete, MaxDeleteRetries: 1, ExtraManifestPath: extra}, plan)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	primary, err := os.ReadFile(res.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied, err := os.ReadFile(extra)
+	if err != nil {
+		t.Fatalf("extra manifest not written: %v", err)
+	}
+	if string(primary) != string(copied) {
+		t.Fatalf("extra copy differs from primary:\n%s\n---\n%s", primary, copied)
+	}
+	if info, err := os.Stat(extra); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 extra copy, got %v (%v)", info.Mode().Perm(), err)
+	}
+
+	out := &strings.Builder{}
+	ex2 := Executor{GH: &fakeGH{}, Backup: &fakeBackup{}, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: out}
+	bad := filepath.Join(t.TempDir(), "missing", "dir", "manifest.json")
+	if _, err := ex2.Execute(context.Background(), Config{PlanPath: "plan.json", BackupDir: t.TempDir(), Mode: ModeDelete, MaxDeleteRetries: 1, ExtraManifestPath: bad}, plan); err != nil {
+		t.Fatalf("extra copy failure must not fail the run: %v", err)
+	}
+	if strings.Count(out.String(), "could not write manifest copy") != 1 {
+		t.Fatalf("expected a single warning:\n%s", out.String())
+	}
+}
+
+func TestExecuteBackupPruneMirrorSurvivesResume(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, now)
+	plan.Fingerprint = "fp-prune"
+	backupRoot := t.TempDir()
+
+	mirror := filepath.Join(backupRoot, "r1.git")
+	if err := os.MkdirAll(mirror, 0o755); err != nil {
+		t.Fatalf("mkdir mirror: %v", err)
+	}
+	cfg := Config{
+		PlanPath:    "plan.json",
+		Resume:      true,
+		BackupDir:   backupRoot,
+		Mode:        ModeBackup,
+		NoArchive:   true,
+		PruneMirror: true,
+	}
+	bk := &fakeBackup{paths: map[string]string{"alice/r1": mirror}}
+	ex := Executor{Backup: bk, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if _, err := os.Stat(mirror); !os.IsNotExist(err) {
+		t.Fatalf("expected mirror removed, stat err=%v", err)
+	}
+	m, err := manifest.Read(filepath.Join(backupRoot, "manifest.json"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if got := m.RepoExecutions[0]; got.BackupPath != "" || got.BundlePath == "" || got.Status != manifest.StatusBackupOK {
+		t.Fatalf("unexpected entry after prune: %+v", got)
+	}
+
+	resumed := &fakeBackup{}
+	ex = Executor{Backup: resumed, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if resumed.mirrorN != 0 || resumed.bundleN != 0 {
+		t.Fatalf("expected resume to skip pruned repo, mirror=%d bundle=%d", resumed.mirrorN, resumed.bundleN)
+	}
+}
+
+func TestExecuteBackupNoSnapshotSkipsBrowsableClone(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, now)
+	plan.Fingerprint = "fp-no-snapshot"
+	backupRoot := t.TempDir()
+	mirror := filepath.Join(backupRoot, "r1.git")
+	if err := os.MkdirAll(mirror, 0o755); err != nil {
+		t.Fatalf("mkdir mirror: %v", err)
+	}
+	cfg := Config{PlanPath: "plan.json", Resume: true, BackupDir: backupRoot, Mode: ModeBackup, NoArchive: true, PruneMirror: true, NoSnapshot: true}
+	bk := &fakeBackup{paths: map[string]string{"alice/r1": mirror}}
+	ex := Executor{Backup: bk, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if bk.snapshotN != 0 || bk.bundleN != 1 {
+		t.Fatalf("expected bundle without snapshot, snapshot=%d bundle=%d", bk.snapshotN, bk.bundleN)
+	}
+	m, err := manifest.Read(filepath.Join(backupRoot, "manifest.json"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	got := m.RepoExecutions[0]
+	if got.BrowsablePath != "" || !got.SnapshotSkipped || got.Status != manifest.StatusBackupOK || !mirrorPruned(got) {
+		t.Fatalf("unexpected entry: %+v", got)
+	}
+
+	resumed := &fakeBackup{}
+	ex = Executor{Backup: resumed, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if resumed.mirrorN != 0 || resumed.snapshotN != 0 || resumed.bundleN != 0 {
+		t.Fatalf("expected resume to treat skipped snapshot as complete: %+v", resumed)
+	}
+}
+
+func TestExecuteBackupRecordsLFSPresence(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{
+		{Owner: "alice", Name: "r1", FullName: "alice/r1"},
+		{Owner: "alice", Name: "r2", FullName: "alice/r2"},
+	}, now)
+	plan.Fingerprint = "fp-lfs"
+	backupRoot := t.TempDir()
+	cfg := Config{PlanPath: "plan.json", Resume: true, BackupDir: backupRoot, Mode: ModeBackup, NoArchive: true}
+
+	bk := &fakeBackup{lfsRepos: map[string]bool{"alice/r1": true}}
+	ex := Executor{Backup: bk, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if bk.lfsN != 0 {
+		t.Fatalf("expected LFS to be skipped without IncludeLFS, calls=%d", bk.lfsN)
+	}
+
+	cfg.BackupDir = t.TempDir()
+	cfg.IncludeLFS = true
+	bk = &fakeBackup{lfsRepos: map[string]bool{"alice/r1": true}}
+	ex = Executor{Backup: bk, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	m, err := manifest.Read(filepath.Join(cfg.BackupDir, "manifest.json"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if got := m.RepoExecutions[0]; !got.LFS || got.LFSPath != filepath.Join(cfg.BackupDir, "lfs", "r1") {
+		t.Fatalf("expected LFS recorded for r1: %+v", got)
+	}
+	if got := m.RepoExecutions[1]; got.LFS || got.LFSPath != "" || got.Status != manifest.StatusBackupOK {
+		t.Fatalf("expected r2 without LFS to back up normally: %+v", got)
+	}
+}
+
+func TestExecuteBackupWritesRunLog(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{
+		{Owner: "alice", Name: "r1", FullName: "alice/r1"},
+		{Owner: "alice", Name: "r2", FullName: "alice/r2"},
+	}, now)
+	plan.Fingerprint = "fp-log"
+	backupRoot := t.TempDir()
+	cfg := Config{PlanPath: "plan.json", Resume: true, BackupDir: backupRoot, Mode: ModeBackup, NoArchive: true, LogPath: "run.jsonl"}
+	bk := &fakeBackup{failFor: map[string]error{"alice/r2": errors.New("clone failed")}}
+	ex := Executor{Backup: bk, Now: func() time.Time { return now }, In: strings.NewReader("ACCEPT\n"), Out: &strings.Builder{}}
+	if _, err := ex.Execute(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	// A second run appends to the same log.
+	ex.In = strings.NewReader("ACCEPT\n")
+	if _, err := ex.Execute(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(backupRoot, "run.jsonl"))
+	if err != nil {
+		t.Fatalf("read run log: %v", err)
+	}
+	var done []LogEvent
+	starts := 0
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var ev LogEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if ev.Time != "2026-02-25T10:00:00Z" {
+			t.Fatalf("unexpected time in %q", line)
+		}
+		switch ev.Event {
+		case "run_start":
+			starts++
+		case "repo_done":
+			done = append(done, ev)
+		}
+	}
+	if starts != 2 {
+		t.Fatalf("expected log to be appended across runs, got %d run_start events", starts)
+	}
+	if len(done) < 2 {
+		t.Fatalf("expected repo_done events, got %+v", done)
+	}
+	if done[0].Repo != "alice/r1" || done[0].Status != string(manifest.StatusBackupOK) {
+		t.Fatalf("unexpected r1 event: %+v", done[0])
+	}
+	if done[1].Repo != "alice/r2" || done[1].Status != string(manifest.StatusBackupFailed) || done[1].Error == "" {
+		t.Fatalf("unexpected r2 event: %+v", done[1])
+	}
+}
+
+func TestPruneMirrorRejectsPathOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := pruneMirror(root, filepath.Join(filepath.Dir(root), "elsewhere.git")); err == nil {
+		t.Fatal("expected error for mirror outside backup root")
+	}
+}
+
+func TestResolveBackupRootUsesConfiguredBase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	base := t.TempDir()
+	ex := Executor{Now: func() time.Time { return now }}
+	root, err := ex.resolveBackupRoot("fp-none", Config{Resume: true, DefaultBackupBase: base})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(base, "gh-manager-archive-2026-02-25-100000"); root != want {
+		t.Fatalf("unexpected root: got=%s want=%s", root, want)
+	}
+}
+
+func TestResolveBackupRootResumesFromConfiguredBase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	base := t.TempDir()
+	existing := filepath.Join(base, "gh-manager-archive-2026-01-01-000000")
+	if err := os.MkdirAll(existing, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{FullName: "alice/r1"}}, now)
+	plan.Fingerprint = "fp-configured"
+	m := manifest.New("plan.json", existing, plan, now, manifest.NewOptions{Mode: ModeDelete})
+	if err := manifest.Write(manifest.Path(existing), m); err != nil {
+		t.Fatal(err)
+	}
+	ex := Executor{Now: func() time.Time { return now }}
+	root, err := ex.resolveBackupRoot("fp-configured", Config{Resume: true, DefaultBackupBase: base})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != existing {
+		t.Fatalf("expected resume from configured base, got %s", root)
+	}
+}
+
+func TestResolveBackupRootResumesFromExplicitSearchDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	data := t.TempDir()
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{FullName: "alice/r1"}}, now)
+	plan.Fingerprint = "fp-elsewhere"
+	m := manifest.New("plan.json", data, plan, now, manifest.NewOptions{Mode: ModeDelete})
+	if err := manifest.Write(manifest.Path(data), m); err != nil {
+		t.Fatal(err)
+	}
+	ex := Executor{Now: func() time.Time { return now }}
+
+	root, err := ex.resolveBackupRoot("fp-elsewhere", Config{Resume: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root == data {
+		t.Fatal("did not expect to find manifest without a search dir")
+	}
+	root, err = ex.resolveBackupRoot("fp-elsewhere", Config{Resume: true, ResumeSearchDirs: []string{data}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != data {
+		t.Fatalf("expected resume to locate %s, got %s", data, root)
+	}
+	root, err = ex.resolveBackupRoot("fp-other", Config{Resume: true, ResumeSearchDirs: []string{data}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root == data {
+		t.Fatal("expected fingerprint mismatch to be ignored")
+	}
+}
+
+func TestExecuteReportsProgress(t *testing.T) {
+	now := time.Date(2026, 2, 25, 10, 0, 0, 0, time.UTC)
+	plan := planfile.New("alice", "github.com", "test", []planfile.RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}, {Owner: "alice", Name: "r2", FullName: "alice/r2"}}, now)
+	plan.Fingerprint = "fp-progress"
+	var events []ProgressEvent
+	out := &strings.Builder{}
+	ex := Executor{
+		GH:       &fakeGH{},
+		Backup:   &fakeBackup{bundlePath: map[string]string{}},
+		Now:      func() time.Time { return now },
+		In:       strings.NewReader("ACCEPT\n"),
+		Out:      out,
+		Progress: func(ev ProgressEvent) { events = append(events, ev) },
+	}
+	if _, err := ex.Execute(context.Background(), Config{PlanPath: "plan.json", Resume: true, BackupDir: t.TempDir(), Mode: ModeDelete}, plan); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "[1/2] Backing up alice/r1...") || !strings.Contains(out.String(), "[2/2] Deleting alice/r2...") {
+		t.Fatalf("expected progress prefixes, got:\n%s", out.String())
+	}
+	if len(events) != 6 {
+		t.Fatalf("expected 3 stages per repo, got %d: %+v", len(events), events)
+	}
+	last := events[len(events)-1]
+	if last.Index != 2 || last.Total != 2 || last.Stage != StageDelete || last.FullName != "alice/r2" {
+		t.Fatalf("unexpected last event: %+v", last)
+	}
 }
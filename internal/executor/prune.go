@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gh-manager/internal/manifest"
+)
+
+const backupRootPrefix = "gh-manager-archive-"
+
+// BackupRootInfo describes one gh-manager-archive-* directory.
+type BackupRootInfo struct {
+	Path string
+	// ModTime is the manifest's modification time, or the directory's when
+	// there is no readable manifest.
+	ModTime     time.Time
+	HasManifest bool
+	// Incomplete counts entries still pending or failed, including repos whose
+	// bundle has not reached the archive repo yet.
+	Incomplete int
+}
+
+// Complete reports whether the root has a manifest with nothing left to do.
+func (r BackupRootInfo) Complete() bool {
+	return r.HasManifest && r.Incomplete == 0
+}
+
+// walkBackupRoots calls fn for every gh-manager-archive-* directory directly
+// under each base, skipping duplicate bases.
+func walkBackupRoots(bases []string, fn func(root string)) {
+	seen := map[string]bool{}
+	for _, base := range bases {
+		if base == "" || seen[base] {
+			continue
+		}
+		seen[base] = true
+		entries, err := os.ReadDir(base)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasPrefix(entry.Name(), backupRootPrefix) {
+				fn(filepath.Join(base, entry.Name()))
+			}
+		}
+	}
+}
+
+// ScanBackupRoots lists the backup roots under bases, newest first.
+func ScanBackupRoots(bases []string) []BackupRootInfo {
+	var roots []BackupRootInfo
+	walkBackupRoots(bases, func(root string) {
+		info := BackupRootInfo{Path: root}
+		if m, err := manifest.Read(manifest.Path(root)); err == nil {
+			info.HasManifest = true
+			for _, entry := range m.RepoExecutions {
+				if incompleteEntry(entry) {
+					info.Incomplete++
+				}
+			}
+			if st, err := os.Stat(manifest.Path(root)); err == nil {
+				info.ModTime = st.ModTime()
+			}
+		}
+		if info.ModTime.IsZero() {
+			st, err := os.Stat(root)
+			if err != nil {
+				return
+			}
+			info.ModTime = st.ModTime()
+		}
+		roots = append(roots, info)
+	})
+	sort.Slice(roots, func(i, j int) bool {
+		if !roots[i].ModTime.Equal(roots[j].ModTime) {
+			return roots[i].ModTime.After(roots[j].ModTime)
+		}
+		return roots[i].Path > roots[j].Path
+	})
+	return roots
+}
+
+// incompleteEntry reports whether a root still holds work for this entry, so
+// its local copy may be the only one.
+func incompleteEntry(entry manifest.RepoExecutionEntry) bool {
+	switch entry.Status {
+	case manifest.StatusPending, manifest.StatusSkippedNoBackup:
+		return true
+	}
+	return FailedEntry(entry) || needsArchive(entry)
+}
+
+// SelectPrunable picks roots (newest first, as from ScanBackupRoots) that are
+// older than olderThan or beyond the keep most recent. Zero disables either
+// rule. Roots without a complete manifest are held back unless force is set.
+func SelectPrunable(roots []BackupRootInfo, now time.Time, olderThan time.Duration, keep int, force bool) (prune, held []BackupRootInfo) {
+	for i, r := range roots {
+		tooOld := olderThan > 0 && now.Sub(r.ModTime) > olderThan
+		beyondKeep := keep > 0 && i >= keep
+		if !tooOld && !beyondKeep {
+			continue
+		}
+		if !force && !r.Complete() {
+			held = append(held, r)
+			continue
+		}
+		prune = append(prune, r)
+	}
+	return prune, held
+}
+
+// RemoveBackupRoot deletes a root found by ScanBackupRoots, refusing anything
+// that is not a gh-manager-archive-* directory.
+func RemoveBackupRoot(root string) error {
+	if !strings.HasPrefix(filepath.Base(filepath.Clean(root)), backupRootPrefix) {
+		return fmt.Errorf("refusing to remove %s: not a %s* directory", root, backupRootPrefix)
+	}
+	return os.RemoveAll(root)
+}
@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gh-manager/internal/manifest"
+	"gh-manager/internal/planfile"
+)
+
+func writeBackupRoot(t *testing.T, base, name string, mod time.Time, statuses ...manifest.RepoExecutionStatus) string {
+	t.Helper()
+	root := filepath.Join(base, name)
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if statuses != nil {
+		repos := make([]planfile.RepoRecord, len(statuses))
+		for i := range statuses {
+			repos[i] = planfile.RepoRecord{FullName: "alice/r" + string(rune('a'+i))}
+		}
+		m := manifest.New("plan.json", root, planfile.New("alice", "github.com", "test", repos, mod), mod, manifest.NewOptions{Mode: ModeBackup})
+		for i, s := range statuses {
+			m.RepoExecutions[i].Status = s
+			if s == manifest.StatusBackupOK || s == manifest.StatusDeleted {
+				m.RepoExecutions[i].ArchiveStatus = "archived"
+			}
+		}
+		if err := manifest.Write(manifest.Path(root), m); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(manifest.Path(root), mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(root, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestScanAndSelectPrunableBackupRoots(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	base := t.TempDir()
+	newest := writeBackupRoot(t, base, "gh-manager-archive-c", now.AddDate(0, 0, -1), manifest.StatusBackupOK)
+	oldDone := writeBackupRoot(t, base, "gh-manager-archive-b", now.AddDate(0, 0, -40), manifest.StatusBackupOK, manifest.StatusDeleted)
+	oldFailed := writeBackupRoot(t, base, "gh-manager-archive-a", now.AddDate(0, 0, -50), manifest.StatusBackupOK, manifest.StatusBackupFailed)
+	noManifest := writeBackupRoot(t, base, "gh-manager-archive-z", now.AddDate(0, 0, -60))
+	if err := os.MkdirAll(filepath.Join(base, "unrelated"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	roots := ScanBackupRoots([]string{base, base})
+	if len(roots) != 4 || roots[0].Path != newest || roots[3].Path != noManifest {
+		t.Fatalf("unexpected scan: %+v", roots)
+	}
+	if roots[2].Incomplete != 1 || roots[3].HasManifest {
+		t.Fatalf("unexpected manifest state: %+v", roots)
+	}
+
+	prune, held := SelectPrunable(roots, now, 30*24*time.Hour, 0, false)
+	if len(prune) != 1 || prune[0].Path != oldDone || len(held) != 2 || held[0].Path != oldFailed || held[1].Path != noManifest {
+		t.Fatalf("older-than: prune=%+v held=%+v", prune, held)
+	}
+	prune, held = SelectPrunable(roots, now, 0, 3, false)
+	if len(prune) != 0 || len(held) != 1 || held[0].Path != noManifest {
+		t.Fatalf("keep: prune=%+v held=%+v", prune, held)
+	}
+	prune, held = SelectPrunable(roots, now, 0, 1, true)
+	if len(prune) != 3 || len(held) != 0 {
+		t.Fatalf("force: prune=%+v held=%+v", prune, held)
+	}
+
+	if err := RemoveBackupRoot(oldDone); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := os.Stat(oldDone); !os.IsNotExist(err) {
+		t.Fatalf("expected root removed, stat err=%v", err)
+	}
+	if err := RemoveBackupRoot(base); err == nil {
+		t.Fatal("expected non-archive directory to be refused")
+	}
+}
+
+func TestScanBackupRootsCountsUnarchivedEntriesAsIncomplete(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	base := t.TempDir()
+	old := now.AddDate(0, 0, -40)
+	archiveFailed := writeBackupRoot(t, base, "gh-manager-archive-a", old, manifest.StatusBackupOK)
+	skipped := writeBackupRoot(t, base, "gh-manager-archive-b", old, manifest.StatusSkippedNoBackup)
+	m, err := manifest.Read(manifest.Path(archiveFailed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.RepoExecutions[0].ArchiveStatus = "archive_failed"
+	if err := manifest.Write(manifest.Path(archiveFailed), m); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(manifest.Path(archiveFailed), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	prune, held := SelectPrunable(ScanBackupRoots([]string{base}), now, 30*24*time.Hour, 0, false)
+	if len(prune) != 0 || len(held) != 2 {
+		t.Fatalf("roots with unarchived or skipped entries must be held: prune=%+v held=%+v", prune, held)
+	}
+	for _, r := range held {
+		if r.Path != archiveFailed && r.Path != skipped {
+			t.Fatalf("unexpected held root: %+v", r)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"gh-manager/internal/manifest"
+	"gh-manager/internal/planfile"
+)
+
+type Risk string
+
+const (
+	// RiskLow covers archived repos and forks.
+	RiskLow Risk = "low"
+	// RiskMedium covers source repos that have been idle, or whose activity is unknown.
+	RiskMedium Risk = "medium"
+	// RiskHigh covers source repos updated within the active window.
+	RiskHigh Risk = "high"
+
+	DefaultRiskActiveWindow = 180 * 24 * time.Hour
+
+	maxListedHighRisk = 10
+)
+
+// ClassifyRisk rates how costly deleting r would be if it were a mistake.
+func ClassifyRisk(r planfile.RepoRecord, now time.Time, activeWindow time.Duration) Risk {
+	if r.IsArchived || r.IsFork {
+		return RiskLow
+	}
+	if activeWindow <= 0 {
+		activeWindow = DefaultRiskActiveWindow
+	}
+	updated, ok := planfile.ParseUpdatedAt(r.UpdatedAt)
+	if !ok || now.Sub(updated) > activeWindow {
+		return RiskMedium
+	}
+	return RiskHigh
+}
+
+// deleteCandidates returns the plan repos this run will try to delete:
+// protected repos are never deleted, and on resume entries already done (or,
+// with --only-failed, not failed) are left alone.
+func deleteCandidates(cfg Config, repos []planfile.RepoRecord, m manifest.ExecutionManifestV1, isProtected func(string) bool) []planfile.RepoRecord {
+	entries := make(map[string]manifest.RepoExecutionEntry, len(m.RepoExecutions))
+	for _, e := range m.RepoExecutions {
+		entries[e.FullName] = e
+	}
+	out := make([]planfile.RepoRecord, 0, len(repos))
+	for _, r := range repos {
+		if isProtected(r.FullName) {
+			continue
+		}
+		if e, ok := entries[r.FullName]; ok && (shouldSkipEntry(cfg.Mode, e) || (cfg.OnlyFailed && !FailedEntry(e))) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// printRiskBreakdown summarises the planned deletes by risk and lists the
+// high-risk repos. It returns how many repos are high risk.
+func printRiskBreakdown(out io.Writer, repos []planfile.RepoRecord, now time.Time, activeWindow time.Duration) int {
+	counts := map[Risk]int{}
+	var high []string
+	for _, r := range repos {
+		risk := ClassifyRisk(r, now, activeWindow)
+		counts[risk]++
+		if risk == RiskHigh {
+			high = append(high, r.FullName)
+		}
+	}
+	fmt.Fprintf(out, "Risk: %d high (active source repos), %d medium (idle or unknown activity), %d low (archived/forks)\n", counts[RiskHigh], counts[RiskMedium], counts[RiskLow])
+	for i, name := range high {
+		if i == maxListedHighRisk {
+			fmt.Fprintf(out, "  ... and %d more\n", len(high)-maxListedHighRisk)
+			break
+		}
+		fmt.Fprintf(out, "  high: %s\n", name)
+	}
+	return len(high)
+}
@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gh-manager/internal/manifest"
+)
+
+// LogEvent is one line of the JSONL run log written when Config.LogPath is set.
+type LogEvent struct {
+	Time          string `json:"time"`
+	Event         string `json:"event"`
+	Mode          string `json:"mode,omitempty"`
+	Repo          string `json:"repo,omitempty"`
+	Index         int    `json:"index,omitempty"`
+	Total         int    `json:"total,omitempty"`
+	Stage         string `json:"stage,omitempty"`
+	Status        string `json:"status,omitempty"`
+	ArchiveStatus string `json:"archiveStatus,omitempty"`
+	Commit        string `json:"commit,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Attempts      int    `json:"attempts,omitempty"`
+}
+
+// runLog appends events to a JSONL file and syncs after every line so an
+// interrupted run still leaves a readable log. A nil runLog discards events.
+type runLog struct {
+	f   *os.File
+	now func() time.Time
+}
+
+func openRunLog(path, backupRoot string, now func() time.Time) (*runLog, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(backupRoot, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &runLog{f: f, now: now}, nil
+}
+
+func (l *runLog) write(ev LogEvent) {
+	if l == nil {
+		return
+	}
+	ev.Time = l.now().UTC().Format(time.RFC3339)
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	_, _ = l.f.Write(append(b, '\n'))
+	_ = l.f.Sync()
+}
+
+func (l *runLog) repoDone(index, total int, entry manifest.RepoExecutionEntry) {
+	l.write(LogEvent{
+		Event:         "repo_done",
+		Repo:          entry.FullName,
+		Index:         index,
+		Total:         total,
+		Status:        string(entry.Status),
+		ArchiveStatus: entry.ArchiveStatus,
+		Error:         entry.Error,
+		Attempts:      entry.Attempts,
+	})
+}
+
+func (l *runLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.f.Close()
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gh-manager/internal/planfile"
+)
+
+// RepoCache keeps ListRepos results on disk as repos-<owner>.json so repeated
+// commands can skip the fetch while the entry is younger than TTL.
+type RepoCache struct {
+	Dir string
+	TTL time.Duration
+	// Host is recorded with each entry; an entry from another host is a miss.
+	Host string
+	// Refresh skips reading the cache but still rewrites it.
+	Refresh bool
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+type repoCacheFile struct {
+	FetchedAt time.Time             `json:"fetchedAt"`
+	Host      string                `json:"host"`
+	Source    string                `json:"source"`
+	Limit     int                   `json:"limit"`
+	Repos     []planfile.RepoRecord `json:"repos"`
+}
+
+func (c RepoCache) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// path names the entry after owner, or after the source for member/all
+// listings, which do not depend on an owner.
+func (c RepoCache) path(owner, source string) string {
+	if source != SourceOwner {
+		owner = "@" + source
+	}
+	return filepath.Join(c.Dir, "repos-"+strings.ReplaceAll(owner, string(filepath.Separator), "_")+".json")
+}
+
+// Load returns the cached repos for owner when a fresh entry with the same
+// host, source and limit exists.
+func (c RepoCache) Load(owner, source string, limit int) ([]planfile.RepoRecord, bool) {
+	if c.Refresh || c.TTL <= 0 {
+		return nil, false
+	}
+	b, err := os.ReadFile(c.path(owner, source))
+	if err != nil {
+		return nil, false
+	}
+	var f repoCacheFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, false
+	}
+	if f.Host != c.Host || f.Source != source || f.Limit != limit {
+		return nil, false
+	}
+	if age := c.now().Sub(f.FetchedAt); age < 0 || age >= c.TTL {
+		return nil, false
+	}
+	return f.Repos, true
+}
+
+func (c RepoCache) Save(owner, source string, limit int, repos []planfile.RepoRecord) error {
+	if c.TTL <= 0 {
+		return nil
+	}
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(repoCacheFile{FetchedAt: c.now().UTC(), Host: c.Host, Source: source, Limit: limit, Repos: repos}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := c.path(owner, source)
+	if err := os.WriteFile(path+".tmp", append(b, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Clear removes every cached repo list, e.g. after repos were deleted.
+func (c RepoCache) Clear() error {
+	matches, err := filepath.Glob(filepath.Join(c.Dir, "repos-*.json"))
+	if err != nil {
+		return err
+	}
+	for _, m := range matches {
+		if err := os.Remove(m); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRepoCacheHitMissAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache := RepoCache{Dir: dir, TTL: 10 * time.Minute, Host: "github.com", Now: func() time.Time { return now }}
+	r := &fakeRunner{out: map[string]string{
+		"gh repo list": `[{"name":"r1","nameWithOwner":"alice/r1","owner":{"login":"alice"}}]`,
+	}}
+	fetches := func() int {
+		n := 0
+		for _, c := range r.calls {
+			if strings.HasPrefix(c, "gh repo list") {
+				n++
+			}
+		}
+		return n
+	}
+	gh := NewClient(r).WithRepoCache(cache)
+
+	if repos, err := gh.ListUserRepos(ctx, "alice"); err != nil || len(repos) != 1 || fetches() != 1 {
+		t.Fatalf("expected a fetch on miss, repos=%v err=%v fetches=%d", repos, err, fetches())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "repos-alice.json")); err != nil {
+		t.Fatalf("expected cache file: %v", err)
+	}
+
+	now = now.Add(9 * time.Minute)
+	if repos, err := gh.ListUserRepos(ctx, "alice"); err != nil || len(repos) != 1 || repos[0].FullName != "alice/r1" || fetches() != 1 {
+		t.Fatalf("expected a cache hit, repos=%v err=%v fetches=%d", repos, err, fetches())
+	}
+	if _, err := gh.ListRepos(ctx, "alice", ListOptions{Limit: 5}); err != nil || fetches() != 2 {
+		t.Fatalf("expected a different limit to miss, err=%v fetches=%d", err, fetches())
+	}
+
+	now = now.Add(11 * time.Minute)
+	if _, err := gh.ListUserRepos(ctx, "alice"); err != nil || fetches() != 3 {
+		t.Fatalf("expected an expired entry to refetch, err=%v fetches=%d", err, fetches())
+	}
+
+	refresh := cache
+	refresh.Refresh = true
+	if _, err := NewClient(r).WithRepoCache(refresh).ListUserRepos(ctx, "alice"); err != nil || fetches() != 4 {
+		t.Fatalf("expected refresh to bypass the cache, err=%v fetches=%d", err, fetches())
+	}
+	other := cache
+	other.Host = "ghe.example.com"
+	if _, err := NewClient(r).WithRepoCache(other).ListUserRepos(ctx, "alice"); err != nil || fetches() != 5 {
+		t.Fatalf("expected another host to miss, err=%v fetches=%d", err, fetches())
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "repos-alice.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected Clear to remove the cache file, got %v", err)
+	}
+}
@@ -1,9 +1,11 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 
 type Client struct {
 	runner app.CommandRunner
+	cache  *RepoCache
 }
 
 func NewClient(r app.CommandRunner) Client {
@@ -29,11 +32,19 @@ type repoResponse struct {
 	Description string `json:"description"`
 	UpdatedAt   string `json:"updatedAt"`
 	IsPrivate   bool   `json:"isPrivate"`
+	Visibility  string `json:"visibility"`
 	IsFork      bool   `json:"isFork"`
 	IsArchived  bool   `json:"isArchived"`
+	DiskUsage   int64  `json:"diskUsage"`
 	Owner       struct {
 		Login string `json:"login"`
 	} `json:"owner"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	RepositoryTopics []struct {
+		Name string `json:"name"`
+	} `json:"repositoryTopics"`
 }
 
 func (c Client) CurrentUser(ctx context.Context) (string, error) {
@@ -44,19 +55,102 @@ func (c Client) CurrentUser(ctx context.Context) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// restRepoResponse is the REST shape returned by `gh api user/repos`.
+type restRepoResponse struct {
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	UpdatedAt   string `json:"updated_at"`
+	Private     bool   `json:"private"`
+	Visibility  string `json:"visibility"`
+	Fork        bool   `json:"fork"`
+	Archived    bool   `json:"archived"`
+	Size        int64  `json:"size"`
+	Owner       struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Language string   `json:"language"`
+	Topics   []string `json:"topics"`
+}
+
+const (
+	SourceOwner  = "owner"
+	SourceMember = "member"
+	SourceAll    = "all"
+
+	DefaultListLimit = 1000
+)
+
+// ListOptions narrows ListRepos. Source owner (the default) lists repos owned
+// by the given owner via `gh repo list`; member and all list the
+// authenticated user's collaborator/org-member repos (plus owned ones for all)
+// via the REST affiliation filter.
+type ListOptions struct {
+	Limit  int
+	Source string
+}
+
+func ParseSource(s string) (string, error) {
+	switch s {
+	case "", SourceOwner:
+		return SourceOwner, nil
+	case SourceMember, SourceAll:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unsupported source: %s (want owner|member|all)", s)
+	}
+}
+
+// WithRepoCache returns a client whose ListRepos consults cache first.
+func (c Client) WithRepoCache(cache RepoCache) Client {
+	c.cache = &cache
+	return c
+}
+
 func (c Client) ListUserRepos(ctx context.Context, owner string) ([]planfile.RepoRecord, error) {
-	if owner == "" {
+	return c.ListRepos(ctx, owner, ListOptions{})
+}
+
+func (c Client) ListRepos(ctx context.Context, owner string, opts ListOptions) ([]planfile.RepoRecord, error) {
+	source, err := ParseSource(opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if source == SourceOwner && owner == "" {
 		u, err := c.CurrentUser(ctx)
 		if err != nil {
 			return nil, err
 		}
 		owner = u
 	}
+	if c.cache == nil {
+		return c.fetchRepos(ctx, owner, source, limit)
+	}
+	if repos, ok := c.cache.Load(owner, source, limit); ok {
+		return repos, nil
+	}
+	repos, err := c.fetchRepos(ctx, owner, source, limit)
+	if err != nil {
+		return nil, err
+	}
+	// A cache write failure only costs the next command a refetch.
+	_ = c.cache.Save(owner, source, limit, repos)
+	return repos, nil
+}
+
+func (c Client) fetchRepos(ctx context.Context, owner, source string, limit int) ([]planfile.RepoRecord, error) {
+	if source != SourceOwner {
+		return c.listAffiliatedRepos(ctx, source, limit)
+	}
 	out, err := c.runner.Run(
 		ctx,
 		"gh", "repo", "list", owner,
-		"--limit", "1000",
-		"--json", "name,nameWithOwner,description,updatedAt,isPrivate,isFork,isArchived,owner",
+		"--limit", strconv.Itoa(limit),
+		"--json", "name,nameWithOwner,description,updatedAt,isPrivate,visibility,isFork,isArchived,diskUsage,owner,primaryLanguage,repositoryTopics",
 	)
 	if err != nil {
 		return nil, err
@@ -67,9 +161,13 @@ func (c Client) ListUserRepos(ctx context.Context, owner string) ([]planfile.Rep
 	}
 	repos := make([]planfile.RepoRecord, 0, len(raw))
 	for _, r := range raw {
-		updated := r.UpdatedAt
-		if _, err := time.Parse(time.RFC3339, updated); err != nil {
-			updated = ""
+		var language string
+		if r.PrimaryLanguage != nil {
+			language = r.PrimaryLanguage.Name
+		}
+		var topics []string
+		for _, t := range r.RepositoryTopics {
+			topics = append(topics, t.Name)
 		}
 		repos = append(repos, planfile.RepoRecord{
 			Owner:       r.Owner.Login,
@@ -77,14 +175,83 @@ func (c Client) ListUserRepos(ctx context.Context, owner string) ([]planfile.Rep
 			FullName:    r.NameWithOwn,
 			Description: r.Description,
 			IsPrivate:   r.IsPrivate,
+			Visibility:  strings.ToLower(r.Visibility),
 			IsFork:      r.IsFork,
 			IsArchived:  r.IsArchived,
-			UpdatedAt:   updated,
+			UpdatedAt:   validUpdatedAt(r.UpdatedAt),
+			DiskUsage:   r.DiskUsage,
+			Language:    language,
+			Topics:      topics,
 		})
 	}
 	return repos, nil
 }
 
+// listAffiliatedRepos pages through /user/repos. gh prints one JSON array per
+// page; pagination is skipped when the limit fits in a single page, otherwise
+// every page is fetched and the result truncated to limit.
+func (c Client) listAffiliatedRepos(ctx context.Context, source string, limit int) ([]planfile.RepoRecord, error) {
+	affiliation := "collaborator,organization_member"
+	if source == SourceAll {
+		affiliation = "owner,collaborator,organization_member"
+	}
+	perPage := 100
+	args := []string{"api"}
+	if limit > perPage {
+		args = append(args, "--paginate")
+	} else {
+		perPage = limit
+	}
+	args = append(args, fmt.Sprintf("user/repos?affiliation=%s&per_page=%d&sort=full_name", affiliation, perPage))
+	out, err := c.runner.Run(ctx, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+	var repos []planfile.RepoRecord
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() && len(repos) < limit {
+		var page []restRepoResponse
+		if err := dec.Decode(&page); err != nil {
+			return nil, fmt.Errorf("parse repo list: %w", err)
+		}
+		for _, r := range page {
+			if len(repos) == limit {
+				break
+			}
+			repos = append(repos, planfile.RepoRecord{
+				Owner:       r.Owner.Login,
+				Name:        r.Name,
+				FullName:    r.FullName,
+				Description: r.Description,
+				IsPrivate:   r.Private,
+				Visibility:  strings.ToLower(r.Visibility),
+				IsFork:      r.Fork,
+				IsArchived:  r.Archived,
+				UpdatedAt:   validUpdatedAt(r.UpdatedAt),
+				DiskUsage:   r.Size,
+				Language:    r.Language,
+				Topics:      r.Topics,
+			})
+		}
+	}
+	return repos, nil
+}
+
+func validUpdatedAt(s string) string {
+	if _, err := time.Parse(time.RFC3339, s); err != nil {
+		return ""
+	}
+	return s
+}
+
+func (c Client) HeadSHA(ctx context.Context, fullName string) (string, error) {
+	out, err := c.runner.Run(ctx, "gh", "api", "repos/"+fullName+"/commits/HEAD", "--jq", ".sha")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 func (c Client) DeleteRepo(ctx context.Context, fullName string) error {
 	_, err := c.runner.Run(ctx, "gh", "repo", "delete", fullName, "--yes")
 	return err
@@ -94,10 +261,34 @@ func (c Client) EnsureRepo(ctx context.Context, fullName, visibility string) err
 	if _, err := c.runner.Run(ctx, "gh", "repo", "view", fullName, "--json", "name", "--jq", ".name"); err == nil {
 		return nil
 	}
-	vis := "--private"
-	if visibility == "public" {
-		vis = "--public"
+	vis, err := VisibilityFlag(visibility)
+	if err != nil {
+		return err
 	}
-	_, err := c.runner.Run(ctx, "gh", "repo", "create", fullName, vis, "--confirm")
+	_, err = c.runner.Run(ctx, "gh", "repo", "create", fullName, vis, "--confirm")
 	return err
 }
+
+// VisibilityFlag maps a repo visibility to its `gh repo create` flag; an
+// empty value means private. internal is only valid on enterprise hosts.
+func VisibilityFlag(visibility string) (string, error) {
+	switch visibility {
+	case "", "private":
+		return "--private", nil
+	case "public":
+		return "--public", nil
+	case "internal":
+		return "--internal", nil
+	default:
+		return "", fmt.Errorf("unsupported visibility: %s (want private|public|internal)", visibility)
+	}
+}
+
+// ValidateFullName checks that name has the owner/name form.
+func ValidateFullName(name string) error {
+	owner, repo, ok := strings.Cut(name, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid repository %q (want owner/name)", name)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeRunner struct {
+	calls []string
+	// out maps a call prefix to its stdout.
+	out map[string]string
+}
+
+func (f *fakeRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	call := name + " " + strings.Join(args, " ")
+	f.calls = append(f.calls, call)
+	for prefix, out := range f.out {
+		if strings.HasPrefix(call, prefix) {
+			return []byte(out), nil
+		}
+	}
+	if strings.HasPrefix(call, "gh repo view") {
+		return nil, errors.New("HTTP 404: Not Found")
+	}
+	return nil, nil
+}
+
+func TestEnsureRepoPassesVisibilityFlag(t *testing.T) {
+	for visibility, flag := range map[string]string{"": "--private", "private": "--private", "public": "--public", "internal": "--internal"} {
+		r := &fakeRunner{}
+		if err := NewClient(r).EnsureRepo(context.Background(), "alice/archive", visibility); err != nil {
+			t.Fatalf("%q: %v", visibility, err)
+		}
+		want := "gh repo create alice/archive " + flag + " --confirm"
+		if len(r.calls) != 2 || r.calls[1] != want {
+			t.Fatalf("%q: unexpected calls %v", visibility, r.calls)
+		}
+	}
+
+	r := &fakeRunner{}
+	if err := NewClient(r).EnsureRepo(context.Background(), "alice/archive", "secret"); err == nil {
+		t.Fatal("expected unknown visibility to be rejected")
+	}
+	if len(r.calls) != 1 {
+		t.Fatalf("expected no create call for unknown visibility, got %v", r.calls)
+	}
+}
+
+func TestListReposMapsLimitAndSourceToGhArgs(t *testing.T) {
+	ctx := context.Background()
+	r := &fakeRunner{out: map[string]string{
+		"gh repo list": `[{"name":"r1","nameWithOwner":"alice/r1","owner":{"login":"alice"},"updatedAt":"2026-01-01T00:00:00Z","isPrivate":true,"visibility":"INTERNAL","primaryLanguage":{"name":"Go"},"repositoryTopics":[{"name":"cli"}]}]`,
+	}}
+	repos, err := NewClient(r).ListRepos(ctx, "alice", ListOptions{Limit: 25})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	want := "gh repo list alice --limit 25 --json name,nameWithOwner,description,updatedAt,isPrivate,visibility,isFork,isArchived,diskUsage,owner,primaryLanguage,repositoryTopics"
+	if len(r.calls) != 1 || r.calls[0] != want || len(repos) != 1 || repos[0].FullName != "alice/r1" || repos[0].Language != "Go" || repos[0].Visibility != "internal" || len(repos[0].Topics) != 1 || repos[0].Topics[0] != "cli" {
+		t.Fatalf("unexpected owner listing: calls=%v repos=%v", r.calls, repos)
+	}
+
+	r = &fakeRunner{out: map[string]string{"gh api user/repos": `[
+		{"name":"x","full_name":"org/x","owner":{"login":"org"},"private":true,"visibility":"private","size":12,"updated_at":"2026-01-01T00:00:00Z"},
+		{"name":"y","full_name":"bob/y","owner":{"login":"bob"},"fork":true,"archived":true}
+	]`}}
+	repos, err = NewClient(r).ListRepos(ctx, "", ListOptions{Limit: 1, Source: SourceMember})
+	if err != nil {
+		t.Fatalf("list member: %v", err)
+	}
+	if len(r.calls) != 1 || r.calls[0] != "gh api user/repos?affiliation=collaborator,organization_member&per_page=1&sort=full_name" {
+		t.Fatalf("unexpected member call: %v", r.calls)
+	}
+	if len(repos) != 1 || repos[0].FullName != "org/x" || repos[0].Owner != "org" || !repos[0].IsPrivate || repos[0].Visibility != "private" || repos[0].DiskUsage != 12 {
+		t.Fatalf("expected limit to truncate and fields to map: %+v", repos)
+	}
+
+	// Above one page gh paginates and prints one array per page.
+	r = &fakeRunner{out: map[string]string{"gh api --paginate": `[{"name":"a","full_name":"alice/a"}]` + "\n" + `[{"name":"b","full_name":"org/b"}]`}}
+	repos, err = NewClient(r).ListRepos(ctx, "", ListOptions{Source: SourceAll})
+	if err != nil {
+		t.Fatalf("list all: %v", err)
+	}
+	if len(r.calls) != 1 || r.calls[0] != "gh api --paginate user/repos?affiliation=owner,collaborator,organization_member&per_page=100&sort=full_name" {
+		t.Fatalf("unexpected all call: %v", r.calls)
+	}
+	if len(repos) != 2 || repos[1].FullName != "org/b" {
+		t.Fatalf("expected both pages, got %+v", repos)
+	}
+
+	if _, err := NewClient(&fakeRunner{}).ListRepos(ctx, "", ListOptions{Source: "everyone"}); err == nil {
+		t.Fatal("expected unknown source to be rejected")
+	}
+}
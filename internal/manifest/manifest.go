@@ -1,14 +1,22 @@
 package manifest
 
 import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gh-manager/internal/planfile"
 )
 
+const SchemaVersion = "v1"
+
 type RepoExecutionStatus string
 
 const (
@@ -17,6 +25,12 @@ const (
 	StatusDeleted      RepoExecutionStatus = "deleted"
 	StatusBackupFailed RepoExecutionStatus = "backup_failed"
 	StatusDeleteFailed RepoExecutionStatus = "delete_failed"
+	// StatusSkippedNoBackup marks a repo left undeleted because its backup
+	// could not be verified (execute --backup-then-delete).
+	StatusSkippedNoBackup RepoExecutionStatus = "skipped_no_backup"
+	// StatusSkippedProtected marks a repo left undeleted because it matches
+	// protected_repos.
+	StatusSkippedProtected RepoExecutionStatus = "skipped_protected"
 )
 
 type RepoExecutionEntry struct {
@@ -24,12 +38,21 @@ type RepoExecutionEntry struct {
 	Status        RepoExecutionStatus `json:"status"`
 	BackupPath    string              `json:"backupPath,omitempty"`
 	BrowsablePath string              `json:"browsablePath,omitempty"`
-	BundlePath    string              `json:"bundlePath,omitempty"`
-	ArchiveCommit string              `json:"archiveCommit,omitempty"`
-	ArchiveStatus string              `json:"archiveStatus,omitempty"`
-	Error         string              `json:"error,omitempty"`
-	Attempts      int                 `json:"attempts"`
-	LastAttemptAt string              `json:"lastAttemptAt,omitempty"`
+	// SnapshotSkipped marks an entry backed up with --no-snapshot, so an empty
+	// BrowsablePath is not treated as unfinished work.
+	SnapshotSkipped bool   `json:"snapshotSkipped,omitempty"`
+	BundlePath      string `json:"bundlePath,omitempty"`
+	// Compression is "gzip" when BundlePath is a .bundle.gz file.
+	Compression   string `json:"compression,omitempty"`
+	LFS           bool   `json:"lfs,omitempty"`
+	LFSPath       string `json:"lfsPath,omitempty"`
+	ArchiveCommit string `json:"archiveCommit,omitempty"`
+	ArchiveStatus string `json:"archiveStatus,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Attempts      int    `json:"attempts"`
+	LastAttemptAt string `json:"lastAttemptAt,omitempty"`
+	// Visibility is the source repo's visibility when the plan was made.
+	Visibility string `json:"visibility,omitempty"`
 }
 
 type ExecutionManifestV1 struct {
@@ -60,8 +83,130 @@ type BundleArtifact struct {
 	FullName   string `json:"fullName"`
 	BundlePath string `json:"bundlePath"`
 	UpdatedAt  string `json:"updatedAt"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// ArchiveManifest is the manifest.json written next to the bundles in each
+// archive repo folder.
+type ArchiveManifest struct {
+	PlanFingerprint string          `json:"planFingerprint"`
+	CreatedAt       string          `json:"createdAt"`
+	Bundles         []ArchiveBundle `json:"bundles"`
+}
+
+type ArchiveBundle struct {
+	FullName string `json:"fullName"`
+	// BundleFile is relative to the folder holding the archive manifest.
+	BundleFile string `json:"bundleFile"`
+	SHA256     string `json:"sha256"`
+	UpdatedAt  string `json:"updatedAt"`
+	// Compression is "gzip" for .bundle.gz files.
+	Compression string `json:"compression,omitempty"`
+	// Visibility is the source repo's visibility, so restore can warn before
+	// making a private repo public.
+	Visibility string `json:"visibility,omitempty"`
+}
+
+const CompressionGzip = "gzip"
+
+// BundleCompression reports the compression of a bundle file from its name.
+func BundleCompression(path string) string {
+	if strings.HasSuffix(path, ".bundle.gz") {
+		return CompressionGzip
+	}
+	return ""
+}
+
+// DecompressBundle gunzips a .bundle.gz into a temporary .bundle file in dir
+// (the system temp dir when empty) and returns its path; the caller removes
+// it.
+func DecompressBundle(path, dir string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	zr, err := gzip.NewReader(in)
+	if err != nil {
+		return "", fmt.Errorf("decompress bundle %s: %w", path, err)
+	}
+	defer zr.Close()
+	out, err := os.CreateTemp(dir, "gh-manager-*.bundle")
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(out, zr)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(out.Name())
+		return "", fmt.Errorf("decompress bundle %s: %w", path, err)
+	}
+	return out.Name(), nil
+}
+
+// ReadArchive loads an archive manifest; a missing file yields an empty one.
+func ReadArchive(path string) (ArchiveManifest, error) {
+	var man ArchiveManifest
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return man, nil
+		}
+		return man, err
+	}
+	if err := json.Unmarshal(b, &man); err != nil {
+		return man, fmt.Errorf("parse archive manifest %s: %w", path, err)
+	}
+	return man, nil
+}
+
+// FileSHA256 returns the hex sha256 recorded for bundles in ArchiveBundle.
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// PublishResult summarizes one archive publish: the pushed commit plus how
+// many bundles and bytes it carried. Skipped counts bundles whose content
+// was already in the archive repo.
+type PublishResult struct {
+	Commit  string
+	Bundles int
+	Bytes   int64
+	Skipped int
+}
+
+// CommitError reports an archive publish that failed before its commit was
+// created; a retry has to copy and commit the bundles again.
+type CommitError struct {
+	Err error
 }
 
+func (e *CommitError) Error() string { return "archive commit failed: " + e.Err.Error() }
+func (e *CommitError) Unwrap() error { return e.Err }
+
+// PushError reports an archive commit that was created but not pushed. The
+// commit is kept locally so a resumed run only has to push it.
+type PushError struct {
+	Commit string
+	Err    error
+}
+
+func (e *PushError) Error() string {
+	return fmt.Sprintf("archive push failed (commit %s kept for resume): %v", e.Commit, e.Err)
+}
+func (e *PushError) Unwrap() error { return e.Err }
+
 func New(planPath, backupRoot string, p planfile.DeletionPlanV1, now time.Time, opts NewOptions) ExecutionManifestV1 {
 	repos := make([]RepoExecutionEntry, 0, len(p.Repos))
 	for _, r := range p.Repos {
@@ -72,12 +217,13 @@ func New(planPath, backupRoot string, p planfile.DeletionPlanV1, now time.Time,
 		repos = append(repos, RepoExecutionEntry{
 			FullName:      r.FullName,
 			Status:        StatusPending,
+			Visibility:    RepoVisibility(r),
 			ArchiveStatus: archiveStatus,
 		})
 	}
 	ts := now.UTC().Format(time.RFC3339)
 	return ExecutionManifestV1{
-		SchemaVersion:   "v1",
+		SchemaVersion:   SchemaVersion,
 		Mode:            opts.Mode,
 		PlanFingerprint: p.Fingerprint,
 		PlanPath:        planPath,
@@ -92,19 +238,85 @@ func New(planPath, backupRoot string, p planfile.DeletionPlanV1, now time.Time,
 	}
 }
 
+// RepoVisibility is the visibility recorded for r: private, public or
+// internal. Plans without a visibility fall back to isPrivate.
+func RepoVisibility(r planfile.RepoRecord) string {
+	if r.Visibility != "" {
+		return r.Visibility
+	}
+	if r.IsPrivate {
+		return "private"
+	}
+	return "public"
+}
+
 func Path(backupRoot string) string {
 	return filepath.Join(backupRoot, "manifest.json")
 }
 
 func Read(path string) (ExecutionManifestV1, error) {
-	var m ExecutionManifestV1
 	b, err := os.ReadFile(path)
 	if err != nil {
-		return m, err
+		return ExecutionManifestV1{}, err
+	}
+	m, err := migrate(b)
+	if err != nil {
+		return ExecutionManifestV1{}, err
+	}
+	if m.BackupRoot == "" {
+		m.BackupRoot = filepath.Dir(path)
+	}
+	return m, nil
+}
+
+// migrate decodes a manifest of any known schema version and upgrades it to
+// the current v1 layout. Manifests written before schemaVersion existed were
+// delete-only and did not record mode, host, or archive status.
+func migrate(raw []byte) (ExecutionManifestV1, error) {
+	var head struct {
+		SchemaVersion string `json:"schemaVersion"`
 	}
-	if err := json.Unmarshal(b, &m); err != nil {
-		return m, err
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return ExecutionManifestV1{}, err
+	}
+	var m ExecutionManifestV1
+	switch head.SchemaVersion {
+	case SchemaVersion:
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return ExecutionManifestV1{}, err
+		}
+		return m, nil
+	case "":
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return ExecutionManifestV1{}, err
+		}
+	default:
+		return ExecutionManifestV1{}, fmt.Errorf("unsupported manifest schemaVersion: %s", head.SchemaVersion)
+	}
+
+	m.SchemaVersion = SchemaVersion
+	if m.Mode == "" {
+		m.Mode = "delete"
 	}
+	if m.Host == "" {
+		m.Host = "github.com"
+	}
+	if m.UpdatedAt == "" {
+		m.UpdatedAt = m.CreatedAt
+	}
+	for i := range m.RepoExecutions {
+		entry := &m.RepoExecutions[i]
+		if entry.Status == "" {
+			entry.Status = StatusPending
+		}
+		if entry.ArchiveStatus == "" {
+			entry.ArchiveStatus = "skipped"
+			if m.Mode == "backup" {
+				entry.ArchiveStatus = "pending"
+			}
+		}
+	}
+	m.RecomputeCounters()
 	return m, nil
 }
 
@@ -115,7 +327,11 @@ func Write(path string, m ExecutionManifestV1) error {
 		return err
 	}
 	b = append(b, '\n')
-	return os.WriteFile(path, b, 0o600)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
 }
 
 func (m *ExecutionManifestV1) Touch(now time.Time) {
@@ -1,6 +1,8 @@
 package manifest
 
 import (
+	"compress/gzip"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -26,3 +28,89 @@ func TestManifestWriteReadAndCounters(t *testing.T) {
 		t.Fatalf("unexpected counters: %+v", loaded)
 	}
 }
+
+func TestReadMigratesLegacyManifest(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "manifest.json")
+	legacy := `{
+  "planFingerprint": "fp-old",
+  "planPath": "/tmp/plan.json",
+  "actor": "alice",
+  "createdAt": "2025-01-01T00:00:00Z",
+  "repoExecutions": [
+    {"fullName": "alice/r1", "status": "deleted", "backupPath": "/b/r1.git", "attempts": 1},
+    {"fullName": "alice/r2"}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Read(path)
+	if err != nil {
+		t.Fatalf("read legacy: %v", err)
+	}
+	if m.SchemaVersion != SchemaVersion || m.Mode != "delete" || m.Host != "github.com" {
+		t.Fatalf("unexpected migrated header: %+v", m)
+	}
+	if m.BackupRoot != root || m.UpdatedAt != "2025-01-01T00:00:00Z" {
+		t.Fatalf("expected defaulted backupRoot/updatedAt, got %q %q", m.BackupRoot, m.UpdatedAt)
+	}
+	if m.RepoExecutions[1].Status != StatusPending || m.RepoExecutions[1].ArchiveStatus != "skipped" {
+		t.Fatalf("unexpected migrated entry: %+v", m.RepoExecutions[1])
+	}
+	if m.DeletedCount != 1 {
+		t.Fatalf("expected counters recomputed, got %d", m.DeletedCount)
+	}
+}
+
+func TestReadRejectsUnknownSchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte(`{"schemaVersion":"v9"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path); err == nil {
+		t.Fatal("expected unsupported schemaVersion error")
+	}
+}
+
+func TestRepoVisibilityKeepsInternal(t *testing.T) {
+	cases := map[string]planfile.RepoRecord{
+		"internal": {IsPrivate: true, Visibility: "internal"},
+		"private":  {IsPrivate: true},
+		"public":   {},
+	}
+	for want, r := range cases {
+		if got := RepoVisibility(r); got != want {
+			t.Errorf("RepoVisibility(%+v) = %q, want %q", r, got, want)
+		}
+	}
+}
+
+func TestDecompressBundle(t *testing.T) {
+	dir := t.TempDir()
+	gz := filepath.Join(dir, "alice__demo.bundle.gz")
+	f, err := os.Create(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write([]byte("bundle data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	plain, err := DecompressBundle(gz, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(plain)
+	if b, _ := os.ReadFile(plain); string(b) != "bundle data" || filepath.Dir(plain) != dir {
+		t.Fatalf("unexpected decompressed bundle %s: %q", plain, b)
+	}
+	if _, err := DecompressBundle(plain, dir); err == nil {
+		t.Fatal("expected an error for a file that is not gzipped")
+	}
+}
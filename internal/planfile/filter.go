@@ -0,0 +1,96 @@
+package planfile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RepoFilter reports whether a repo should stay in the candidate list.
+type RepoFilter func(RepoRecord) bool
+
+func ExcludeForks(r RepoRecord) bool {
+	return !r.IsFork
+}
+
+func ExcludeArchived(r RepoRecord) bool {
+	return !r.IsArchived
+}
+
+// VisibilityFilter keeps only private or only public repos. "all" (or "")
+// returns a nil filter.
+func VisibilityFilter(visibility string) (RepoFilter, error) {
+	switch visibility {
+	case "", "all":
+		return nil, nil
+	case "private":
+		return func(r RepoRecord) bool { return r.IsPrivate }, nil
+	case "public":
+		return func(r RepoRecord) bool { return !r.IsPrivate }, nil
+	default:
+		return nil, fmt.Errorf("unsupported visibility %q (want private|public|all)", visibility)
+	}
+}
+
+// UpdatedBetween keeps repos updated at or after `after` and strictly before
+// `before`; a zero bound is open. Repos whose UpdatedAt is empty or
+// unparseable are kept only when includeUnknown is set.
+func UpdatedBetween(after, before time.Time, includeUnknown bool) RepoFilter {
+	return func(r RepoRecord) bool {
+		at, ok := ParseUpdatedAt(r.UpdatedAt)
+		if !ok {
+			return includeUnknown
+		}
+		if !after.IsZero() && at.Before(after) {
+			return false
+		}
+		if !before.IsZero() && !at.Before(before) {
+			return false
+		}
+		return true
+	}
+}
+
+// FilterRepos keeps the repos that pass every filter and returns how many
+// were dropped.
+func FilterRepos(repos []RepoRecord, filters ...RepoFilter) ([]RepoRecord, int) {
+	if len(filters) == 0 {
+		return repos, 0
+	}
+	out := make([]RepoRecord, 0, len(repos))
+	for _, r := range repos {
+		keep := true
+		for _, f := range filters {
+			if !f(r) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out = append(out, r)
+		}
+	}
+	return out, len(repos) - len(out)
+}
+
+func ParseUpdatedAt(v string) (time.Time, bool) {
+	if v == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// ParseDate accepts YYYY, YYYY-MM, YYYY-MM-DD (UTC midnight) or RFC3339.
+func ParseDate(v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	for _, layout := range []string{time.RFC3339, "2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (want YYYY, YYYY-MM, YYYY-MM-DD or RFC3339)", v)
+}
@@ -0,0 +1,98 @@
+package planfile
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterReposComposesFilters(t *testing.T) {
+	repos := []RepoRecord{
+		{FullName: "alice/keep"},
+		{FullName: "alice/fork", IsFork: true},
+		{FullName: "alice/old", IsArchived: true},
+		{FullName: "alice/old-fork", IsFork: true, IsArchived: true},
+	}
+
+	kept, dropped := FilterRepos(repos)
+	if len(kept) != 4 || dropped != 0 {
+		t.Fatalf("expected no filtering without filters, kept=%d dropped=%d", len(kept), dropped)
+	}
+
+	kept, dropped = FilterRepos(repos, ExcludeForks)
+	if len(kept) != 2 || dropped != 2 || kept[1].FullName != "alice/old" {
+		t.Fatalf("unexpected fork filtering: kept=%v dropped=%d", kept, dropped)
+	}
+
+	kept, dropped = FilterRepos(repos, ExcludeForks, ExcludeArchived)
+	if len(kept) != 1 || dropped != 3 || kept[0].FullName != "alice/keep" {
+		t.Fatalf("unexpected combined filtering: kept=%v dropped=%d", kept, dropped)
+	}
+}
+
+func TestVisibilityFilterCombinesWithOtherFilters(t *testing.T) {
+	repos := []RepoRecord{
+		{FullName: "alice/pub"},
+		{FullName: "alice/priv", IsPrivate: true},
+		{FullName: "alice/priv-fork", IsPrivate: true, IsFork: true},
+		{FullName: "alice/pub-old", IsArchived: true},
+	}
+	private, err := VisibilityFilter("private")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, dropped := FilterRepos(repos, private, ExcludeForks)
+	if len(kept) != 1 || dropped != 3 || kept[0].FullName != "alice/priv" {
+		t.Fatalf("unexpected private filtering: kept=%v dropped=%d", kept, dropped)
+	}
+	public, err := VisibilityFilter("public")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, dropped = FilterRepos(repos, public)
+	if len(kept) != 2 || dropped != 2 {
+		t.Fatalf("unexpected public filtering: kept=%v dropped=%d", kept, dropped)
+	}
+	if f, err := VisibilityFilter("all"); err != nil || f != nil {
+		t.Fatalf("all should disable the filter, got %v", err)
+	}
+	if _, err := VisibilityFilter("internal"); err == nil {
+		t.Fatal("expected an error for unsupported visibility")
+	}
+}
+
+func TestUpdatedBetweenHandlesBoundsAndUnknownDates(t *testing.T) {
+	repos := []RepoRecord{
+		{FullName: "alice/old", UpdatedAt: "2022-06-01T00:00:00Z"},
+		{FullName: "alice/edge", UpdatedAt: "2023-01-01T00:00:00Z"},
+		{FullName: "alice/new", UpdatedAt: "2024-03-01T12:00:00Z"},
+		{FullName: "alice/unknown", UpdatedAt: ""},
+		{FullName: "alice/garbage", UpdatedAt: "yesterday"},
+	}
+	before, err := ParseDate("2023")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept, dropped := FilterRepos(repos, UpdatedBetween(time.Time{}, before, false))
+	if len(kept) != 1 || kept[0].FullName != "alice/old" || dropped != 4 {
+		t.Fatalf("unexpected updated-before result: %v", kept)
+	}
+
+	kept, _ = FilterRepos(repos, UpdatedBetween(time.Time{}, before, true))
+	if len(kept) != 3 || kept[1].FullName != "alice/unknown" || kept[2].FullName != "alice/garbage" {
+		t.Fatalf("expected unknown dates to be included on request: %v", kept)
+	}
+
+	after, err := ParseDate("2023-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, _ = FilterRepos(repos, UpdatedBetween(after, time.Time{}, false))
+	if len(kept) != 2 || kept[0].FullName != "alice/edge" || kept[1].FullName != "alice/new" {
+		t.Fatalf("expected after bound to be inclusive: %v", kept)
+	}
+
+	if _, err := ParseDate("last year"); err == nil {
+		t.Fatal("expected invalid date error")
+	}
+}
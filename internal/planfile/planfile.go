@@ -1,6 +1,7 @@
 package planfile
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
@@ -21,9 +22,19 @@ type RepoRecord struct {
 	FullName    string `json:"fullName"`
 	Description string `json:"description"`
 	IsPrivate   bool   `json:"isPrivate"`
-	IsFork      bool   `json:"isFork"`
-	IsArchived  bool   `json:"isArchived"`
-	UpdatedAt   string `json:"updatedAt"`
+	// Visibility is private, public or internal as GitHub reports it; empty in
+	// plans written before it was recorded.
+	Visibility string `json:"visibility,omitempty"`
+	IsFork     bool   `json:"isFork"`
+	IsArchived bool   `json:"isArchived"`
+	UpdatedAt  string `json:"updatedAt"`
+	// DiskUsage is the size GitHub reports for the repo, in KiB.
+	DiskUsage int64 `json:"diskUsage,omitempty"`
+	// HeadSHA is the default-branch HEAD captured at plan time (plan --capture-head).
+	HeadSHA string `json:"headSha,omitempty"`
+	// Language is the primary language GitHub detected, if any.
+	Language string   `json:"language,omitempty"`
+	Topics   []string `json:"topics,omitempty"`
 }
 
 type DeletionPlanV1 struct {
@@ -80,9 +91,19 @@ func (p *DeletionPlanV1) Sign(secret []byte) error {
 }
 
 func (p DeletionPlanV1) Validate(secret []byte) error {
+	return p.ValidateAny([][]byte{secret})
+}
+
+// ValidateAny is Validate with several candidate secrets; the signature
+// only has to match one, so plans signed before a secret rotation still
+// validate.
+func (p DeletionPlanV1) ValidateAny(secrets [][]byte) error {
 	if p.SchemaVersion != "v1" {
 		return fmt.Errorf("unsupported schemaVersion: %s", p.SchemaVersion)
 	}
+	if strings.TrimSpace(p.Host) == "" {
+		return errors.New("missing host")
+	}
 	if p.Count != len(p.Repos) {
 		return fmt.Errorf("count mismatch: count=%d repos=%d", p.Count, len(p.Repos))
 	}
@@ -96,13 +117,15 @@ func (p DeletionPlanV1) Validate(secret []byte) error {
 	if p.Fingerprint != fp {
 		return errors.New("fingerprint mismatch")
 	}
-	h := hmac.New(sha256.New, secret)
-	h.Write([]byte(fp))
-	expected := hex.EncodeToString(h.Sum(nil))
-	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(p.Signature))) {
-		return errors.New("invalid signature")
+	for _, secret := range secrets {
+		h := hmac.New(sha256.New, secret)
+		h.Write([]byte(fp))
+		expected := hex.EncodeToString(h.Sum(nil))
+		if hmac.Equal([]byte(expected), []byte(strings.ToLower(p.Signature))) {
+			return nil
+		}
 	}
-	return nil
+	return errors.New("invalid signature")
 }
 
 func (p DeletionPlanV1) ComputeFingerprint() (string, error) {
@@ -126,7 +149,11 @@ func (p DeletionPlanV1) ComputeFingerprint() (string, error) {
 	return hex.EncodeToString(h[:]), nil
 }
 
+// Write saves the plan as YAML when path ends in .yaml/.yml, otherwise as JSON.
 func Write(path string, p DeletionPlanV1) error {
+	if FormatForPath(path) == FormatYAML {
+		return WriteYAML(path, p)
+	}
 	b, err := json.MarshalIndent(p, "", "  ")
 	if err != nil {
 		return err
@@ -141,6 +168,9 @@ func Read(path string) (DeletionPlanV1, error) {
 	if err != nil {
 		return p, err
 	}
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] != '{' {
+		return UnmarshalYAML(b)
+	}
 	if err := json.Unmarshal(b, &p); err != nil {
 		return p, err
 	}
@@ -153,14 +183,7 @@ func EnsureSecret(configDir string) ([]byte, error) {
 	}
 	secretPath := filepath.Join(configDir, "secret.hex")
 	if b, err := os.ReadFile(secretPath); err == nil {
-		raw, decErr := hex.DecodeString(strings.TrimSpace(string(b)))
-		if decErr != nil {
-			return nil, fmt.Errorf("invalid secret format: %w", decErr)
-		}
-		if len(raw) < 32 {
-			return nil, errors.New("secret too short")
-		}
-		return raw, nil
+		return decodeSecret(b)
 	}
 
 	raw := make([]byte, 32)
@@ -173,3 +196,46 @@ func EnsureSecret(configDir string) ([]byte, error) {
 	}
 	return raw, nil
 }
+
+// VerificationSecrets returns the primary secret from EnsureSecret followed
+// by the retired secret.old.hex when present.
+func VerificationSecrets(configDir string) ([][]byte, error) {
+	primary, err := EnsureSecret(configDir)
+	if err != nil {
+		return nil, err
+	}
+	secrets := [][]byte{primary}
+	b, err := os.ReadFile(filepath.Join(configDir, "secret.old.hex"))
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	old, err := decodeSecret(b)
+	if err != nil {
+		return nil, fmt.Errorf("secret.old.hex: %w", err)
+	}
+	return append(secrets, old), nil
+}
+
+// ReadSecret loads a hex signing secret from an explicit path. Unlike
+// EnsureSecret it never creates the file.
+func ReadSecret(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read secret file: %w", err)
+	}
+	return decodeSecret(b)
+}
+
+func decodeSecret(b []byte) ([]byte, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret format: %w", err)
+	}
+	if len(raw) < 32 {
+		return nil, errors.New("secret too short")
+	}
+	return raw, nil
+}
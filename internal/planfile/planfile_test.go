@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -26,6 +27,25 @@ func TestPlanSignValidateAndTamperDetection(t *testing.T) {
 	}
 }
 
+func TestPlanValidateEnterpriseHost(t *testing.T) {
+	secret := []byte("01234567890123456789012345678901")
+	plan := New("alice", "ghe.example.com", "test", []RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, time.Now())
+	if err := plan.Sign(secret); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := plan.Validate(secret); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	plan.Host = "github.com"
+	if err := plan.Validate(secret); err == nil {
+		t.Fatal("expected host tamper validation error")
+	}
+	plan.Host = ""
+	if err := plan.Validate(secret); err == nil {
+		t.Fatal("expected missing host validation error")
+	}
+}
+
 func TestEnsureSecret(t *testing.T) {
 	d := t.TempDir()
 	secret, err := EnsureSecret(d)
@@ -44,6 +64,70 @@ func TestEnsureSecret(t *testing.T) {
 	}
 }
 
+func TestValidateAcceptsRetiredSecretAfterRotation(t *testing.T) {
+	d := t.TempDir()
+	oldSecret, err := EnsureSecret(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := New("alice", "github.com", "test", []RepoRecord{{Owner: "alice", Name: "r1", FullName: "alice/r1"}}, time.Now())
+	if err := plan.Sign(oldSecret); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate: retire the current secret and let a new primary be generated.
+	if err := os.Rename(filepath.Join(d, "secret.hex"), filepath.Join(d, "secret.old.hex")); err != nil {
+		t.Fatal(err)
+	}
+	secrets, err := VerificationSecrets(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 2 || string(secrets[0]) == string(oldSecret) || string(secrets[1]) != string(oldSecret) {
+		t.Fatal("expected a new primary followed by the retired secret")
+	}
+	if err := plan.Validate(secrets[0]); err == nil {
+		t.Fatal("new primary alone should not validate an old plan")
+	}
+	if err := plan.ValidateAny(secrets); err != nil {
+		t.Fatalf("old plan should validate during rotation: %v", err)
+	}
+
+	plan.Repos[0].Name = "tampered"
+	if err := plan.ValidateAny(secrets); err == nil {
+		t.Fatal("expected tampered plan to fail with any secret")
+	}
+}
+
+func TestReadSecret(t *testing.T) {
+	d := t.TempDir()
+	want, err := EnsureSecret(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadSecret(filepath.Join(d, "secret.hex"))
+	if err != nil {
+		t.Fatalf("read secret: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatal("expected the same secret from an explicit path")
+	}
+
+	short := filepath.Join(d, "short.hex")
+	if err := os.WriteFile(short, []byte("abcd\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSecret(short); err == nil || !strings.Contains(err.Error(), "too short") {
+		t.Fatalf("expected too-short error, got %v", err)
+	}
+	if _, err := ReadSecret(filepath.Join(d, "missing.hex")); err == nil {
+		t.Fatal("expected error for a missing secret file")
+	}
+	if _, err := os.Stat(filepath.Join(d, "missing.hex")); !os.IsNotExist(err) {
+		t.Fatal("ReadSecret must not create the file")
+	}
+}
+
 func TestWriteReadRoundTrip(t *testing.T) {
 	p := New("alice", "github.com", "test", nil, time.Now())
 	p.Fingerprint = "f"
@@ -65,3 +149,44 @@ func TestWriteReadRoundTrip(t *testing.T) {
 		t.Fatalf("file missing: %v", err)
 	}
 }
+
+func TestYAMLPlanRoundTripValidates(t *testing.T) {
+	secret := []byte("01234567890123456789012345678901")
+	plan := New("alice", "github.com", "test", []RepoRecord{
+		{Owner: "alice", Name: "r2", FullName: "alice/r2", Description: "quotes \" colon: # hash\nnewline", IsFork: true, DiskUsage: 2048, HeadSHA: "abc123"},
+		{Owner: "alice", Name: "r1", FullName: "alice/r1", IsPrivate: true, UpdatedAt: "2026-01-02T03:04:05Z", Language: "Go", Topics: []string{"cli", "deprecated"}},
+	}, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err := plan.Sign(secret); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	if err := Write(path, plan); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	if raw[0] == '{' {
+		t.Fatalf("expected YAML output, got %s", raw)
+	}
+	out, err := Read(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if err := out.Validate(secret); err != nil {
+		t.Fatalf("yaml plan should validate: %v\n%s", err, raw)
+	}
+	inJSON, _ := json.Marshal(plan)
+	outJSON, _ := json.Marshal(out)
+	if string(inJSON) != string(outJSON) {
+		t.Fatalf("roundtrip mismatch:\n%s\n%s", inJSON, outJSON)
+	}
+
+	// Hand-edited YAML with plain scalars and comments still parses.
+	hand := []byte("# reviewed\nschemaVersion: v1\ncreatedAt: '2026-02-01T00:00:00Z'\nactor: alice\nhost: github.com\nrepos:\n  - owner: alice\n    name: r1 # keep\n    fullName: alice/r1\n    isPrivate: true\n    topics: [cli, 'old tools']\ncount: 1\n")
+	hp, err := UnmarshalYAML(hand)
+	if err != nil {
+		t.Fatalf("parse hand-written yaml: %v", err)
+	}
+	if hp.Count != 1 || len(hp.Repos) != 1 || hp.Repos[0].Name != "r1" || !hp.Repos[0].IsPrivate || hp.CreatedAt != "2026-02-01T00:00:00Z" || len(hp.Repos[0].Topics) != 2 || hp.Repos[0].Topics[1] != "old tools" {
+		t.Fatalf("unexpected plan: %+v", hp)
+	}
+}
@@ -0,0 +1,45 @@
+package planfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProtectedMatcher compiles protected_repos patterns into one matcher. A
+// pattern is an exact owner/name, a glob or a regex, as in selection files.
+func ProtectedMatcher(patterns []string) (func(fullName string) bool, error) {
+	var matchers []func(string) bool
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		m, err := SelectionMatcher(p)
+		if err != nil {
+			return nil, fmt.Errorf("protected repo pattern %q: %w", p, err)
+		}
+		matchers = append(matchers, m)
+	}
+	return func(fullName string) bool {
+		for _, m := range matchers {
+			if m(fullName) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// ProtectedRepos returns the full names in repos that match patterns.
+func ProtectedRepos(repos []RepoRecord, patterns []string) ([]string, error) {
+	isProtected, err := ProtectedMatcher(patterns)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, r := range repos {
+		if isProtected(r.FullName) {
+			out = append(out, r.FullName)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,26 @@
+package planfile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProtectedRepos(t *testing.T) {
+	repos := []RepoRecord{{FullName: "alice/infra"}, {FullName: "Alice/Prod-API"}, {FullName: "alice/scratch"}, {FullName: "bob/keep"}}
+	got, err := ProtectedRepos(repos, []string{"alice/infra", "alice/prod-*", " ", "/^bob/", "re:^alice/prod-api$"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "alice/infra,Alice/Prod-API,bob/keep" {
+		t.Fatalf("unexpected protected repos: %v", got)
+	}
+	if got, err := ProtectedRepos(repos, []string{"/^ALICE/PROD/"}); err != nil || strings.Join(got, ",") != "Alice/Prod-API" {
+		t.Fatalf("regex patterns should ignore case: %v %v", got, err)
+	}
+	if got, err := ProtectedRepos(repos, nil); err != nil || len(got) != 0 {
+		t.Fatalf("expected nothing protected without patterns: %v %v", got, err)
+	}
+	if _, err := ProtectedMatcher([]string{"alice/[x"}); err == nil || !strings.Contains(err.Error(), "alice/[x") {
+		t.Fatalf("expected invalid pattern error, got %v", err)
+	}
+}
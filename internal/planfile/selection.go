@@ -0,0 +1,105 @@
+package planfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ReadSelectionFile reads one owner/name per line; blank lines and lines
+// starting with # are skipped.
+func ReadSelectionFile(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names, sc.Err()
+}
+
+func WriteSelectionFile(path string, fullNames []string) error {
+	var b strings.Builder
+	for _, n := range fullNames {
+		b.WriteString(n + "\n")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o600)
+}
+
+// ExpandSelection resolves selection lines against repos and returns the
+// matched repos in repos order. A line is an exact owner/name, a glob such
+// as alice/test-*, or a regular expression written as /expr/ or re:expr; all
+// three match case-insensitively, like GitHub names. Every line must match at
+// least one repo.
+func ExpandSelection(repos []RepoRecord, lines []string) ([]RepoRecord, error) {
+	matchers := make([]func(string) bool, 0, len(lines))
+	for _, line := range lines {
+		m, err := SelectionMatcher(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", line, err)
+		}
+		matchers = append(matchers, m)
+	}
+	hit := make([]bool, len(lines))
+	var out []RepoRecord
+	for _, r := range repos {
+		matched := false
+		for i, m := range matchers {
+			if m(r.FullName) {
+				hit[i] = true
+				matched = true
+			}
+		}
+		if matched {
+			out = append(out, r)
+		}
+	}
+	var missing []string
+	for i, line := range lines {
+		if !hit[i] {
+			missing = append(missing, line)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no repos match: %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// SelectionMatcher compiles one selection line (see ExpandSelection) into a
+// matcher over full names. The TUI's Select Matching uses it too.
+func SelectionMatcher(line string) (func(string) bool, error) {
+	expr, isRegex := strings.CutPrefix(line, "re:")
+	if !isRegex && len(line) > 2 && strings.HasPrefix(line, "/") && strings.HasSuffix(line, "/") {
+		expr, isRegex = line[1:len(line)-1], true
+	}
+	if isRegex {
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		return re.MatchString, nil
+	}
+	pattern := strings.ToLower(line)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return func(name string) bool { return strings.ToLower(name) == pattern }, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob: %w", err)
+	}
+	return func(name string) bool {
+		ok, _ := path.Match(pattern, strings.ToLower(name))
+		return ok
+	}, nil
+}
@@ -0,0 +1,84 @@
+package planfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSelectionFileRoundTripAndExpandSelection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "selection.txt")
+	if err := WriteSelectionFile(path, []string{"alice/a", "alice/c"}); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, append([]byte("# exported\n\n"), b...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	names, err := ReadSelectionFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "alice/a" || names[1] != "alice/c" {
+		t.Fatalf("unexpected names: %v", names)
+	}
+
+	repos := []RepoRecord{{FullName: "alice/a"}, {FullName: "alice/b"}, {FullName: "Alice/C"}}
+	selected, err := ExpandSelection(repos, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 2 || selected[0].FullName != "alice/a" || selected[1].FullName != "Alice/C" {
+		t.Fatalf("unexpected selection: %v", selected)
+	}
+	if _, err := ExpandSelection(repos, []string{"alice/a", "alice/gone"}); err == nil || !strings.Contains(err.Error(), "alice/gone") {
+		t.Fatalf("expected missing repo error, got %v", err)
+	}
+}
+
+func TestExpandSelectionPatterns(t *testing.T) {
+	repos := []RepoRecord{
+		{FullName: "alice/test-one"},
+		{FullName: "alice/keep"},
+		{FullName: "Alice/Test-Two"},
+		{FullName: "bob/tmp-1"},
+		{FullName: "bob/tmp-22"},
+	}
+	names := func(rs []RepoRecord) string {
+		var out []string
+		for _, r := range rs {
+			out = append(out, r.FullName)
+		}
+		return strings.Join(out, ",")
+	}
+	cases := []struct {
+		lines []string
+		want  string
+	}{
+		{[]string{"alice/test-*"}, "alice/test-one,Alice/Test-Two"},
+		{[]string{`/^bob/tmp-\d$/`}, "bob/tmp-1"},
+		{[]string{"re:-22$", "alice/keep"}, "alice/keep,bob/tmp-22"},
+		{[]string{"bob/*", "bob/tmp-1"}, "bob/tmp-1,bob/tmp-22"},
+	}
+	for _, tc := range cases {
+		got, err := ExpandSelection(repos, tc.lines)
+		if err != nil {
+			t.Fatalf("%v: %v", tc.lines, err)
+		}
+		if names(got) != tc.want {
+			t.Fatalf("%v: got %s want %s", tc.lines, names(got), tc.want)
+		}
+	}
+
+	_, err := ExpandSelection(repos, []string{"alice/*", "carol/*", "/^x/"})
+	if err == nil || err.Error() != "no repos match: carol/*, /^x/" {
+		t.Fatalf("expected unmatched patterns error, got %v", err)
+	}
+	if _, err := ExpandSelection(repos, []string{"/[/"}); err == nil || !strings.Contains(err.Error(), "invalid regex") {
+		t.Fatalf("expected invalid regex error, got %v", err)
+	}
+	if _, err := ExpandSelection(repos, []string{"alice/[a"}); err == nil || !strings.Contains(err.Error(), "invalid glob") {
+		t.Fatalf("expected invalid glob error, got %v", err)
+	}
+}
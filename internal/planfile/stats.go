@@ -0,0 +1,74 @@
+package planfile
+
+import (
+	"fmt"
+	"time"
+)
+
+// RepoStats aggregates a repo listing for the stats command.
+type RepoStats struct {
+	Total    int `json:"total"`
+	Private  int `json:"private"`
+	Public   int `json:"public"`
+	Forks    int `json:"forks"`
+	Sources  int `json:"sources"`
+	Archived int `json:"archived"`
+	// DiskUsage is the summed size in KiB; SizedRepos counts repos that reported one.
+	DiskUsage  int64  `json:"diskUsage"`
+	SizedRepos int    `json:"sizedRepos"`
+	Oldest     string `json:"oldestUpdatedAt,omitempty"`
+	Newest     string `json:"newestUpdatedAt,omitempty"`
+}
+
+func SummarizeRepos(repos []RepoRecord) RepoStats {
+	var s RepoStats
+	var oldest, newest time.Time
+	for _, r := range repos {
+		s.Total++
+		if r.IsPrivate {
+			s.Private++
+		} else {
+			s.Public++
+		}
+		if r.IsFork {
+			s.Forks++
+		} else {
+			s.Sources++
+		}
+		if r.IsArchived {
+			s.Archived++
+		}
+		if r.DiskUsage > 0 {
+			s.DiskUsage += r.DiskUsage
+			s.SizedRepos++
+		}
+		t, ok := ParseUpdatedAt(r.UpdatedAt)
+		if !ok {
+			continue
+		}
+		if oldest.IsZero() || t.Before(oldest) {
+			oldest, s.Oldest = t, r.UpdatedAt
+		}
+		if newest.IsZero() || t.After(newest) {
+			newest, s.Newest = t, r.UpdatedAt
+		}
+	}
+	return s
+}
+
+// FormatDiskUsage renders a GitHub diskUsage value (KiB) in binary units.
+func FormatDiskUsage(kib int64) string {
+	if kib <= 0 {
+		return "-"
+	}
+	const unit = 1024
+	if kib < unit {
+		return fmt.Sprintf("%d KiB", kib)
+	}
+	div, exp := int64(unit), 0
+	for v := kib / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(kib)/float64(div), "MGTPE"[exp])
+}
@@ -0,0 +1,27 @@
+package planfile
+
+import "testing"
+
+func TestSummarizeRepos(t *testing.T) {
+	repos := []RepoRecord{
+		{FullName: "alice/a", IsPrivate: true, UpdatedAt: "2023-05-01T00:00:00Z", DiskUsage: 1024},
+		{FullName: "alice/b", IsFork: true, UpdatedAt: "2021-01-02T00:00:00Z", DiskUsage: 512},
+		{FullName: "alice/c", IsArchived: true, UpdatedAt: "2025-09-30T12:00:00Z"},
+		{FullName: "alice/d", IsPrivate: true, IsFork: true},
+	}
+	s := SummarizeRepos(repos)
+	want := RepoStats{
+		Total: 4, Private: 2, Public: 2, Forks: 2, Sources: 2, Archived: 1,
+		DiskUsage: 1536, SizedRepos: 2,
+		Oldest: "2021-01-02T00:00:00Z", Newest: "2025-09-30T12:00:00Z",
+	}
+	if s != want {
+		t.Fatalf("unexpected stats:\n got %+v\nwant %+v", s, want)
+	}
+	if got := FormatDiskUsage(s.DiskUsage); got != "1.5 MiB" {
+		t.Fatalf("unexpected formatted size %q", got)
+	}
+	if empty := SummarizeRepos(nil); empty != (RepoStats{}) {
+		t.Fatalf("expected zero stats for no repos, got %+v", empty)
+	}
+}
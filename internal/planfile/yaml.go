@@ -0,0 +1,292 @@
+package planfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// FormatForPath picks the plan format from a file extension; anything other
+// than .yaml/.yml is JSON.
+func FormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// WriteYAML writes p as YAML. The fingerprint and signature are always
+// computed over the canonical JSON form, so a YAML plan validates exactly
+// like the JSON one it was converted from.
+func WriteYAML(path string, p DeletionPlanV1) error {
+	b, err := MarshalYAML(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o600)
+}
+
+func ReadYAML(path string) (DeletionPlanV1, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return DeletionPlanV1{}, err
+	}
+	return UnmarshalYAML(b)
+}
+
+// MarshalYAML renders the plan as block-style YAML, using the JSON field
+// names. Strings are always double-quoted so values never change type.
+func MarshalYAML(p DeletionPlanV1) ([]byte, error) {
+	var buf bytes.Buffer
+	scalar := func(indent, key string, v any) error {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "%s%s: %s\n", indent, key, b)
+		return nil
+	}
+	top := func(key string, v any) error { return scalar("", key, v) }
+	for _, kv := range []struct {
+		key string
+		v   any
+	}{
+		{"schemaVersion", p.SchemaVersion},
+		{"createdAt", p.CreatedAt},
+		{"actor", p.Actor},
+		{"host", p.Host},
+	} {
+		if err := top(kv.key, kv.v); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.Repos) == 0 {
+		buf.WriteString("repos: []\n")
+	} else {
+		buf.WriteString("repos:\n")
+	}
+	for _, r := range p.Repos {
+		fields := []struct {
+			key string
+			v   any
+		}{
+			{"owner", r.Owner},
+			{"name", r.Name},
+			{"fullName", r.FullName},
+			{"description", r.Description},
+			{"isPrivate", r.IsPrivate},
+			{"isFork", r.IsFork},
+			{"isArchived", r.IsArchived},
+			{"updatedAt", r.UpdatedAt},
+		}
+		if r.DiskUsage != 0 {
+			fields = append(fields, struct {
+				key string
+				v   any
+			}{"diskUsage", r.DiskUsage})
+		}
+		if r.HeadSHA != "" {
+			fields = append(fields, struct {
+				key string
+				v   any
+			}{"headSha", r.HeadSHA})
+		}
+		if r.Language != "" {
+			fields = append(fields, struct {
+				key string
+				v   any
+			}{"language", r.Language})
+		}
+		if len(r.Topics) > 0 {
+			// A JSON array is a valid YAML flow sequence.
+			fields = append(fields, struct {
+				key string
+				v   any
+			}{"topics", r.Topics})
+		}
+		for i, f := range fields {
+			indent := "    "
+			if i == 0 {
+				indent = "  - "
+			}
+			if err := scalar(indent, f.key, f.v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, kv := range []struct {
+		key string
+		v   any
+	}{
+		{"count", p.Count},
+		{"fingerprint", p.Fingerprint},
+		{"signature", p.Signature},
+		{"toolVersion", p.ToolVersion},
+	} {
+		if err := top(kv.key, kv.v); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalYAML parses the subset of YAML that MarshalYAML produces (plus
+// comments, plain and single-quoted scalars) by mapping it onto the JSON
+// representation, so field names and types follow the JSON tags.
+func UnmarshalYAML(b []byte) (DeletionPlanV1, error) {
+	var p DeletionPlanV1
+	doc := map[string]any{}
+	var repos []any
+	var cur map[string]any
+	inRepos := false
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		raw := strings.TrimRight(sc.Text(), " \t\r")
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		indented := raw[0] == ' '
+		if !indented {
+			inRepos = false
+			key, val, err := splitYAMLPair(trimmed)
+			if err != nil {
+				return p, fmt.Errorf("yaml line %d: %w", lineNo, err)
+			}
+			if key == "repos" {
+				switch val {
+				case "":
+					inRepos = true
+				case "[]":
+				default:
+					return p, fmt.Errorf("yaml line %d: repos must be a block list", lineNo)
+				}
+				continue
+			}
+			v, err := parseYAMLScalar(val)
+			if err != nil {
+				return p, fmt.Errorf("yaml line %d: %w", lineNo, err)
+			}
+			doc[key] = v
+			continue
+		}
+		if !inRepos {
+			return p, fmt.Errorf("yaml line %d: unexpected indentation", lineNo)
+		}
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			cur = map[string]any{}
+			repos = append(repos, cur)
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			if trimmed == "" {
+				continue
+			}
+		} else if cur == nil {
+			return p, fmt.Errorf("yaml line %d: expected a list item", lineNo)
+		}
+		key, val, err := splitYAMLPair(trimmed)
+		if err != nil {
+			return p, fmt.Errorf("yaml line %d: %w", lineNo, err)
+		}
+		v, err := parseYAMLScalar(val)
+		if err != nil {
+			return p, fmt.Errorf("yaml line %d: %w", lineNo, err)
+		}
+		cur[key] = v
+	}
+	if err := sc.Err(); err != nil {
+		return p, err
+	}
+	if repos != nil {
+		doc["repos"] = repos
+	}
+	js, err := json.Marshal(doc)
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(js, &p); err != nil {
+		return p, fmt.Errorf("yaml plan: %w", err)
+	}
+	return p, nil
+}
+
+func splitYAMLPair(s string) (string, string, error) {
+	i := strings.Index(s, ":")
+	if i <= 0 || (i+1 < len(s) && s[i+1] != ' ') {
+		return "", "", fmt.Errorf("expected key: value, got %q", s)
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:]), nil
+}
+
+func parseYAMLScalar(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "["):
+		return parseYAMLFlowSeq(s)
+	case strings.HasPrefix(s, `"`):
+		var out string
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			return nil, fmt.Errorf("invalid double-quoted string %s", s)
+		}
+		return out, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return nil, fmt.Errorf("invalid single-quoted string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	switch s {
+	case "", "~", "null":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	return s, nil
+}
+
+// parseYAMLFlowSeq accepts JSON arrays as written by MarshalYAML, and plain
+// `[a, b]` sequences of scalars as typed by hand.
+func parseYAMLFlowSeq(s string) (any, error) {
+	var out []any
+	if err := json.Unmarshal([]byte(s), &out); err == nil {
+		return out, nil
+	}
+	if i := strings.LastIndex(s, "]"); i > 0 {
+		s = s[:i+1]
+	}
+	if !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("invalid flow sequence %s", s)
+	}
+	out = []any{}
+	for _, item := range strings.Split(s[1:len(s)-1], ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		v, err := parseYAMLScalar(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
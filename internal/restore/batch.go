@@ -0,0 +1,46 @@
+package restore
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult is the outcome of one request in a RestoreAll batch.
+type BatchResult struct {
+	Request Request
+	Result  Result
+	Err     error
+}
+
+// nameClaims records the target names taken so far in a batch.
+type nameClaims struct {
+	mu    sync.Mutex
+	names map[string]bool
+}
+
+// RestoreAll runs reqs with at most concurrency restores in flight (values
+// below 1 mean one at a time). Each restore uses its own temp workdir; target
+// names are claimed under a shared lock so two restores never create the
+// same repo. Results come back in request order, and a failed restore does
+// not stop the others.
+func (s Service) RestoreAll(ctx context.Context, reqs []Request, concurrency int) []BatchResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	s.claims = &nameClaims{names: map[string]bool{}}
+	results := make([]BatchResult, len(reqs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, req Request) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := s.Restore(ctx, req)
+			results[i] = BatchResult{Request: req, Result: res, Err: err}
+		}(i, req)
+	}
+	wg.Wait()
+	return results
+}
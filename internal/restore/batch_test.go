@@ -0,0 +1,139 @@
+package restore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gh-manager/internal/app"
+)
+
+// batchRunner is a concurrency-safe fake that records created repos and the
+// peak number of clones in flight.
+type batchRunner struct {
+	mu       sync.Mutex
+	existing map[string]bool
+	created  []string
+	inflight int
+	peak     int
+}
+
+func (b *batchRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	key := name + " " + strings.Join(args, " ")
+	switch {
+	case strings.HasPrefix(key, "gh repo view "):
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.existing[args[2]] {
+			return []byte("ok"), nil
+		}
+		return nil, errors.New("HTTP 404: Not Found")
+	case strings.HasPrefix(key, "gh repo create "):
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.existing[args[2]] {
+			return nil, errors.New("name already exists on this account")
+		}
+		b.existing[args[2]] = true
+		b.created = append(b.created, args[2])
+	case strings.HasPrefix(key, "git clone "):
+		b.mu.Lock()
+		b.inflight++
+		b.peak = max(b.peak, b.inflight)
+		b.mu.Unlock()
+		// Hold the clone until a second restore overlaps with it (or give up).
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			b.mu.Lock()
+			overlapped := b.peak > 1
+			b.mu.Unlock()
+			if overlapped {
+				break
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		b.mu.Lock()
+		b.inflight--
+		b.mu.Unlock()
+	}
+	return []byte("ok"), nil
+}
+
+func TestRestoreAllRunsConcurrentlyWithoutNameCollisions(t *testing.T) {
+	dir := t.TempDir()
+	var reqs []Request
+	// Two owners share a repo name, so {name} maps three sources onto "tools".
+	for _, full := range []string{"alice/tools", "bob/tools", "carol/tools", "alice/web", "missing/repo"} {
+		path := filepath.Join(dir, strings.ReplaceAll(full, "/", "__")+".bundle")
+		if full != "missing/repo" {
+			if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		reqs = append(reqs, Request{RepoFullName: full, SourceKind: "bundle", SourcePath: path, TargetOwner: "dana", NameTemplate: "{name}"})
+	}
+	r := &batchRunner{existing: map[string]bool{"dana/tools": true}}
+	results := NewService(r, "").RestoreAll(context.Background(), reqs, 3)
+
+	if len(results) != len(reqs) {
+		t.Fatalf("expected %d results, got %d", len(reqs), len(results))
+	}
+	for i, res := range results {
+		if res.Request.RepoFullName != reqs[i].RepoFullName {
+			t.Fatalf("results out of order at %d: %s", i, res.Request.RepoFullName)
+		}
+	}
+	if results[4].Err == nil {
+		t.Fatal("expected the missing source to fail")
+	}
+	var targets []string
+	for _, res := range results[:4] {
+		if res.Err != nil {
+			t.Fatalf("restore %s failed: %v", res.Request.RepoFullName, res.Err)
+		}
+		targets = append(targets, res.Result.TargetFullName)
+	}
+	slices.Sort(targets)
+	if strings.Join(targets, ",") != "dana/tools-2,dana/tools-3,dana/tools-4,dana/web" {
+		t.Fatalf("unexpected targets: %v", targets)
+	}
+	if len(r.created) != 4 {
+		t.Fatalf("expected 4 creates, got %v", r.created)
+	}
+	if r.peak < 2 {
+		t.Fatalf("expected restores to overlap, peak in flight %d", r.peak)
+	}
+}
+
+// Run with -race: every request carries a host, so each restore wraps the
+// shared rate-limited runner for it.
+func TestRestoreAllWithHostKeepsSharedRunnerUntouched(t *testing.T) {
+	dir := t.TempDir()
+	var reqs []Request
+	for _, full := range []string{"alice/a", "alice/b", "alice/c"} {
+		path := filepath.Join(dir, strings.ReplaceAll(full, "/", "__")+".bundle")
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		reqs = append(reqs, Request{RepoFullName: full, SourceKind: "bundle", SourcePath: path, TargetOwner: "dana", NameTemplate: "{name}", Host: "ghe.example.com"})
+	}
+	inner := &batchRunner{existing: map[string]bool{}}
+	runner := app.NewRateLimitRunner(inner, 600000)
+	for _, res := range NewService(runner, "").RestoreAll(context.Background(), reqs, 3) {
+		if res.Err != nil {
+			t.Fatalf("restore %s failed: %v", res.Request.RepoFullName, res.Err)
+		}
+	}
+	if rl := runner.(*app.RateLimitRunner); rl.Runner != app.CommandRunner(inner) {
+		t.Fatalf("expected the caller's runner to stay unchanged, got %#v", rl.Runner)
+	}
+	if len(inner.created) != 3 {
+		t.Fatalf("expected 3 creates, got %v", inner.created)
+	}
+}
@@ -11,15 +11,24 @@ import (
 )
 
 type ArchiveEntry struct {
-	FullName     string
-	BundlePath   string
+	FullName   string
+	BundlePath string
+	// BundleSHA256 is the hash an archive manifest recorded for BundlePath.
+	BundleSHA256 string
 	SnapshotPath string
+	LFSPath      string
 	UpdatedAt    string
+	// Visibility is the source repo's recorded visibility, if known.
+	Visibility string
+	// SnapshotTarPath is a snapshot packed as .tar, .tar.gz or .tgz.
+	SnapshotTarPath string
 }
 
 type Source struct {
 	Kind string
 	Path string
+	// SHA256 is the expected bundle hash, when known.
+	SHA256 string
 }
 
 func LoadIndex(root string) ([]ArchiveEntry, error) {
@@ -43,7 +52,7 @@ func LoadIndex(root string) ([]ArchiveEntry, error) {
 		if e.FullName == "" {
 			continue
 		}
-		if e.BundlePath == "" && e.SnapshotPath == "" {
+		if e.BundlePath == "" && e.SnapshotPath == "" && e.SnapshotTarPath == "" {
 			continue
 		}
 		out = append(out, *e)
@@ -55,7 +64,7 @@ func LoadIndex(root string) ([]ArchiveEntry, error) {
 func PreferredSource(e ArchiveEntry) (Source, bool) {
 	if e.BundlePath != "" {
 		if fi, err := os.Stat(e.BundlePath); err == nil && !fi.IsDir() {
-			return Source{Kind: "bundle", Path: e.BundlePath}, true
+			return Source{Kind: "bundle", Path: e.BundlePath, SHA256: e.BundleSHA256}, true
 		}
 	}
 	if e.SnapshotPath != "" {
@@ -63,6 +72,11 @@ func PreferredSource(e ArchiveEntry) (Source, bool) {
 			return Source{Kind: "snapshot", Path: e.SnapshotPath}, true
 		}
 	}
+	if e.SnapshotTarPath != "" {
+		if fi, err := os.Stat(e.SnapshotTarPath); err == nil && !fi.IsDir() {
+			return Source{Kind: "snapshot-tar", Path: e.SnapshotTarPath}, true
+		}
+	}
 	return Source{}, false
 }
 
@@ -100,12 +114,31 @@ func loadFromManifest(root string, out map[string]*ArchiveEntry) error {
 		}
 		e := ensureEntry(out, re.FullName)
 		e.UpdatedAt = firstNonEmpty(e.UpdatedAt, re.LastAttemptAt)
+		e.Visibility = firstNonEmpty(e.Visibility, re.Visibility)
 		if re.BundlePath != "" {
 			e.BundlePath = resolvePath(root, re.BundlePath)
 		}
 		if re.BrowsablePath != "" {
 			e.SnapshotPath = resolvePath(root, re.BrowsablePath)
 		}
+		if re.LFS && re.LFSPath != "" {
+			e.LFSPath = resolvePath(root, re.LFSPath)
+		}
+	}
+	// An archive repo folder's manifest.json lists bundles with their hashes.
+	am, err := manifest.ReadArchive(path)
+	if err != nil {
+		return err
+	}
+	for _, b := range am.Bundles {
+		if b.FullName == "" || b.BundleFile == "" {
+			continue
+		}
+		e := ensureEntry(out, b.FullName)
+		e.BundlePath = resolvePath(root, filepath.FromSlash(b.BundleFile))
+		e.BundleSHA256 = b.SHA256
+		e.UpdatedAt = firstNonEmpty(e.UpdatedAt, b.UpdatedAt)
+		e.Visibility = firstNonEmpty(e.Visibility, b.Visibility)
 	}
 	return nil
 }
@@ -120,7 +153,7 @@ func scanBundles(root string, out map[string]*ArchiveEntry) error {
 		return err
 	}
 	for _, ent := range ents {
-		if ent.IsDir() || !strings.HasSuffix(ent.Name(), ".bundle") {
+		if ent.IsDir() || !(strings.HasSuffix(ent.Name(), ".bundle") || strings.HasSuffix(ent.Name(), ".bundle.gz")) {
 			continue
 		}
 		fullName, ok := bundleNameToFullName(ent.Name())
@@ -128,7 +161,10 @@ func scanBundles(root string, out map[string]*ArchiveEntry) error {
 			continue
 		}
 		e := ensureEntry(out, fullName)
-		e.BundlePath = filepath.Join(dir, ent.Name())
+		if path := filepath.Join(dir, ent.Name()); e.BundlePath != path {
+			e.BundlePath = path
+			e.BundleSHA256 = ""
+		}
 	}
 	return nil
 }
@@ -144,6 +180,15 @@ func scanSnapshots(root string, out map[string]*ArchiveEntry) error {
 	}
 	for _, ent := range ents {
 		if !ent.IsDir() {
+			base, ok := trimSnapshotTarSuffix(ent.Name())
+			if !ok {
+				continue
+			}
+			fullName, ok := snapshotNameToFullName(base)
+			if !ok {
+				continue
+			}
+			ensureEntry(out, fullName).SnapshotTarPath = filepath.Join(dir, ent.Name())
 			continue
 		}
 		fullName, ok := snapshotNameToFullName(ent.Name())
@@ -167,7 +212,7 @@ func ensureEntry(out map[string]*ArchiveEntry, fullName string) *ArchiveEntry {
 }
 
 func bundleNameToFullName(filename string) (string, bool) {
-	base := strings.TrimSuffix(filename, ".bundle")
+	base := strings.TrimSuffix(strings.TrimSuffix(filename, ".gz"), ".bundle")
 	parts := strings.SplitN(base, "__", 2)
 	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
 		return "", false
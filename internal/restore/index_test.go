@@ -12,7 +12,7 @@ func TestLoadIndexFromManifestAndFallback(t *testing.T) {
   "schemaVersion":"v1",
   "mode":"backup",
   "repoExecutions":[
-    {"fullName":"alice/repo1","bundlePath":"` + filepath.ToSlash(filepath.Join(root, "bundles", "alice__repo1.bundle")) + `","browsablePath":"` + filepath.ToSlash(filepath.Join(root, "snapshots", "alice__repo1")) + `"}
+    {"fullName":"alice/repo1","visibility":"private","bundlePath":"` + filepath.ToSlash(filepath.Join(root, "bundles", "alice__repo1.bundle")) + `","browsablePath":"` + filepath.ToSlash(filepath.Join(root, "snapshots", "alice__repo1")) + `"}
   ]
 }`
 	if err := os.WriteFile(filepath.Join(root, "manifest.json"), []byte(manifest), 0o600); err != nil {
@@ -38,6 +38,9 @@ func TestLoadIndexFromManifestAndFallback(t *testing.T) {
 	if entries[0].FullName != "alice/repo1" || entries[1].FullName != "alice/repo2" {
 		t.Fatalf("unexpected entries: %#v", entries)
 	}
+	if entries[0].Visibility != "private" || entries[1].Visibility != "" {
+		t.Fatalf("expected recorded visibility only for repo1: %#v", entries)
+	}
 }
 
 func TestPreferredSourceBundleFirst(t *testing.T) {
@@ -69,3 +72,28 @@ func TestBundleNameToFullName(t *testing.T) {
 		t.Fatalf("unexpected full name: %s", got)
 	}
 }
+
+func TestLoadIndexReadsArchiveManifestChecksums(t *testing.T) {
+	root := t.TempDir()
+	archive := `{"planFingerprint":"fp","bundles":[{"fullName":"alice/repo1","bundleFile":"bundles/alice__repo1.bundle","sha256":"abc123","visibility":"public"}]}`
+	if err := os.WriteFile(filepath.Join(root, "manifest.json"), []byte(archive), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "bundles"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "bundles", "alice__repo1.bundle"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := LoadIndex(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].BundleSHA256 != "abc123" || entries[0].Visibility != "public" {
+		t.Fatalf("expected recorded checksum, got %#v", entries)
+	}
+	src, ok := PreferredSource(entries[0])
+	if !ok || src.Kind != "bundle" || src.SHA256 != "abc123" {
+		t.Fatalf("expected checksum on bundle source, got %#v", src)
+	}
+}
@@ -8,31 +8,62 @@ import (
 	"strings"
 
 	"gh-manager/internal/app"
+	"gh-manager/internal/github"
+	"gh-manager/internal/manifest"
 )
 
 type Service struct {
 	runner app.CommandRunner
+	host   string
+	// claims is shared by the restores of one RestoreAll batch.
+	claims *nameClaims
 }
 
-func NewService(r app.CommandRunner) Service {
-	return Service{runner: r}
+func NewService(r app.CommandRunner, host string) Service {
+	return Service{runner: r, host: host}
 }
 
 type Request struct {
-	ArchiveRoot      string
-	RepoFullName     string
-	SourceKind       string
-	SourcePath       string
+	ArchiveRoot  string
+	RepoFullName string
+	SourceKind   string
+	SourcePath   string
+	// SourceSHA256 is the bundle hash recorded by the archive manifest; when
+	// set, the bundle is verified before cloning.
+	SourceSHA256     string
 	TargetOwner      string
 	TargetName       string
 	TargetVisibility string
+	// LFSPath holds Git LFS objects to push before the refs; empty skips LFS.
+	LFSPath string
+	// TargetBranch overrides the default branch detected from the source.
+	TargetBranch string
+	// KeepWorkDir leaves the temporary clone in place after a successful
+	// restore; failed restores always keep it for debugging.
+	KeepWorkDir bool
+	// NameTemplate, when set, replaces TargetName with the expansion of
+	// {owner} and {name} from RepoFullName, and conflicts are resolved by
+	// taking the first free numeric suffix instead of failing.
+	NameTemplate string
+	// SourceVisibility is the visibility recorded for the source repo; empty
+	// means unknown and skips the visibility guard.
+	SourceVisibility string
+	// AllowVisibilityChange confirms a restore whose TargetVisibility is more
+	// permissive than SourceVisibility.
+	AllowVisibilityChange bool
+	// Host is the GitHub host to restore to; empty uses the service's host.
+	// It sets GH_HOST for gh calls and the host of the push remote, so an
+	// archive taken from github.com can be restored to an enterprise host.
+	Host string
 }
 
 type Result struct {
 	TargetFullName string
-	WorkDir        string
-	SourceKind     string
-	SourcePath     string
+	// WorkDir is the temporary clone, or "" once it has been removed.
+	WorkDir       string
+	SourceKind    string
+	SourcePath    string
+	DefaultBranch string
 }
 
 type TargetExistsError struct {
@@ -48,10 +79,48 @@ func (e TargetExistsError) SuggestedName() string {
 	return e.Suggested
 }
 
+// VisibilityDowngradeError reports a restore that would expose the repo more
+// widely than its source (for example private to public). Retry with
+// Request.AllowVisibilityChange once the user has confirmed.
+type VisibilityDowngradeError struct {
+	Source string
+	Target string
+}
+
+func (e VisibilityDowngradeError) Error() string {
+	return fmt.Sprintf("restoring a %s repo as %s requires confirmation", e.Source, e.Target)
+}
+
+// visibilityRank orders visibilities from least to most permissive.
+var visibilityRank = map[string]int{"private": 0, "internal": 1, "public": 2}
+
+// checkVisibility validates target and, when source is known, refuses a more
+// permissive target unless allow is set.
+func checkVisibility(source, target string, allow bool) error {
+	if _, err := github.VisibilityFlag(target); err != nil {
+		return err
+	}
+	if s, known := visibilityRank[source]; known && visibilityRank[target] > s && !allow {
+		return VisibilityDowngradeError{Source: source, Target: target}
+	}
+	return nil
+}
+
+// maxNameSuffix bounds how many numbered names are probed on a conflict.
+const maxNameSuffix = 20
+
 func (s Service) Restore(ctx context.Context, req Request) (Result, error) {
 	if s.runner == nil {
 		return Result{}, fmt.Errorf("restore runner is nil")
 	}
+	s = s.forHost(req.Host)
+	if req.NameTemplate != "" {
+		name, err := ExpandNameTemplate(req.NameTemplate, req.RepoFullName)
+		if err != nil {
+			return Result{}, err
+		}
+		req.TargetName = name
+	}
 	if strings.TrimSpace(req.SourcePath) == "" {
 		return Result{}, fmt.Errorf("source path is required")
 	}
@@ -61,43 +130,71 @@ func (s Service) Restore(ctx context.Context, req Request) (Result, error) {
 	if req.TargetVisibility == "" {
 		req.TargetVisibility = "private"
 	}
-	if req.TargetVisibility != "private" && req.TargetVisibility != "public" {
-		return Result{}, fmt.Errorf("unsupported visibility: %s", req.TargetVisibility)
+	if err := checkVisibility(req.SourceVisibility, req.TargetVisibility, req.AllowVisibilityChange); err != nil {
+		return Result{}, err
 	}
 	targetFullName := req.TargetOwner + "/" + req.TargetName
 
 	if err := validateSource(req.SourceKind, req.SourcePath); err != nil {
 		return Result{}, err
 	}
-	if exists, err := repoExists(ctx, s.runner, targetFullName); err != nil {
+	if req.SourceKind == "bundle" && req.SourceSHA256 != "" {
+		if err := verifyBundle(req.SourcePath, req.SourceSHA256); err != nil {
+			return Result{}, err
+		}
+	}
+	cloneSource := req.SourcePath
+	if req.SourceKind == "snapshot-tar" {
+		tmp, repo, err := extractSnapshotTar(req.SourcePath)
+		if err != nil {
+			return Result{}, err
+		}
+		defer os.RemoveAll(tmp)
+		cloneSource = repo
+	}
+	name, err := s.claimTarget(ctx, req)
+	if err != nil {
 		return Result{}, err
-	} else if exists {
-		return Result{}, TargetExistsError{TargetFullName: targetFullName, Suggested: req.TargetName + "-ghm"}
 	}
+	req.TargetName = name
+	targetFullName = req.TargetOwner + "/" + name
 
 	workdir, err := os.MkdirTemp("", "gh-manager-restore-*")
 	if err != nil {
 		return Result{}, err
 	}
 
-	if _, err := s.runner.Run(ctx, "git", "clone", req.SourcePath, workdir); err != nil {
+	if req.SourceKind == "bundle" && manifest.BundleCompression(req.SourcePath) == manifest.CompressionGzip {
+		plain, err := manifest.DecompressBundle(req.SourcePath, "")
+		if err != nil {
+			return Result{}, err
+		}
+		defer os.Remove(plain)
+		cloneSource = plain
+	}
+	if _, err := s.runner.Run(ctx, "git", "clone", cloneSource, workdir); err != nil {
 		return Result{}, err
 	}
 
-	visFlag := "--private"
-	if req.TargetVisibility == "public" {
-		visFlag = "--public"
+	visFlag, err := github.VisibilityFlag(req.TargetVisibility)
+	if err != nil {
+		return Result{}, err
 	}
 	if _, err := s.runner.Run(ctx, "gh", "repo", "create", targetFullName, visFlag, "--confirm"); err != nil {
 		return Result{}, err
 	}
 
-	remote := "git@github.com:" + targetFullName + ".git"
+	remote := app.SSHRemote(s.host, targetFullName)
 	if _, err := s.runner.Run(ctx, "git", "-C", workdir, "remote", "set-url", "origin", remote); err != nil {
 		if _, addErr := s.runner.Run(ctx, "git", "-C", workdir, "remote", "add", "origin", remote); addErr != nil {
 			return Result{}, err
 		}
 	}
+	if req.LFSPath != "" {
+		if _, err := s.runner.Run(ctx, "git", "-C", workdir, "-c", "lfs.storage="+req.LFSPath, "lfs", "push", "--all", "origin"); err != nil {
+			return Result{}, err
+		}
+	}
 	if _, err := s.runner.Run(ctx, "git", "-C", workdir, "push", "--all", "origin"); err != nil {
 		return Result{}, err
 	}
@@ -105,14 +202,162 @@ func (s Service) Restore(ctx context.Context, req Request) (Result, error) {
 		return Result{}, err
 	}
 
+	branch := strings.TrimSpace(req.TargetBranch)
+	if branch == "" {
+		branch = sourceDefaultBranch(ctx, s.runner, workdir)
+	}
+	if branch != "" {
+		if _, err := s.runner.Run(ctx, "gh", "repo", "edit", targetFullName, "--default-branch", branch); err != nil {
+			return Result{}, fmt.Errorf("set default branch %s: %w", branch, err)
+		}
+	}
+
+	if !req.KeepWorkDir {
+		if err := os.RemoveAll(workdir); err == nil {
+			workdir = ""
+		}
+	}
 	return Result{
 		TargetFullName: targetFullName,
 		WorkDir:        workdir,
 		SourceKind:     req.SourceKind,
 		SourcePath:     req.SourcePath,
+		DefaultBranch:  branch,
 	}, nil
 }
 
+// forHost returns a copy of s targeting host, or s itself when host is empty
+// or already the service's host.
+func (s Service) forHost(host string) Service {
+	host = strings.TrimSpace(host)
+	if host == "" || host == s.host {
+		return s
+	}
+	return Service{runner: app.WithHost(s.runner, host), host: host, claims: s.claims}
+}
+
+// ExpandNameTemplate fills {owner} and {name} from a source owner/name.
+func ExpandNameTemplate(tmpl, sourceFullName string) (string, error) {
+	owner, name, ok := strings.Cut(sourceFullName, "/")
+	if !ok || owner == "" || name == "" {
+		return "", fmt.Errorf("name template needs a source owner/name, got %q", sourceFullName)
+	}
+	out := strings.NewReplacer("{owner}", owner, "{name}", name).Replace(tmpl)
+	if strings.ContainsAny(out, "{}") {
+		return "", fmt.Errorf("unsupported placeholder in name template %q (want {owner} and {name})", tmpl)
+	}
+	if err := ValidateRepoName(out); err != nil {
+		return "", fmt.Errorf("name template %q expands to invalid repo name %q: %w", tmpl, out, err)
+	}
+	return out, nil
+}
+
+// maxRepoNameLen is GitHub's limit on repository name length.
+const maxRepoNameLen = 100
+
+// ValidateRepoName rejects names GitHub would refuse or silently rewrite.
+func ValidateRepoName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("name is empty")
+	case name == "." || name == "..":
+		return fmt.Errorf("name %q is reserved", name)
+	case len(name) > maxRepoNameLen:
+		return fmt.Errorf("name is longer than %d characters", maxRepoNameLen)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return fmt.Errorf("character %q is not allowed (use letters, digits, '.', '-' or '_')", r)
+		}
+	}
+	return nil
+}
+
+// claimTarget settles the target name: req.TargetName when it is free,
+// otherwise the first free numbered name for templated requests. Inside
+// RestoreAll the check and the claim happen under one lock, so concurrent
+// restores never pick the same name.
+func (s Service) claimTarget(ctx context.Context, req Request) (string, error) {
+	if s.claims != nil {
+		s.claims.mu.Lock()
+		defer s.claims.mu.Unlock()
+	}
+	name := req.TargetName
+	targetFullName := req.TargetOwner + "/" + name
+	if exists, err := s.targetExists(ctx, targetFullName); err != nil {
+		return "", err
+	} else if exists {
+		if req.NameTemplate == "" {
+			suggested, err := s.freeName(ctx, req.TargetOwner, name, "-ghm")
+			if err != nil {
+				return "", err
+			}
+			return "", TargetExistsError{TargetFullName: targetFullName, Suggested: suggested}
+		}
+		if name, err = s.freeName(ctx, req.TargetOwner, name, ""); err != nil {
+			return "", err
+		}
+	}
+	if s.claims != nil {
+		s.claims.names[strings.ToLower(req.TargetOwner+"/"+name)] = true
+	}
+	return name, nil
+}
+
+// targetExists reports a repo on GitHub or one already claimed in this batch.
+func (s Service) targetExists(ctx context.Context, fullName string) (bool, error) {
+	if s.claims != nil && s.claims.names[strings.ToLower(fullName)] {
+		return true, nil
+	}
+	return repoExists(ctx, s.runner, fullName)
+}
+
+// freeName returns the first target name under owner that does not exist
+// yet. With a tag it tries base+tag, base+tag-2, ...; without one it tries
+// base-2, base-3, ... It gives up after maxNameSuffix candidates.
+func (s Service) freeName(ctx context.Context, owner, base, tag string) (string, error) {
+	first := 2
+	if tag != "" {
+		first = 1
+	}
+	for i := first; i <= maxNameSuffix; i++ {
+		candidate := base + tag
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", candidate, i)
+		}
+		exists, err := s.targetExists(ctx, owner+"/"+candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("target repository %s/%s exists and no free name was found after %d attempts (%s%s-%d); pass a different name", owner, base, maxNameSuffix-first+1, base, tag, maxNameSuffix)
+}
+
+func verifyBundle(path, want string) error {
+	got, err := manifest.FileSHA256(path)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(got, strings.TrimSpace(want)) {
+		return fmt.Errorf("bundle checksum mismatch for %s: archive manifest has sha256 %s, file has %s (truncated or corrupted bundle?)", path, want, got)
+	}
+	return nil
+}
+
+// sourceDefaultBranch reads the branch the clone checked out, which follows
+// the bundle's HEAD ref or the snapshot's HEAD. It returns "" when HEAD is
+// detached or unreadable.
+func sourceDefaultBranch(ctx context.Context, runner app.CommandRunner, workdir string) string {
+	out, err := runner.Run(ctx, "git", "-C", workdir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
 func validateSource(kind, path string) error {
 	st, err := os.Stat(path)
 	if err != nil {
@@ -123,13 +368,20 @@ func validateSource(kind, path string) error {
 		if st.IsDir() {
 			return fmt.Errorf("bundle source must be a file: %s", path)
 		}
-		if filepath.Ext(path) != ".bundle" {
-			return fmt.Errorf("bundle source must end with .bundle: %s", path)
+		if filepath.Ext(path) != ".bundle" && manifest.BundleCompression(path) == "" {
+			return fmt.Errorf("bundle source must end with .bundle or .bundle.gz: %s", path)
 		}
 	case "snapshot":
 		if !st.IsDir() {
 			return fmt.Errorf("snapshot source must be a directory: %s", path)
 		}
+	case "snapshot-tar":
+		if st.IsDir() {
+			return fmt.Errorf("snapshot-tar source must be a file: %s", path)
+		}
+		if _, ok := trimSnapshotTarSuffix(filepath.Base(path)); !ok {
+			return fmt.Errorf("snapshot-tar source must end with .tar, .tar.gz or .tgz: %s", path)
+		}
 	default:
 		return fmt.Errorf("unsupported source kind: %s", kind)
 	}
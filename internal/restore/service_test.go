@@ -1,23 +1,34 @@
 package restore
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"gh-manager/internal/app"
 )
 
 type fakeRunner struct {
 	calls [][]string
 	fail  map[string]error
+	out   map[string]string
 }
 
 func (f *fakeRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
 	call := append([]string{name}, args...)
 	f.calls = append(f.calls, call)
 	key := strings.Join(call, " ")
+	for suffix, out := range f.out {
+		if strings.HasSuffix(key, suffix) {
+			return []byte(out), nil
+		}
+	}
 	if err, ok := f.fail[key]; ok {
 		if err != nil {
 			return nil, err
@@ -30,6 +41,45 @@ func (f *fakeRunner) Run(_ context.Context, name string, args ...string) ([]byte
 	return []byte("ok"), nil
 }
 
+type cloneCapture struct {
+	fakeRunner
+	cloned []byte
+}
+
+func (c *cloneCapture) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if name == "git" && len(args) == 3 && args[0] == "clone" {
+		c.cloned, _ = os.ReadFile(args[1])
+	}
+	return c.fakeRunner.Run(ctx, name, args...)
+}
+
+func TestRestoreDecompressesGzipBundle(t *testing.T) {
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle.gz")
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte("bundle contents"))
+	_ = zw.Close()
+	if err := os.WriteFile(bundle, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := &cloneCapture{}
+	_, err := NewService(r, "").Restore(context.Background(), Request{SourceKind: "bundle", SourcePath: bundle, TargetOwner: "alice", TargetName: "repo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(r.cloned) != "bundle contents" {
+		t.Fatalf("expected clone from decompressed bundle, got %q", r.cloned)
+	}
+	plain := r.calls[1][2]
+	if !strings.HasSuffix(plain, ".bundle") {
+		t.Fatalf("expected clone of a .bundle file, got %s", plain)
+	}
+	if _, err := os.Stat(plain); !os.IsNotExist(err) {
+		t.Fatalf("expected decompressed bundle removed, stat err=%v", err)
+	}
+}
+
 func TestRestoreBundleSuccess(t *testing.T) {
 	root := t.TempDir()
 	bundle := filepath.Join(root, "alice__repo.bundle")
@@ -37,7 +87,7 @@ func TestRestoreBundleSuccess(t *testing.T) {
 		t.Fatal(err)
 	}
 	r := &fakeRunner{fail: map[string]error{}}
-	s := NewService(r)
+	s := NewService(r, "")
 	res, err := s.Restore(context.Background(), Request{
 		SourceKind:       "bundle",
 		SourcePath:       bundle,
@@ -54,15 +104,64 @@ func TestRestoreBundleSuccess(t *testing.T) {
 	joined := flatten(r.calls)
 	mustContain(t, joined, "git clone "+bundle)
 	mustContain(t, joined, "gh repo create alice/repo-restored --private --confirm")
-	mustContain(t, joined, "git -C "+res.WorkDir+" push --all origin")
-	mustContain(t, joined, "git -C "+res.WorkDir+" push --tags origin")
+	workdir := r.calls[1][3]
+	mustContain(t, joined, "git -C "+workdir+" push --all origin")
+	mustContain(t, joined, "git -C "+workdir+" push --tags origin")
+	if res.WorkDir != "" {
+		t.Fatalf("expected WorkDir cleared after cleanup, got %q", res.WorkDir)
+	}
+	if _, err := os.Stat(workdir); !os.IsNotExist(err) {
+		t.Fatalf("expected workdir removed, stat err=%v", err)
+	}
+}
+
+func TestRestoreKeepsWorkDirOnFailure(t *testing.T) {
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := &fakeRunner{fail: map[string]error{"gh repo create alice/repo --private --confirm": errors.New("HTTP 403")}}
+	if _, err := NewService(r, "").Restore(context.Background(), Request{SourceKind: "bundle", SourcePath: bundle, TargetOwner: "alice", TargetName: "repo"}); err == nil {
+		t.Fatal("expected create failure")
+	}
+	workdir := r.calls[1][3]
+	t.Cleanup(func() { _ = os.RemoveAll(workdir) })
+	if _, err := os.Stat(workdir); err != nil {
+		t.Fatalf("expected workdir kept for debugging: %v", err)
+	}
+}
+
+func TestRestoreVerifiesBundleChecksum(t *testing.T) {
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const sumX = "2d711642b726b04401627ca9fbac32f5c8530fb1903cc4db02258717921a4881"
+	req := Request{SourceKind: "bundle", SourcePath: bundle, TargetOwner: "alice", TargetName: "repo", SourceSHA256: strings.Repeat("0", 64)}
+
+	r := &fakeRunner{fail: map[string]error{}}
+	_, err := NewService(r, "").Restore(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "bundle checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if strings.Contains(flatten(r.calls), "git clone") {
+		t.Fatalf("expected no clone after mismatch: %s", flatten(r.calls))
+	}
+
+	req.SourceSHA256 = sumX
+	r = &fakeRunner{fail: map[string]error{}}
+	if _, err := NewService(r, "").Restore(context.Background(), req); err != nil {
+		t.Fatalf("expected matching checksum to restore: %v", err)
+	}
 }
 
 func TestRestoreConflict(t *testing.T) {
 	r := &fakeRunner{fail: map[string]error{
 		"gh repo view alice/existing --json name --jq .name": nil,
 	}}
-	s := NewService(r)
+	s := NewService(r, "")
 	root := t.TempDir()
 	bundle := filepath.Join(root, "alice__repo.bundle")
 	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
@@ -86,6 +185,85 @@ func TestRestoreConflict(t *testing.T) {
 	}
 }
 
+func TestRestoreSuggestionSkipsExistingNames(t *testing.T) {
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	exists := map[string]error{}
+	for _, name := range []string{"repo", "repo-ghm", "repo-ghm-2", "repo-ghm-3"} {
+		exists["gh repo view alice/"+name+" --json name --jq .name"] = nil
+	}
+	req := Request{SourceKind: "bundle", SourcePath: bundle, TargetOwner: "alice", TargetName: "repo"}
+	_, err := NewService(&fakeRunner{fail: exists}, "").Restore(context.Background(), req)
+	var c TargetExistsError
+	if !errors.As(err, &c) || c.SuggestedName() != "repo-ghm-4" {
+		t.Fatalf("expected free suggestion repo-ghm-4, got %v (%q)", err, c.SuggestedName())
+	}
+
+	// Retrying with a taken suggestion probes again from the new base.
+	req.TargetName = "repo-ghm-2"
+	exists["gh repo view alice/repo-ghm-2-ghm --json name --jq .name"] = nil
+	_, err = NewService(&fakeRunner{fail: exists}, "").Restore(context.Background(), req)
+	if !errors.As(err, &c) || c.SuggestedName() != "repo-ghm-2-ghm-2" {
+		t.Fatalf("unexpected retry suggestion: %v (%q)", err, c.SuggestedName())
+	}
+
+	for i := 2; i <= maxNameSuffix; i++ {
+		exists[fmt.Sprintf("gh repo view alice/repo-ghm-%d --json name --jq .name", i)] = nil
+	}
+	req.TargetName = "repo"
+	_, err = NewService(&fakeRunner{fail: exists}, "").Restore(context.Background(), req)
+	if err == nil || errors.As(err, &c) || !strings.Contains(err.Error(), "no free name") {
+		t.Fatalf("expected a clear exhaustion error, got %v", err)
+	}
+}
+
+func TestExpandNameTemplate(t *testing.T) {
+	got, err := ExpandNameTemplate("{owner}-{name}-restored", "alice/repo")
+	if err != nil || got != "alice-repo-restored" {
+		t.Fatalf("unexpected expansion: %q %v", got, err)
+	}
+	if got, err := ExpandNameTemplate("old-{name}", "alice/repo"); err != nil || got != "old-repo" {
+		t.Fatalf("unexpected prefix expansion: %q %v", got, err)
+	}
+	for _, tmpl := range []string{"{repo}-x", "{owner}/{name}", "   ", "my {name}"} {
+		if _, err := ExpandNameTemplate(tmpl, "alice/repo"); err == nil {
+			t.Fatalf("expected error for template %q", tmpl)
+		}
+	}
+	if _, err := ExpandNameTemplate("{name}", "repo"); err == nil {
+		t.Fatal("expected error without a source owner")
+	}
+}
+
+func TestRestoreNameTemplateIncrementsOnConflict(t *testing.T) {
+	r := &fakeRunner{fail: map[string]error{
+		"gh repo view bob/alice-repo-restored --json name --jq .name":   nil,
+		"gh repo view bob/alice-repo-restored-2 --json name --jq .name": nil,
+	}}
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := NewService(r, "").Restore(context.Background(), Request{
+		RepoFullName: "alice/repo",
+		SourceKind:   "bundle",
+		SourcePath:   bundle,
+		TargetOwner:  "bob",
+		NameTemplate: "{owner}-{name}-restored",
+	})
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if res.TargetFullName != "bob/alice-repo-restored-3" {
+		t.Fatalf("expected first free numbered name, got %s", res.TargetFullName)
+	}
+	mustContain(t, flatten(r.calls), "gh repo create bob/alice-repo-restored-3 --private --confirm")
+}
+
 func TestRestoreSnapshotFallback(t *testing.T) {
 	root := t.TempDir()
 	snap := filepath.Join(root, "snapshot")
@@ -93,7 +271,7 @@ func TestRestoreSnapshotFallback(t *testing.T) {
 		t.Fatal(err)
 	}
 	r := &fakeRunner{fail: map[string]error{}}
-	s := NewService(r)
+	s := NewService(r, "")
 	_, err := s.Restore(context.Background(), Request{
 		SourceKind:  "snapshot",
 		SourcePath:  snap,
@@ -120,3 +298,192 @@ func mustContain(t *testing.T, text, sub string) {
 		t.Fatalf("missing %q in:\n%s", sub, text)
 	}
 }
+
+func TestRestorePushesLFSBeforeRefs(t *testing.T) {
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lfs := filepath.Join(root, "lfs", "alice__repo")
+	r := &fakeRunner{fail: map[string]error{}}
+	res, err := NewService(r, "").Restore(context.Background(), Request{
+		SourceKind:  "bundle",
+		SourcePath:  bundle,
+		TargetOwner: "alice",
+		TargetName:  "repo",
+		LFSPath:     lfs,
+		KeepWorkDir: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(res.WorkDir) })
+	joined := flatten(r.calls)
+	lfsPush := "git -C " + res.WorkDir + " -c lfs.storage=" + lfs + " lfs push --all origin"
+	refPush := "git -C " + res.WorkDir + " push --all origin"
+	mustContain(t, joined, lfsPush)
+	if strings.Index(joined, lfsPush) > strings.Index(joined, refPush) {
+		t.Fatalf("expected LFS push before ref push:\n%s", joined)
+	}
+}
+
+func TestRestoreSetsDefaultBranch(t *testing.T) {
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	req := Request{SourceKind: "bundle", SourcePath: bundle, TargetOwner: "alice", TargetName: "repo"}
+
+	r := &fakeRunner{fail: map[string]error{}, out: map[string]string{"symbolic-ref --short HEAD": "trunk\n"}}
+	res, err := NewService(r, "").Restore(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	joined := flatten(r.calls)
+	mustContain(t, joined, "gh repo edit alice/repo --default-branch trunk")
+	if res.DefaultBranch != "trunk" || !strings.HasSuffix(joined, "--default-branch trunk") {
+		t.Fatalf("expected default branch to be set after pushing: %s\n%s", res.DefaultBranch, joined)
+	}
+
+	req.TargetBranch = "release"
+	r = &fakeRunner{fail: map[string]error{}, out: map[string]string{"symbolic-ref --short HEAD": "trunk\n"}}
+	if _, err := NewService(r, "").Restore(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	joined = flatten(r.calls)
+	mustContain(t, joined, "gh repo edit alice/repo --default-branch release")
+	if strings.Contains(joined, "symbolic-ref") {
+		t.Fatalf("override should skip detection:\n%s", joined)
+	}
+}
+
+func TestRestoreUsesEnterpriseHostRemote(t *testing.T) {
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := &fakeRunner{fail: map[string]error{}}
+	s := NewService(r, "ghe.example.com")
+	if _, err := s.Restore(context.Background(), Request{
+		ArchiveRoot:  root,
+		RepoFullName: "alice/repo",
+		SourceKind:   "bundle",
+		SourcePath:   bundle,
+		TargetOwner:  "alice",
+		TargetName:   "repo",
+	}); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	want := "git@ghe.example.com:alice/repo.git"
+	for _, c := range r.calls {
+		if strings.Contains(strings.Join(c, " "), "remote set-url origin "+want) {
+			return
+		}
+	}
+	t.Fatalf("expected enterprise remote %s in calls: %v", want, r.calls)
+}
+
+func TestRestoreRequestHostOverridesServiceHost(t *testing.T) {
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := &fakeRunner{fail: map[string]error{}}
+	s := NewService(r, "github.com")
+	if _, err := s.Restore(context.Background(), Request{
+		ArchiveRoot:  root,
+		RepoFullName: "alice/repo",
+		SourceKind:   "bundle",
+		SourcePath:   bundle,
+		TargetOwner:  "corp",
+		TargetName:   "repo",
+		Host:         "ghe.example.com",
+	}); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	want := "remote set-url origin git@ghe.example.com:corp/repo.git"
+	found := false
+	for _, c := range r.calls {
+		if strings.Contains(strings.Join(c, " "), want) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected %q in calls: %v", want, r.calls)
+	}
+
+	exec := NewService(app.ExecRunner{Host: "github.com"}, "github.com").forHost("ghe.example.com")
+	if got := exec.runner.(app.ExecRunner).Host; got != "ghe.example.com" || exec.host != "ghe.example.com" {
+		t.Fatalf("expected gh calls to target ghe.example.com, got runner host %q service host %q", got, exec.host)
+	}
+	if same := NewService(r, "github.com").forHost(""); same.host != "github.com" {
+		t.Fatalf("empty request host should keep the service host, got %q", same.host)
+	}
+}
+
+func TestRestoreVisibilityTransitions(t *testing.T) {
+	root := t.TempDir()
+	bundle := filepath.Join(root, "alice__repo.bundle")
+	if err := os.WriteFile(bundle, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		source, target string
+		needsConfirm   bool
+	}{
+		{"", "public", false},
+		{"", "internal", false},
+		{"private", "private", false},
+		{"private", "internal", true},
+		{"private", "public", true},
+		{"internal", "private", false},
+		{"internal", "internal", false},
+		{"internal", "public", true},
+		{"public", "private", false},
+		{"public", "internal", false},
+		{"public", "public", false},
+	}
+	for _, tc := range cases {
+		req := Request{SourceKind: "bundle", SourcePath: bundle, TargetOwner: "alice", TargetName: "repo", SourceVisibility: tc.source, TargetVisibility: tc.target}
+		r := &fakeRunner{}
+		_, err := NewService(r, "").Restore(context.Background(), req)
+		var downgrade VisibilityDowngradeError
+		if got := errors.As(err, &downgrade); got != tc.needsConfirm {
+			t.Fatalf("%s -> %s: needsConfirm=%t, err=%v", tc.source, tc.target, tc.needsConfirm, err)
+		}
+		if tc.needsConfirm {
+			if downgrade.Source != tc.source || downgrade.Target != tc.target || len(r.calls) != 0 {
+				t.Fatalf("%s -> %s: unexpected error %+v or calls %v", tc.source, tc.target, downgrade, r.calls)
+			}
+			req.AllowVisibilityChange = true
+			if _, err = NewService(r, "").Restore(context.Background(), req); err != nil {
+				t.Fatalf("%s -> %s confirmed: %v", tc.source, tc.target, err)
+			}
+		} else if err != nil {
+			t.Fatalf("%s -> %s: %v", tc.source, tc.target, err)
+		}
+		mustContain(t, flatten(r.calls), "gh repo create alice/repo --"+tc.target+" --confirm")
+	}
+
+	_, err := NewService(&fakeRunner{}, "").Restore(context.Background(), Request{SourceKind: "bundle", SourcePath: bundle, TargetOwner: "alice", TargetName: "repo", TargetVisibility: "secret"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported visibility: secret") {
+		t.Fatalf("expected unsupported visibility error, got %v", err)
+	}
+}
+
+func TestValidateRepoName(t *testing.T) {
+	for _, name := range []string{"repo", "old.repo_v2-x", strings.Repeat("a", 100)} {
+		if err := ValidateRepoName(name); err != nil {
+			t.Fatalf("%q: unexpected error %v", name, err)
+		}
+	}
+	for _, name := range []string{"", ".", "..", "a/b", "my repo", "caf\u00e9", strings.Repeat("a", 101)} {
+		if err := ValidateRepoName(name); err == nil {
+			t.Fatalf("%q: expected error", name)
+		}
+	}
+}
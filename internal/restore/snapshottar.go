@@ -0,0 +1,136 @@
+package restore
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// snapshotTarSuffixes are the tarball forms of a snapshot directory accepted
+// as a "snapshot-tar" source.
+var snapshotTarSuffixes = []string{".tar.gz", ".tgz", ".tar"}
+
+// trimSnapshotTarSuffix returns name without its tar suffix, and false when
+// name is not a snapshot tarball.
+func trimSnapshotTarSuffix(name string) (string, bool) {
+	for _, suffix := range snapshotTarSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix), true
+		}
+	}
+	return "", false
+}
+
+// extractSnapshotTar unpacks a snapshot tarball into a temp dir and returns
+// that dir plus the git repo inside it. The caller removes the temp dir.
+// Only directories and regular files are extracted; entries that would land
+// outside the temp dir are rejected.
+func extractSnapshotTar(path string) (tmp, repo string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if !strings.HasSuffix(path, ".tar") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return "", "", fmt.Errorf("extract snapshot %s: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	tmp, err = os.MkdirTemp("", "gh-manager-snapshot-*")
+	if err != nil {
+		return "", "", err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(tmp)
+			tmp = ""
+		}
+	}()
+	if err := untar(r, tmp); err != nil {
+		return "", "", fmt.Errorf("extract snapshot %s: %w", path, err)
+	}
+	repo, ok := findGitRepo(tmp)
+	if !ok {
+		return "", "", fmt.Errorf("snapshot %s does not contain a git repository", path)
+	}
+	return tmp, repo, nil
+}
+
+func untar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(strings.TrimPrefix(hdr.Name, "./"))
+		if name == "" || name == "." {
+			continue
+		}
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("unsafe path in archive: %s", hdr.Name)
+		}
+		target := filepath.Join(dest, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, tr)
+			if cerr := out.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// findGitRepo reports the repo in dir: dir itself, or its only top-level
+// directory when the tarball was made from the snapshot's parent.
+func findGitRepo(dir string) (string, bool) {
+	if isGitRepo(dir) {
+		return dir, true
+	}
+	ents, err := os.ReadDir(dir)
+	if err != nil || len(ents) != 1 || !ents[0].IsDir() {
+		return "", false
+	}
+	sub := filepath.Join(dir, ents[0].Name())
+	return sub, isGitRepo(sub)
+}
+
+// isGitRepo accepts a working clone (.git dir) or a bare repository.
+func isGitRepo(dir string) bool {
+	if fileExists(filepath.Join(dir, ".git", "HEAD")) {
+		return true
+	}
+	st, err := os.Stat(filepath.Join(dir, "objects"))
+	return fileExists(filepath.Join(dir, "HEAD")) && err == nil && st.IsDir()
+}
+
+func fileExists(path string) bool {
+	st, err := os.Stat(path)
+	return err == nil && !st.IsDir()
+}
@@ -0,0 +1,128 @@
+package restore
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTar(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if !strings.HasSuffix(path, ".tar") {
+		var gz bytes.Buffer
+		zw := gzip.NewWriter(&gz)
+		if _, err := zw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		data = gz.Bytes()
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractSnapshotTar(t *testing.T) {
+	dir := t.TempDir()
+
+	nested := filepath.Join(dir, "alice__tools.tar.gz")
+	writeTar(t, nested, map[string]string{"alice__tools/.git/HEAD": "ref: refs/heads/main\n", "alice__tools/README.md": "hi"})
+	tmp, repo, err := extractSnapshotTar(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo != filepath.Join(tmp, "alice__tools") {
+		t.Fatalf("expected the top-level dir as repo, got %s", repo)
+	}
+	if b, _ := os.ReadFile(filepath.Join(repo, "README.md")); string(b) != "hi" {
+		t.Fatalf("unexpected extracted content %q", b)
+	}
+	os.RemoveAll(tmp)
+
+	flat := filepath.Join(dir, "flat.tar")
+	writeTar(t, flat, map[string]string{"./.git/HEAD": "ref: refs/heads/main\n"})
+	tmp, repo, err = extractSnapshotTar(flat)
+	if err != nil || repo != tmp {
+		t.Fatalf("expected repo at the tar root: %s %s %v", tmp, repo, err)
+	}
+	os.RemoveAll(tmp)
+
+	notRepo := filepath.Join(dir, "plain.tgz")
+	writeTar(t, notRepo, map[string]string{"docs/README.md": "hi"})
+	if _, _, err := extractSnapshotTar(notRepo); err == nil || !strings.Contains(err.Error(), "does not contain a git repository") {
+		t.Fatalf("expected missing repo error, got %v", err)
+	}
+
+	escape := filepath.Join(dir, "escape.tar")
+	writeTar(t, escape, map[string]string{"../evil": "x"})
+	if _, _, err := extractSnapshotTar(escape); err == nil || !strings.Contains(err.Error(), "unsafe path") {
+		t.Fatalf("expected unsafe path error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "evil")); !os.IsNotExist(err) {
+		t.Fatalf("entry escaped the extraction dir: %v", err)
+	}
+}
+
+func TestRestoreFromSnapshotTar(t *testing.T) {
+	root := t.TempDir()
+	snapDir := filepath.Join(root, "snapshots")
+	if err := os.MkdirAll(snapDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tarPath := filepath.Join(snapDir, "alice__tools.tar.gz")
+	writeTar(t, tarPath, map[string]string{"alice__tools/.git/HEAD": "ref: refs/heads/main\n"})
+
+	entries, err := LoadIndex(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].FullName != "alice/tools" {
+		t.Fatalf("unexpected index: %+v", entries)
+	}
+	src, ok := PreferredSource(entries[0])
+	if !ok || src.Kind != "snapshot-tar" || src.Path != tarPath {
+		t.Fatalf("unexpected source: %+v %v", src, ok)
+	}
+
+	r := &fakeRunner{fail: map[string]error{}}
+	res, err := NewService(r, "").Restore(context.Background(), Request{
+		SourceKind:  src.Kind,
+		SourcePath:  src.Path,
+		TargetOwner: "alice",
+		TargetName:  "tools",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SourceKind != "snapshot-tar" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	calls := flatten(r.calls)
+	mustContain(t, calls, "git clone "+os.TempDir())
+	mustContain(t, calls, string(filepath.Separator)+"alice__tools ")
+
+	if err := validateSource("snapshot-tar", snapDir); err == nil {
+		t.Fatal("expected directory to be rejected as snapshot-tar")
+	}
+}
@@ -11,8 +11,37 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"gh-manager/internal/config"
 )
 
+const indexCacheFile = "index.cache.json"
+
+// IndexCache is the last theme index fetched successfully, kept so remote
+// theme listing still works offline.
+type IndexCache struct {
+	FetchedAt time.Time         `json:"fetched_at"`
+	SourceURL string            `json:"source_url"`
+	Index     ThemeIndex        `json:"index"`
+	Sources   map[string]string `json:"sources,omitempty"`
+}
+
+// MergedIndex combines several theme indexes. Sources maps each theme id to
+// the index URL it was taken from so relative theme URLs resolve correctly.
+type MergedIndex struct {
+	Index   ThemeIndex
+	Sources map[string]string
+}
+
+func (m MergedIndex) Find(id string) (ThemeIndexEntry, string, bool) {
+	for _, entry := range m.Index.Themes {
+		if entry.ID == id {
+			return entry, m.Sources[id], true
+		}
+	}
+	return ThemeIndexEntry{}, "", false
+}
+
 func FetchIndex(ctx context.Context, indexURL string) (ThemeIndex, error) {
 	b, err := fetchURL(ctx, indexURL)
 	if err != nil {
@@ -28,29 +57,103 @@ func FetchIndex(ctx context.Context, indexURL string) (ThemeIndex, error) {
 	return idx, nil
 }
 
+// FetchIndexes fetches every index URL and merges their entries; later URLs
+// override earlier ones on duplicate ids. Unreachable indexes are skipped
+// unless none can be fetched.
+func FetchIndexes(ctx context.Context, indexURLs []string) (MergedIndex, error) {
+	merged := MergedIndex{Index: ThemeIndex{Version: 1}, Sources: map[string]string{}}
+	pos := map[string]int{}
+	var firstErr error
+	fetched := 0
+	for _, indexURL := range indexURLs {
+		idx, err := FetchIndex(ctx, indexURL)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("fetch theme index %s: %w", indexURL, err)
+			}
+			continue
+		}
+		fetched++
+		for _, entry := range idx.Themes {
+			if i, ok := pos[entry.ID]; ok {
+				merged.Index.Themes[i] = entry
+			} else {
+				pos[entry.ID] = len(merged.Index.Themes)
+				merged.Index.Themes = append(merged.Index.Themes, entry)
+			}
+			merged.Sources[entry.ID] = indexURL
+		}
+	}
+	if fetched == 0 {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("no theme index configured")
+		}
+		return MergedIndex{}, firstErr
+	}
+	return merged, nil
+}
+
+func IndexCachePath() (string, error) {
+	themesDir, err := config.ThemesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(themesDir, indexCacheFile), nil
+}
+
+func SaveIndexCache(merged MergedIndex, sourceLabel string, now time.Time) error {
+	path, err := IndexCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(IndexCache{FetchedAt: now.UTC(), SourceURL: sourceLabel, Index: merged.Index, Sources: merged.Sources}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0o644)
+}
+
+func LoadIndexCache() (IndexCache, error) {
+	path, err := IndexCachePath()
+	if err != nil {
+		return IndexCache{}, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return IndexCache{}, err
+	}
+	var c IndexCache
+	if err := json.Unmarshal(b, &c); err != nil {
+		return IndexCache{}, fmt.Errorf("parse theme index cache: %w", err)
+	}
+	return c, nil
+}
+
 func FetchThemeByID(ctx context.Context, indexURL, id string) (ThemeFile, error) {
 	idx, err := FetchIndex(ctx, indexURL)
 	if err != nil {
 		return ThemeFile{}, err
 	}
 	for _, entry := range idx.Themes {
-		if entry.ID != id {
-			continue
-		}
-		resolvedURL := resolveThemeURL(indexURL, entry.URL)
-		b, err := fetchURL(ctx, resolvedURL)
-		if err != nil {
-			return ThemeFile{}, err
-		}
-		t, err := ParseThemeFile(b)
-		if err != nil {
-			return ThemeFile{}, err
+		if entry.ID == id {
+			return FetchThemeEntry(ctx, indexURL, entry)
 		}
-		return t, nil
 	}
 	return ThemeFile{}, fmt.Errorf("theme not found in index: %s", id)
 }
 
+// FetchThemeEntry downloads a theme listed in the index at indexURL.
+func FetchThemeEntry(ctx context.Context, indexURL string, entry ThemeIndexEntry) (ThemeFile, error) {
+	b, err := fetchURL(ctx, resolveThemeURL(indexURL, entry.URL))
+	if err != nil {
+		return ThemeFile{}, err
+	}
+	return ParseThemeFile(b)
+}
+
 func fetchURL(ctx context.Context, url string) ([]byte, error) {
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "file://") {
 		return os.ReadFile(url)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 
 	"gh-manager/internal/config"
@@ -33,6 +34,52 @@ func LoadActivePaletteHex(cfg config.Config) (PaletteHex, string, error) {
 	return themeFile.Colors, themeFile.ID, nil
 }
 
+var themeIDRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func ValidateThemeID(id string) error {
+	if id == "default" {
+		return fmt.Errorf("theme id is reserved: %s", id)
+	}
+	if !themeIDRe.MatchString(id) {
+		return fmt.Errorf("invalid theme id %q: use lowercase letters, digits, '-' or '_'", id)
+	}
+	return nil
+}
+
+func ThemeFilePath(id string) (string, error) {
+	themesDir, err := config.ThemesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(themesDir, id+".json"), nil
+}
+
+func LoadThemeFile(id string) (ThemeFile, error) {
+	if id == "default" {
+		return ThemeFile{ID: "default", Name: "Default", Version: 1, Colors: DefaultPaletteHex()}, nil
+	}
+	themesDir, err := config.ThemesDir()
+	if err != nil {
+		return ThemeFile{}, err
+	}
+	b, err := os.ReadFile(filepath.Join(themesDir, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ThemeFile{}, fmt.Errorf("theme not installed: %s", id)
+		}
+		return ThemeFile{}, err
+	}
+	return ParseThemeFile(b)
+}
+
+func MarshalThemeFile(theme ThemeFile) ([]byte, error) {
+	out, err := json.MarshalIndent(theme, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
 func SaveThemeFile(theme ThemeFile) error {
 	if err := theme.Colors.Validate(); err != nil {
 		return err
@@ -69,7 +116,7 @@ func ListLocalThemeIDs() ([]string, error) {
 			continue
 		}
 		name := ent.Name()
-		if filepath.Ext(name) != ".json" {
+		if filepath.Ext(name) != ".json" || name == indexCacheFile {
 			continue
 		}
 		ids = append(ids, name[:len(name)-5])
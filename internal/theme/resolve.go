@@ -5,6 +5,9 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+
+	"gh-manager/internal/config"
 
 	"github.com/muesli/termenv"
 )
@@ -31,16 +34,43 @@ func DetectTrueColor() bool {
 	return false
 }
 
+// DetectDarkBackground asks the terminal for its background color. ok is
+// false when the terminal does not answer (not a TTY, CI, unsupported).
+func DetectDarkBackground() (dark bool, ok bool) {
+	switch c := termenv.BackgroundColor().(type) {
+	case nil, termenv.NoColor:
+		return false, false
+	default:
+		_, _, l := termenv.ConvertToRGB(c).Hsl()
+		return l < 0.5, true
+	}
+}
+
+// SchemeThemeID returns the theme id to load: with auto_scheme on and a
+// detected background, the configured light or dark theme; otherwise Active.
+func SchemeThemeID(tc config.ThemeConfig, detect func() (dark bool, ok bool)) string {
+	if !tc.AutoScheme || detect == nil {
+		return tc.Active
+	}
+	dark, ok := detect()
+	if !ok {
+		return tc.Active
+	}
+	if dark && tc.Dark != "" {
+		return tc.Dark
+	}
+	if !dark && tc.Light != "" {
+		return tc.Light
+	}
+	return tc.Active
+}
+
 func ResolveForTerminal(p PaletteHex, trueColor bool) PaletteResolved {
 	resolve := func(h Hex) string {
 		if trueColor {
 			return string(h)
 		}
-		rgbVal, err := parseHex(h)
-		if err != nil {
-			return "7"
-		}
-		return strconv.Itoa(nearestXterm256(rgbVal))
+		return resolveXterm256(h)
 	}
 	return PaletteResolved{
 		PaneBorderActive:   resolve(p.PaneBorderActive),
@@ -88,11 +118,35 @@ func parseHex(h Hex) (rgb, error) {
 	return rgb{r: int(r), g: int(g), b: int(b)}, nil
 }
 
+// xterm256Cache memoizes resolveXterm256 by lowercased hex so repeated
+// theme resolutions skip the palette scan.
+var xterm256Cache sync.Map
+
+func resolveXterm256(h Hex) string {
+	key := strings.ToLower(string(h))
+	if v, ok := xterm256Cache.Load(key); ok {
+		return v.(string)
+	}
+	out := "7"
+	if rgbVal, err := parseHex(h); err == nil {
+		out = strconv.Itoa(nearestXterm256(rgbVal))
+	}
+	xterm256Cache.Store(key, out)
+	return out
+}
+
+var xterm256Palette = func() [256]rgb {
+	var p [256]rgb
+	for i := range p {
+		p[i] = xterm256RGB(i)
+	}
+	return p
+}()
+
 func nearestXterm256(c rgb) int {
 	bestIdx := 0
 	bestDist := int(^uint(0) >> 1)
-	for i := 0; i < 256; i++ {
-		p := xterm256RGB(i)
+	for i, p := range xterm256Palette {
 		d := sq(c.r-p.r) + sq(c.g-p.g) + sq(c.b-p.b)
 		if d < bestDist {
 			bestDist = d
@@ -1,8 +1,15 @@
 package theme
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"gh-manager/internal/config"
 )
 
 func TestPaletteHexValidate(t *testing.T) {
@@ -19,6 +26,51 @@ func TestPaletteHexValidate(t *testing.T) {
 	}
 }
 
+func TestDiffPalettes(t *testing.T) {
+	a := DefaultPaletteHex()
+	b := a
+	b.Danger = "#123456"
+	b.DetailsValue = "#abcdef"
+	b.TextPrimary = Hex(strings.ToUpper(string(a.TextPrimary)))
+	diffs := DiffPalettes(a, b)
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 diffs, got %+v", diffs)
+	}
+	if diffs[0].Field != "danger" || diffs[0].A != a.Danger || diffs[0].B != "#123456" {
+		t.Fatalf("unexpected first diff %+v", diffs[0])
+	}
+	if diffs[1].Field != "details_value" {
+		t.Fatalf("diffs should follow palette order, got %+v", diffs)
+	}
+	if len(DiffPalettes(a, a)) != 0 {
+		t.Fatal("identical palettes should have no diffs")
+	}
+}
+
+func TestSchemeThemeID(t *testing.T) {
+	tc := config.ThemeConfig{Active: "main", AutoScheme: true, Light: "day", Dark: "night"}
+	detect := func(dark, ok bool) func() (bool, bool) {
+		return func() (bool, bool) { return dark, ok }
+	}
+	cases := []struct {
+		name   string
+		tc     config.ThemeConfig
+		detect func() (bool, bool)
+		want   string
+	}{
+		{"dark background", tc, detect(true, true), "night"},
+		{"light background", tc, detect(false, true), "day"},
+		{"detection failed", tc, detect(true, false), "main"},
+		{"auto off", config.ThemeConfig{Active: "main", Light: "day", Dark: "night"}, detect(true, true), "main"},
+		{"dark theme unset", config.ThemeConfig{Active: "main", AutoScheme: true, Light: "day"}, detect(true, true), "main"},
+	}
+	for _, c := range cases {
+		if got := SchemeThemeID(c.tc, c.detect); got != c.want {
+			t.Fatalf("%s: got %q want %q", c.name, got, c.want)
+		}
+	}
+}
+
 func TestResolveForTerminal(t *testing.T) {
 	p := DefaultPaletteHex()
 	resolvedTrue := ResolveForTerminal(p, true)
@@ -141,3 +193,123 @@ func TestParseThemeFileInvalidVarFormatFails(t *testing.T) {
 		t.Fatalf("expected invalid var format error")
 	}
 }
+
+func TestLoadThemeFileDefaultRoundTrips(t *testing.T) {
+	tf, err := LoadThemeFile("default")
+	if err != nil {
+		t.Fatalf("load default: %v", err)
+	}
+	b, err := MarshalThemeFile(tf)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	parsed, err := ParseThemeFile(b)
+	if err != nil {
+		t.Fatalf("exported default should parse: %v", err)
+	}
+	if parsed.ID != "default" || parsed.Colors != DefaultPaletteHex() {
+		t.Fatalf("unexpected round trip: %+v", parsed)
+	}
+}
+
+func TestLoadThemeFileMissingInstalledTheme(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if _, err := LoadThemeFile("nope"); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Fatalf("expected not installed error, got %v", err)
+	}
+}
+
+func TestIndexCacheRoundTripIsNotListedAsTheme(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	idx := ThemeIndex{Version: 1, Themes: []ThemeIndexEntry{{ID: "dusk", Name: "Dusk", URL: "dusk.json"}}}
+	merged := MergedIndex{Index: idx, Sources: map[string]string{"dusk": "https://example.com/index.json"}}
+	if err := SaveIndexCache(merged, "https://example.com/index.json", now); err != nil {
+		t.Fatalf("save cache: %v", err)
+	}
+	c, err := LoadIndexCache()
+	if err != nil {
+		t.Fatalf("load cache: %v", err)
+	}
+	if !c.FetchedAt.Equal(now) || c.SourceURL != "https://example.com/index.json" || len(c.Index.Themes) != 1 || c.Sources["dusk"] == "" {
+		t.Fatalf("unexpected cache: %+v", c)
+	}
+	ids, err := ListLocalThemeIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("cache file must not be listed as a theme, got %v", ids)
+	}
+}
+
+func TestFetchIndexesMergesLaterOverrides(t *testing.T) {
+	dir := t.TempDir()
+	public := filepath.Join(dir, "public", "index.json")
+	private := filepath.Join(dir, "private", "index.json")
+	for path, body := range map[string]string{
+		public:  `{"themes":[{"id":"dusk","name":"Dusk","url":"dusk.json"},{"id":"dawn","name":"Dawn","url":"dawn.json"}]}`,
+		private: `{"themes":[{"id":"dusk","name":"Dusk (internal)","url":"dusk.json"},{"id":"corp","name":"Corp","url":"corp.json"}]}`,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	merged, err := FetchIndexes(context.Background(), []string{public, filepath.Join(dir, "missing.json"), private})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if len(merged.Index.Themes) != 3 {
+		t.Fatalf("expected 3 merged themes, got %+v", merged.Index.Themes)
+	}
+	entry, source, ok := merged.Find("dusk")
+	if !ok || entry.Name != "Dusk (internal)" || source != private {
+		t.Fatalf("expected later index to override dusk, got %+v from %s", entry, source)
+	}
+	if _, source, _ := merged.Find("dawn"); source != public {
+		t.Fatalf("expected dawn from public index, got %s", source)
+	}
+	if _, err := FetchIndexes(context.Background(), []string{filepath.Join(dir, "missing.json")}); err == nil {
+		t.Fatal("expected error when no index can be fetched")
+	}
+}
+
+func TestResolveXterm256MatchesUncachedScan(t *testing.T) {
+	for _, h := range []Hex{"#000000", "#FF8800", "#ff8800", "#5f87af", "#123456"} {
+		c, err := parseHex(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := strconv.Itoa(nearestXterm256(c))
+		for i := 0; i < 2; i++ {
+			if got := resolveXterm256(h); got != want {
+				t.Fatalf("%s: got %s want %s", h, got, want)
+			}
+		}
+	}
+	if got := resolveXterm256("bad"); got != "7" {
+		t.Fatalf("invalid hex should fall back to 7, got %s", got)
+	}
+}
+
+func BenchmarkResolveForTerminal256Uncached(b *testing.B) {
+	colors := DefaultPaletteHex().namedColors()
+	for i := 0; i < b.N; i++ {
+		for _, c := range colors {
+			rgbVal, _ := parseHex(c.value)
+			_ = nearestXterm256(rgbVal)
+		}
+	}
+}
+
+func BenchmarkResolveForTerminal256(b *testing.B) {
+	p := DefaultPaletteHex()
+	for i := 0; i < b.N; i++ {
+		_ = ResolveForTerminal(p, false)
+	}
+}
@@ -108,48 +108,82 @@ type ThemeIndexEntry struct {
 var hexRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
 var varRefRe = regexp.MustCompile(`^var\(--([a-zA-Z0-9_-]+)\)$`)
 
-func (p PaletteHex) Validate() error {
-	fields := map[string]Hex{
-		"pane_border_active":   p.PaneBorderActive,
-		"pane_border_inactive": p.PaneBorderInactive,
-		"popup_border":         p.PopupBorder,
-		"popup_outer_border":   p.PopupOuterBorder,
-		"danger":               p.Danger,
-		"danger_text":          p.DangerText,
-		"success":              p.Success,
-		"success_text":         p.SuccessText,
-		"text_primary":         p.TextPrimary,
-		"text_muted":           p.TextMuted,
-		"selection_bg":         p.SelectionBg,
-		"selection_fg":         p.SelectionFg,
-		"logo_line_1":          p.LogoLine1,
-		"logo_line_2":          p.LogoLine2,
-		"logo_line_3":          p.LogoLine3,
-		"logo_line_4":          p.LogoLine4,
-		"logo_line_5":          p.LogoLine5,
-		"logo_line_6":          p.LogoLine6,
-		"header_text":          p.HeaderText,
-		"help_text":            p.HelpText,
-		"status_text":          p.StatusText,
-		"table_header":         p.TableHeader,
-		"col_sel":              p.ColSel,
-		"col_name":             p.ColName,
-		"col_visibility":       p.ColVisibility,
-		"col_fork":             p.ColFork,
-		"col_archived":         p.ColArchived,
-		"col_updated":          p.ColUpdated,
-		"col_description":      p.ColDescription,
-		"details_label":        p.DetailsLabel,
-		"details_value":        p.DetailsValue,
+type namedColor struct {
+	key   string
+	value Hex
+}
+
+// namedColors lists every palette field with its JSON key, in declaration order.
+func (p PaletteHex) namedColors() []namedColor {
+	return []namedColor{
+		{"pane_border_active", p.PaneBorderActive},
+		{"pane_border_inactive", p.PaneBorderInactive},
+		{"popup_border", p.PopupBorder},
+		{"popup_outer_border", p.PopupOuterBorder},
+		{"danger", p.Danger},
+		{"danger_text", p.DangerText},
+		{"success", p.Success},
+		{"success_text", p.SuccessText},
+		{"text_primary", p.TextPrimary},
+		{"text_muted", p.TextMuted},
+		{"selection_bg", p.SelectionBg},
+		{"selection_fg", p.SelectionFg},
+		{"logo_line_1", p.LogoLine1},
+		{"logo_line_2", p.LogoLine2},
+		{"logo_line_3", p.LogoLine3},
+		{"logo_line_4", p.LogoLine4},
+		{"logo_line_5", p.LogoLine5},
+		{"logo_line_6", p.LogoLine6},
+		{"header_text", p.HeaderText},
+		{"help_text", p.HelpText},
+		{"status_text", p.StatusText},
+		{"table_header", p.TableHeader},
+		{"col_sel", p.ColSel},
+		{"col_name", p.ColName},
+		{"col_visibility", p.ColVisibility},
+		{"col_fork", p.ColFork},
+		{"col_archived", p.ColArchived},
+		{"col_updated", p.ColUpdated},
+		{"col_description", p.ColDescription},
+		{"details_label", p.DetailsLabel},
+		{"details_value", p.DetailsValue},
 	}
-	for key, val := range fields {
-		if !hexRe.MatchString(string(val)) {
-			return fmt.Errorf("invalid hex color for %s: %q", key, string(val))
+}
+
+func (p PaletteHex) Validate() error {
+	for _, c := range p.namedColors() {
+		if !hexRe.MatchString(string(c.value)) {
+			return fmt.Errorf("invalid hex color for %s: %q", c.key, string(c.value))
 		}
 	}
 	return nil
 }
 
+// FieldDiff is one palette field whose color differs between two themes.
+type FieldDiff struct {
+	Field string
+	A     Hex
+	B     Hex
+}
+
+// DiffPalettes returns the fields that differ between a and b, in palette
+// order. Hex values are compared case-insensitively.
+func DiffPalettes(a, b PaletteHex) []FieldDiff {
+	ac, bc := a.namedColors(), b.namedColors()
+	var out []FieldDiff
+	for i := range ac {
+		if !strings.EqualFold(string(ac[i].value), string(bc[i].value)) {
+			out = append(out, FieldDiff{Field: ac[i].key, A: ac[i].value, B: bc[i].value})
+		}
+	}
+	return out
+}
+
+// PaletteFieldCount is the number of colors in a palette.
+func PaletteFieldCount() int {
+	return len(PaletteHex{}.namedColors())
+}
+
 func ParseThemeFile(b []byte) (ThemeFile, error) {
 	raw := themeFileRaw{
 		Version: 1,
@@ -10,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"gh-manager/internal/app"
 	"gh-manager/internal/planfile"
 	restorepkg "gh-manager/internal/restore"
 )
@@ -17,8 +18,8 @@ import (
 type AppCallbacks struct {
 	Plan            func(selected []planfile.RepoRecord, outPath string) (string, error)
 	Inspect         func(planPath string) (string, error)
-	Backup          func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord) (string, error)
-	Execute         func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord) (string, error)
+	Backup          func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord, progress func(line string)) (string, error)
+	Execute         func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord, progress func(line string)) (string, error)
 	Restore         func(req RestoreRequest) (string, error)
 	Delete          func(repo planfile.RepoRecord) (string, error)
 	ThemeCurrent    func() (string, error)
@@ -26,9 +27,20 @@ type AppCallbacks struct {
 	ThemeListRemote func() ([]ThemeOption, string, error)
 	ThemeInstall    func(id string) (string, error)
 	ThemeApply      func(id string) (UITheme, string, error)
+	ThemePreview    func(id string) (UITheme, error)
 	ThemeUninstall  func(id string) (UITheme, string, error)
 	UpdateCheck     func() (UpdateInfo, error)
 	UpdateRun       func() (string, error)
+	OpenInBrowser   func(fullName string) error
+	// ExportSelection writes the selected full names to a file that
+	// plan --from-file accepts.
+	ExportSelection func(selected []planfile.RepoRecord, outPath string) (string, error)
+	// FindBackup reports where a local backup of repo exists, if any.
+	FindBackup func(repo planfile.RepoRecord) (string, bool, error)
+	// ArchiveIndex lists the repos found in local archives. It runs in the
+	// background after startup and after each command; nil disables the
+	// Bak column and backup filter.
+	ArchiveIndex func() (map[string]bool, error)
 	// RefreshRepos reloads the repo list after mutating operations (execute/restore/delete).
 	RefreshRepos func() ([]planfile.RepoRecord, error)
 
@@ -36,6 +48,10 @@ type AppCallbacks struct {
 	RestoreDefaultArchiveDir string
 	Version                  string
 	Theme                    UITheme
+	Host                     string
+	InitialSelection         []string
+	// Keybindings maps actions to keys (see config.ResolveKeybindings); nil uses the defaults.
+	Keybindings map[string]string
 }
 
 type RestoreRequest struct {
@@ -43,9 +59,14 @@ type RestoreRequest struct {
 	RepoFullName     string
 	SourceKind       string
 	SourcePath       string
+	SourceSHA256     string
 	TargetOwner      string
 	TargetName       string
 	TargetVisibility string
+	// SourceVisibility is the archived repo's visibility, if recorded.
+	SourceVisibility string
+	// AllowVisibilityChange confirms a target more permissive than the source.
+	AllowVisibilityChange bool
 }
 
 type UpdateInfo struct {
@@ -89,11 +110,16 @@ const (
 	modalCommandForm
 	modalRestoreBrowse
 	modalRestoreSelectRepo
+	modalRestoreOwner
 	modalRestoreYesNo
 	modalRestoreRename
+	modalRestoreVisibility
 	modalDeleteConfirm
+	modalDeleteManyConfirm
+	modalExecutePreview
 	modalSettings
 	modalResult
+	modalHelp
 )
 
 type settingsStage int
@@ -134,6 +160,7 @@ type appModel struct {
 	quitting     bool
 	showDetails  bool
 	theme        UITheme
+	keys         keyMap
 
 	restoreState  restoreState
 	modalActive   bool
@@ -141,9 +168,18 @@ type appModel struct {
 	cursorVisible bool
 	resultText    string
 	resultScroll  int
+	// progressLines collects the live output of a running backup/execute.
+	progressLines []string
 	deleteRepo    planfile.RepoRecord
 	deleteInput   string
+	deleteRepos   []planfile.RepoRecord
+	deleteBackup  backupLookup
 	settings      settingsState
+
+	executePreview    []planfile.RepoRecord
+	executePreviewErr error
+	executeDryRun     bool
+	executeCmd        tea.Cmd
 }
 
 type settingsState struct {
@@ -162,6 +198,8 @@ type settingsState struct {
 	updateInfo       UpdateInfo
 	updateBusy       bool
 	updateStatus     string
+	previewActive    bool
+	previewBase      UITheme
 }
 
 type commandResultMsg struct {
@@ -170,6 +208,35 @@ type commandResultMsg struct {
 	refreshRepos bool
 }
 
+// progressMsg carries one output line of a running command; ch is read again
+// until the command closes it.
+type progressMsg struct {
+	line string
+	ch   <-chan string
+}
+
+func waitForProgress(ch <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return progressMsg{line: line, ch: ch}
+	}
+}
+
+// streamCommand runs fn in the background, delivering the lines it reports
+// as progressMsgs before its final commandResultMsg.
+func streamCommand(fn func(progress func(string)) commandResultMsg) tea.Cmd {
+	ch := make(chan string)
+	run := func() tea.Msg {
+		res := fn(func(line string) { ch <- line })
+		close(ch)
+		return res
+	}
+	return tea.Batch(run, waitForProgress(ch))
+}
+
 type cursorBlinkMsg struct{}
 
 type reposRefreshedMsg struct {
@@ -221,18 +288,42 @@ type updateRunMsg struct {
 	err    error
 }
 
+type backupLookup struct {
+	checked bool
+	found   bool
+	path    string
+	err     error
+}
+
+type backupCheckedMsg struct {
+	fullName string
+	lookup   backupLookup
+}
+
+type archiveIndexMsg struct {
+	fullNames map[string]bool
+	err       error
+}
+
+type openInBrowserMsg struct {
+	fullName string
+	err      error
+}
+
 var ansiRE = regexp.MustCompile(`\x1b\[[0-9;]*m`)
 
 func RunApp(repos []planfile.RepoRecord, callbacks AppCallbacks) error {
 	m := newAppModel(repos, callbacks)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err := p.Run()
 	return err
 }
 
 func newAppModel(repos []planfile.RepoRecord, callbacks AppCallbacks) appModel {
+	table := newRepoTable(repos)
+	table.preselect(callbacks.InitialSelection)
 	return appModel{
-		table:      newRepoTable(repos),
+		table:      table,
 		callbacks:  callbacks,
 		activeMode: modeBrowse,
 		activePane: paneTable,
@@ -240,14 +331,18 @@ func newAppModel(repos []planfile.RepoRecord, callbacks AppCallbacks) appModel {
 			{name: "Plan", icon: "󰦨", desc: "Save signed plan from current selection", fields: []formField{{key: "out", label: "Output path", kind: fieldText, placeholder: "./deletion-plan-YYYYMMDD-HHMMSS.json"}}},
 			{name: "Inspect", icon: "󰈞", desc: "Inspect a plan file", fields: []formField{{key: "plan", label: "Plan path", kind: fieldText, required: true, placeholder: "./plan.json"}}},
 			{name: "Backup", icon: "󰁯", desc: "Run backup workflow", fields: []formField{{key: "plan", label: "Plan path", kind: fieldText, placeholder: "(auto from current selection)"}, {key: "backup_location", label: "Backup location", kind: fieldText, placeholder: "(auto timestamp folder)"}, {key: "dry_run", label: "Dry run", kind: fieldBool, boolValue: true}, {key: "confirm", label: "Type ACCEPT or CONFIRM", kind: fieldText, required: true, placeholder: "CONFIRM"}}},
-			{name: "Execute", icon: "󰐊", desc: "Run execute workflow", fields: []formField{{key: "plan", label: "Plan path", kind: fieldText, placeholder: "(auto from current selection)"}, {key: "backup_location", label: "Backup location", kind: fieldText, placeholder: "(auto timestamp folder)"}, {key: "dry_run", label: "Dry run", kind: fieldBool, boolValue: true}, {key: "confirm", label: "Type ACCEPT or CONFIRM", kind: fieldText, required: true, placeholder: "CONFIRM"}}},
+			{name: "Execute", icon: "󰐊", desc: "Run execute workflow", fields: []formField{{key: "plan", label: "Plan path", kind: fieldText, placeholder: "(auto from current selection)"}, {key: "backup_location", label: "Backup location", kind: fieldText, placeholder: "(auto timestamp folder)"}, {key: "dry_run", label: "Dry run", kind: fieldBool, boolValue: true}, {key: "confirm", label: "Type ACCEPT or CONFIRM (repo count if high-risk)", kind: fieldText, required: true, placeholder: "CONFIRM"}}},
 			{name: "Restore", icon: "󰑐", desc: "Restore from local archive to GitHub"},
 			{name: "Delete", icon: "󰆴", desc: "Delete highlighted repository (no backup)"},
+			{name: "Delete Selected", icon: "󰆴", desc: "Delete all selected repositories (no backup)"},
+			{name: "Select Matching", icon: "󰒆", desc: "Select all repos matching a glob or re:regex", fields: []formField{{key: "pattern", label: "Pattern", kind: fieldText, required: true, placeholder: "alice/tmp-*  or  re:^alice/old"}}},
+			{name: "Export Selection", icon: "󰈔", desc: "Save selected repo names for plan --from-file", fields: []formField{{key: "out", label: "Output path", kind: fieldText, placeholder: "./selection-YYYYMMDD-HHMMSS.txt"}}},
 			{name: "Settings", icon: "󰒓", desc: "Manage configuration, theme, and updates"},
 		},
 		status:     "Ready",
 		appVersion: callbacks.Version,
 		theme:      callbacks.Theme.withDefaults(),
+		keys:       newKeyMap(callbacks.Keybindings),
 		settings: settingsState{
 			updateInfo: UpdateInfo{CurrentVersion: formatVersionLabel(callbacks.Version)},
 		},
@@ -261,10 +356,25 @@ func blinkCursorCmd() tea.Cmd {
 }
 
 func (m appModel) Init() tea.Cmd {
-	if m.callbacks.UpdateCheck == nil {
+	var cmds []tea.Cmd
+	if m.callbacks.UpdateCheck != nil {
+		cmds = append(cmds, m.updateCheckCmd())
+	}
+	if m.callbacks.ArchiveIndex != nil {
+		cmds = append(cmds, m.archiveIndexCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m appModel) archiveIndexCmd() tea.Cmd {
+	if m.callbacks.ArchiveIndex == nil {
 		return nil
 	}
-	return m.updateCheckCmd()
+	scan := m.callbacks.ArchiveIndex
+	return func() tea.Msg {
+		names, err := scan()
+		return archiveIndexMsg{fullNames: names, err: err}
+	}
 }
 
 func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -281,8 +391,17 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.cursorVisible = !m.cursorVisible
 		return m, blinkCursorCmd()
+	case progressMsg:
+		if !m.busy {
+			return m, waitForProgress(msg.ch)
+		}
+		m.progressLines = append(m.progressLines, msg.line)
+		_ = m.openResultModal(m.status + "\n" + strings.Join(m.progressLines, "\n"))
+		m.resultScroll = max(len(m.progressLines)+1-(m.modalMaxLines()-2), 0)
+		return m, waitForProgress(msg.ch)
 	case commandResultMsg:
 		m.busy = false
+		m.progressLines = nil
 		if msg.err != nil {
 			if m.restoreState.active {
 				var conflict restorepkg.TargetExistsError
@@ -291,6 +410,12 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.status = "Target exists; choose another name"
 					return m, m.openRestoreRenameModal(conflict.SuggestedName())
 				}
+				var downgrade restorepkg.VisibilityDowngradeError
+				if errors.As(msg.err, &downgrade) {
+					m.restoreState.stage = restoreStageConfirmVisibility
+					m.status = "Confirm visibility change (yes/no)"
+					return m, m.openRestoreVisibilityModal()
+				}
 			}
 			m.status = "Error: " + msg.err.Error()
 			return m, m.openResultModal("Error\n" + msg.err.Error())
@@ -304,9 +429,16 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.refreshRepos && m.callbacks.RefreshRepos != nil {
 			m.status = "Command complete (refreshing repositories...)"
 			_ = m.openResultModal(msg.output)
-			return m, m.refreshReposCmd()
+			return m, tea.Batch(m.refreshReposCmd(), m.archiveIndexCmd())
 		}
-		return m, m.openResultModal(msg.output)
+		return m, tea.Batch(m.openResultModal(msg.output), m.archiveIndexCmd())
+	case archiveIndexMsg:
+		if msg.err != nil {
+			m.status = "Warning: archive scan failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.table.setBackedUp(msg.fullNames)
+		return m, nil
 	case reposRefreshedMsg:
 		if msg.err != nil {
 			m.status = "Warning: refresh failed: " + msg.err.Error()
@@ -330,6 +462,9 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.settings.localThemes = msg.themes
 		m.settings.activeTheme = msg.active
 		m.settings.currentSource = "~/.config/gh-manager/themes"
+		if m.settings.mode == settingsModeApply {
+			m.previewLocalTheme(&m.settings)
+		}
 		return m, nil
 	case settingsRemoteMsg:
 		if msg.err != nil {
@@ -351,6 +486,7 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.settings.status = "Error: " + msg.err.Error()
 			return m, nil
 		}
+		m.settings.previewActive = false
 		m.theme = msg.theme.withDefaults()
 		m.settings.status = msg.output
 		return m, m.settingsListLocalCmd()
@@ -389,9 +525,26 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		latest := formatVersionLabel(m.settings.updateInfo.LatestVersion)
 		m.settings.updateStatus = "Update installed. Restart gh-manager to use " + latest + "."
 		return m, m.openResultModal(msg.output)
+	case backupCheckedMsg:
+		if m.modalKind == modalDeleteConfirm && m.deleteRepo.FullName == msg.fullName {
+			m.deleteBackup = msg.lookup
+		}
+		return m, nil
+	case openInBrowserMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.status = "Opened " + msg.fullName + " in browser"
+		return m, nil
+	case tea.MouseMsg:
+		if m.busy || m.modalActive {
+			return m, nil
+		}
+		return m.updateMouse(msg)
 	case tea.KeyMsg:
 		s := msg.String()
-		if s == "ctrl+c" || s == "q" {
+		if s == "ctrl+c" || (s == "q" && !m.table.filtering) {
 			m.quitting = true
 			return m, tea.Quit
 		}
@@ -401,6 +554,10 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.modalActive {
 			return m.updateModalInput(s)
 		}
+		if m.table.filtering {
+			m.table.updateFilterInput(s)
+			return m, nil
+		}
 
 		switch s {
 		case "1":
@@ -421,6 +578,8 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.restoreState = restoreState{}
 			m.closeModal()
 			return m, nil
+		case "?":
+			return m, m.openHelpModal()
 		case "tab":
 			if m.activePane == paneTable {
 				m.activePane = paneCommands
@@ -440,51 +599,67 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m appModel) updateBrowse(key string) (tea.Model, tea.Cmd) {
 	switch key {
-	case "up", "k":
-		m.table.moveCursor(-1, 0)
-	case "down", "j":
-		m.table.moveCursor(1, 0)
-	case "pgup":
-		m.table.pageMove(-1, 0)
-	case "pgdown":
-		m.table.pageMove(1, 0)
-	case " ":
-		m.table.toggleCurrent()
-	case "a":
-		m.table.selectAllFiltered()
-	case "x":
-		m.table.clearAllFiltered()
+	case "/":
+		m.table.filtering = true
+		return m, nil
 	case "backspace":
 		m.table.backspaceFilter()
-	case "n":
-		m.table.setSortField(sortFieldName)
-	case "u":
-		m.table.setSortField(sortFieldUpdated)
-	case "v":
-		m.table.setSortField(sortFieldVisibility)
-	default:
-		m.table.appendFilterChar(key)
+		return m, nil
+	}
+	action := m.keys.action(key)
+	if action == "open_browser" {
+		return m, m.openInBrowserCmd()
 	}
+	applyTableAction(&m.table, action, 0)
 	return m, nil
 }
 
+func (m *appModel) openInBrowserCmd() tea.Cmd {
+	repo, ok := m.table.currentRepo()
+	if !ok {
+		m.status = "No repository selected"
+		return nil
+	}
+	if m.callbacks.OpenInBrowser == nil {
+		m.status = "Error: open in browser callback unavailable"
+		return nil
+	}
+	open := m.callbacks.OpenInBrowser
+	m.status = "Opening " + repo.FullName + "..."
+	return func() tea.Msg {
+		return openInBrowserMsg{fullName: repo.FullName, err: open(repo.FullName)}
+	}
+}
+
+func repoWebURL(host, fullName string) string {
+	if strings.TrimSpace(host) == "" {
+		host = app.DefaultHost
+	}
+	return "https://" + host + "/" + fullName
+}
+
 func (m appModel) updateCommands(key string) (tea.Model, tea.Cmd) {
 	if m.restoreState.active {
 		return m.updateRestoreFlow(key)
 	}
 
-	switch key {
-	case "up", "k":
+	if key == "enter" {
+		cmd := m.openFormForCurrentCommand()
+		return m, cmd
+	}
+	switch m.keys.action(key) {
+	case "move_up":
 		if m.cmdCursor > 0 {
 			m.cmdCursor--
 		}
-	case "down", "j":
+	case "move_down":
 		if m.cmdCursor < len(m.commands)-1 {
 			m.cmdCursor++
 		}
-	case "enter":
-		cmd := m.openFormForCurrentCommand()
-		return m, cmd
+	case "move_top":
+		m.cmdCursor = 0
+	case "move_bottom":
+		m.cmdCursor = len(m.commands) - 1
 	}
 	return m, nil
 }
@@ -505,6 +680,14 @@ func (m *appModel) openFormForCurrentCommand() tea.Cmd {
 		}
 		return m.openDeleteConfirmModal(repo)
 	}
+	if cmd.name == "Delete Selected" {
+		selected := m.table.selectedReposSorted()
+		if len(selected) == 0 {
+			m.status = "No repositories selected"
+			return nil
+		}
+		return m.openDeleteManyConfirmModal(selected)
+	}
 	if cmd.name == "Settings" {
 		return m.openSettingsModal()
 	}
@@ -539,6 +722,19 @@ func (m appModel) submitCommandForm() (tea.Cmd, error) {
 			out, err := m.callbacks.Plan(selected, outPath)
 			return commandResultMsg{output: out, err: err}
 		}, nil
+	case "Export Selection":
+		selected := m.table.selectedReposSorted()
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no repositories selected")
+		}
+		if m.callbacks.ExportSelection == nil {
+			return nil, fmt.Errorf("export selection callback unavailable")
+		}
+		outPath := strings.TrimSpace(vals["out"].value)
+		return func() tea.Msg {
+			out, err := m.callbacks.ExportSelection(selected, outPath)
+			return commandResultMsg{output: out, err: err}
+		}, nil
 	case "Inspect":
 		if m.callbacks.Inspect == nil {
 			return nil, fmt.Errorf("inspect callback unavailable")
@@ -557,10 +753,11 @@ func (m appModel) submitCommandForm() (tea.Cmd, error) {
 		dryRun := vals["dry_run"].boolValue
 		confirm := strings.TrimSpace(vals["confirm"].value)
 		selected := m.table.selectedReposSorted()
-		return func() tea.Msg {
-			out, err := m.callbacks.Backup(planPath, backupLocation, dryRun, confirm, selected)
+		backupFn := m.callbacks.Backup
+		return streamCommand(func(progress func(string)) commandResultMsg {
+			out, err := backupFn(planPath, backupLocation, dryRun, confirm, selected, progress)
 			return commandResultMsg{output: out, err: err}
-		}, nil
+		}), nil
 	case "Execute":
 		if m.callbacks.Execute == nil {
 			return nil, fmt.Errorf("execute callback unavailable")
@@ -570,10 +767,11 @@ func (m appModel) submitCommandForm() (tea.Cmd, error) {
 		dryRun := vals["dry_run"].boolValue
 		confirm := strings.TrimSpace(vals["confirm"].value)
 		selected := m.table.selectedReposSorted()
-		return func() tea.Msg {
-			out, err := m.callbacks.Execute(planPath, backupLocation, dryRun, confirm, selected)
+		executeFn := m.callbacks.Execute
+		return streamCommand(func(progress func(string)) commandResultMsg {
+			out, err := executeFn(planPath, backupLocation, dryRun, confirm, selected, progress)
 			return commandResultMsg{output: out, err: err, refreshRepos: err == nil}
-		}, nil
+		}), nil
 	case "Restore":
 		return nil, fmt.Errorf("restore is handled by restore window")
 	default:
@@ -581,6 +779,24 @@ func (m appModel) submitCommandForm() (tea.Cmd, error) {
 	}
 }
 
+func (m appModel) submitSelectMatching() (tea.Model, tea.Cmd) {
+	pattern := ""
+	for _, f := range m.formFields {
+		if f.key == "pattern" {
+			pattern = strings.TrimSpace(f.value)
+		}
+	}
+	added, err := m.table.selectMatching(pattern)
+	if err != nil {
+		m.status = "Error: " + err.Error()
+		return m, nil
+	}
+	m.formOpen = false
+	m.closeModal()
+	m.status = fmt.Sprintf("Selected %d new repos matching %q", added, pattern)
+	return m, nil
+}
+
 func (m *appModel) openCommandFormModal() tea.Cmd {
 	m.modalActive = true
 	m.modalKind = modalCommandForm
@@ -595,6 +811,14 @@ func (m *appModel) openRestoreYesNoModal() tea.Cmd {
 	return blinkCursorCmd()
 }
 
+func (m *appModel) openRestoreVisibilityModal() tea.Cmd {
+	m.modalActive = true
+	m.modalKind = modalRestoreVisibility
+	m.cursorVisible = true
+	m.restoreState.promptInput = ""
+	return blinkCursorCmd()
+}
+
 func (m *appModel) openRestoreBrowseModal() tea.Cmd {
 	m.modalActive = true
 	m.modalKind = modalRestoreBrowse
@@ -609,6 +833,14 @@ func (m *appModel) openRestoreSelectRepoModal() tea.Cmd {
 	return nil
 }
 
+func (m *appModel) openRestoreOwnerModal(seed string) tea.Cmd {
+	m.modalActive = true
+	m.modalKind = modalRestoreOwner
+	m.cursorVisible = true
+	m.restoreState.promptInput = seed
+	return blinkCursorCmd()
+}
+
 func (m *appModel) openRestoreRenameModal(seed string) tea.Cmd {
 	m.modalActive = true
 	m.modalKind = modalRestoreRename
@@ -623,10 +855,87 @@ func (m *appModel) openDeleteConfirmModal(repo planfile.RepoRecord) tea.Cmd {
 	m.cursorVisible = true
 	m.deleteRepo = repo
 	m.deleteInput = ""
+	m.deleteBackup = backupLookup{}
 	m.status = "Danger: type repo name to confirm delete"
+	return tea.Batch(blinkCursorCmd(), m.findBackupCmd(repo))
+}
+
+func (m appModel) findBackupCmd(repo planfile.RepoRecord) tea.Cmd {
+	if m.callbacks.FindBackup == nil {
+		return nil
+	}
+	find := m.callbacks.FindBackup
+	return func() tea.Msg {
+		path, found, err := find(repo)
+		return backupCheckedMsg{fullName: repo.FullName, lookup: backupLookup{checked: true, found: found, path: path, err: err}}
+	}
+}
+
+func (m *appModel) openDeleteManyConfirmModal(repos []planfile.RepoRecord) tea.Cmd {
+	m.modalActive = true
+	m.modalKind = modalDeleteManyConfirm
+	m.cursorVisible = true
+	m.deleteRepos = repos
+	m.deleteInput = ""
+	m.status = "Danger: type confirmation phrase to delete selected repos"
 	return blinkCursorCmd()
 }
 
+// openExecutePreviewModal lists the repos an Execute run will touch and holds
+// cmd until the user presses Enter. Repos come from the plan file when one is
+// given, otherwise from the table selection.
+func (m *appModel) openExecutePreviewModal(cmd tea.Cmd) tea.Cmd {
+	var planPath string
+	for _, f := range m.formFields {
+		switch f.key {
+		case "plan":
+			planPath = strings.TrimSpace(f.value)
+		case "dry_run":
+			m.executeDryRun = f.boolValue
+		}
+	}
+	m.executePreview = nil
+	m.executePreviewErr = nil
+	if planPath == "" {
+		m.executePreview = m.table.selectedReposSorted()
+	} else if p, err := planfile.Read(planPath); err != nil {
+		m.executePreviewErr = err
+	} else {
+		m.executePreview = p.Repos
+	}
+	m.executeCmd = cmd
+	m.modalActive = true
+	m.modalKind = modalExecutePreview
+	m.cursorVisible = false
+	m.status = "Review repositories; Enter runs execute, Esc returns to the form"
+	return nil
+}
+
+func deleteManyPhrase(n int) string {
+	return fmt.Sprintf("DELETE %d", n)
+}
+
+// deleteManyCmd deletes repos one at a time, continuing past failures, and
+// reports a per-repo outcome line for each.
+func (m appModel) deleteManyCmd(repos []planfile.RepoRecord) tea.Cmd {
+	del := m.callbacks.Delete
+	return func() tea.Msg {
+		var b strings.Builder
+		deleted, failed := 0, 0
+		for _, repo := range repos {
+			if _, err := del(repo); err != nil {
+				failed++
+				fmt.Fprintf(&b, "FAILED  %s: %v\n", repo.FullName, err)
+				continue
+			}
+			deleted++
+			fmt.Fprintf(&b, "deleted %s\n", repo.FullName)
+		}
+		summary := fmt.Sprintf("Deleted %d of %d repositories (%d failed)\n\n", deleted, len(repos), failed)
+		return commandResultMsg{output: summary + b.String(), refreshRepos: deleted > 0}
+	}
+}
+
 func (m *appModel) openSettingsModal() tea.Cmd {
 	m.modalActive = true
 	m.modalKind = modalSettings
@@ -652,6 +961,12 @@ func (m *appModel) closeModal() {
 	m.resultScroll = 0
 	m.deleteRepo = planfile.RepoRecord{}
 	m.deleteInput = ""
+	m.deleteRepos = nil
+	m.deleteBackup = backupLookup{}
+	m.executePreview = nil
+	m.executePreviewErr = nil
+	m.executeDryRun = false
+	m.executeCmd = nil
 	m.settings = settingsState{
 		updateInfo:   savedUpdate,
 		updateStatus: savedUpdateStatus,
@@ -670,6 +985,15 @@ func (m *appModel) openResultModal(text string) tea.Cmd {
 	return nil
 }
 
+func (m *appModel) openHelpModal() tea.Cmd {
+	m.modalActive = true
+	m.modalKind = modalHelp
+	m.cursorVisible = false
+	m.resultText = helpText(m.keys)
+	m.resultScroll = 0
+	return nil
+}
+
 func (m appModel) refreshReposCmd() tea.Cmd {
 	if m.callbacks.RefreshRepos == nil {
 		return nil
@@ -680,6 +1004,29 @@ func (m appModel) refreshReposCmd() tea.Cmd {
 	}
 }
 
+// previewLocalTheme renders the highlighted local theme without saving it.
+// The theme active before the first preview is kept so Esc can revert.
+func (m *appModel) previewLocalTheme(s *settingsState) {
+	if s.mode != settingsModeApply || m.callbacks.ThemePreview == nil {
+		return
+	}
+	if s.cursor < 0 || s.cursor >= len(s.localThemes) {
+		return
+	}
+	id := s.localThemes[s.cursor]
+	theme, err := m.callbacks.ThemePreview(id)
+	if err != nil {
+		s.status = "Error: " + err.Error()
+		return
+	}
+	if !s.previewActive {
+		s.previewBase = m.theme
+		s.previewActive = true
+	}
+	m.theme = theme.withDefaults()
+	s.status = "Previewing theme: " + id + " (enter to apply, esc to revert)"
+}
+
 func (m appModel) settingsCurrentCmd() tea.Cmd {
 	if m.callbacks.ThemeCurrent == nil {
 		return func() tea.Msg { return settingsCurrentMsg{err: fmt.Errorf("theme current callback unavailable")} }
@@ -760,11 +1107,17 @@ func (m appModel) updateModalInput(key string) (tea.Model, tea.Cmd) {
 				}
 			}
 		case "enter":
+			if m.formCommand == "Select Matching" {
+				return m.submitSelectMatching()
+			}
 			cmd, err := m.submitCommandForm()
 			if err != nil {
 				m.status = "Error: " + err.Error()
 				return m, nil
 			}
+			if m.formCommand == "Execute" {
+				return m, m.openExecutePreviewModal(cmd)
+			}
 			m.closeModal()
 			m.busy = true
 			m.status = "Running " + strings.ToLower(m.formCommand) + "..."
@@ -777,12 +1130,39 @@ func (m appModel) updateModalInput(key string) (tea.Model, tea.Cmd) {
 		return m, nil
 	case modalRestoreBrowse, modalRestoreSelectRepo:
 		return m.updateRestoreFlow(key)
-	case modalRestoreYesNo:
+	case modalRestoreOwner:
 		switch key {
 		case "esc":
-			m.closeModal()
 			m.restoreState.stage = restoreStageSelectRepo
 			m.status = "Restore: select repository"
+			return m, m.openRestoreSelectRepoModal()
+		case "backspace":
+			if len(m.restoreState.promptInput) > 0 {
+				m.restoreState.promptInput = m.restoreState.promptInput[:len(m.restoreState.promptInput)-1]
+			}
+		case "enter":
+			owner := strings.TrimSpace(m.restoreState.promptInput)
+			if err := validateRestoreOwner(owner); err != nil {
+				m.status = "Error: " + err.Error()
+				return m, nil
+			}
+			m.restoreState.targetOwner = owner
+			m.restoreState.promptInput = ""
+			m.restoreState.stage = restoreStageAskUseOriginal
+			m.status = "Use original name? (yes/no)"
+			return m, m.openRestoreYesNoModal()
+		default:
+			if isPrintableKey(key) {
+				m.restoreState.promptInput += key
+			}
+		}
+		return m, nil
+	case modalRestoreYesNo:
+		switch key {
+		case "esc":
+			m.restoreState.stage = restoreStageInputOwner
+			m.status = "Restore: choose target owner"
+			return m, m.openRestoreOwnerModal(m.restoreState.targetOwner)
 		case "backspace":
 			if len(m.restoreState.promptInput) > 0 {
 				m.restoreState.promptInput = m.restoreState.promptInput[:len(m.restoreState.promptInput)-1]
@@ -811,6 +1191,44 @@ func (m appModel) updateModalInput(key string) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, nil
+	case modalRestoreVisibility:
+		switch key {
+		case "esc":
+			m.restoreState = restoreState{}
+			m.closeModal()
+			m.status = "Restore canceled"
+		case "backspace":
+			if len(m.restoreState.promptInput) > 0 {
+				m.restoreState.promptInput = m.restoreState.promptInput[:len(m.restoreState.promptInput)-1]
+			}
+		case "enter":
+			yes, valid := parseYesNo(m.restoreState.promptInput)
+			if !valid {
+				m.status = "Please answer yes or no"
+				return m, nil
+			}
+			if !yes {
+				m.restoreState = restoreState{}
+				m.closeModal()
+				m.status = "Restore canceled"
+				return m, nil
+			}
+			m.restoreState.allowVisibilityChange = true
+			cmd, err := m.submitRestore(m.restoreState.targetName)
+			if err != nil {
+				m.status = "Error: " + err.Error()
+				return m, nil
+			}
+			m.closeModal()
+			m.busy = true
+			m.status = "Running restore..."
+			return m, cmd
+		default:
+			if isPrintableKey(key) {
+				m.restoreState.promptInput += key
+			}
+		}
+		return m, nil
 	case modalRestoreRename:
 		switch key {
 		case "esc":
@@ -876,6 +1294,52 @@ func (m appModel) updateModalInput(key string) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+	case modalDeleteManyConfirm:
+		switch key {
+		case "esc":
+			m.closeModal()
+			m.status = "Delete canceled"
+			return m, nil
+		case "backspace":
+			if len(m.deleteInput) > 0 {
+				m.deleteInput = m.deleteInput[:len(m.deleteInput)-1]
+			}
+			return m, nil
+		case "enter":
+			if m.callbacks.Delete == nil {
+				m.status = "Error: delete callback unavailable"
+				return m, nil
+			}
+			if strings.TrimSpace(m.deleteInput) != deleteManyPhrase(len(m.deleteRepos)) {
+				m.status = "Error: confirmation must match " + deleteManyPhrase(len(m.deleteRepos))
+				return m, nil
+			}
+			repos := m.deleteRepos
+			m.closeModal()
+			m.busy = true
+			m.status = fmt.Sprintf("Deleting %d repositories...", len(repos))
+			return m, m.deleteManyCmd(repos)
+		default:
+			if isPrintableKey(key) {
+				m.deleteInput += key
+			}
+			return m, nil
+		}
+	case modalExecutePreview:
+		switch key {
+		case "esc":
+			m.executeCmd = nil
+			m.status = "Execute canceled; back to form"
+			return m, m.openCommandFormModal()
+		case "enter":
+			cmd := m.executeCmd
+			m.formOpen = false
+			m.closeModal()
+			m.busy = true
+			m.status = "Running execute..."
+			return m, cmd
+		}
+		return m, nil
 	case modalSettings:
 		return m.updateSettingsModal(key)
 	case modalResult:
@@ -891,6 +1355,19 @@ func (m appModel) updateModalInput(key string) (tea.Model, tea.Cmd) {
 			m.resultScroll++
 		}
 		return m, nil
+	case modalHelp:
+		switch key {
+		case "esc", "?":
+			m.closeModal()
+			return m, nil
+		case "up", "k":
+			if m.resultScroll > 0 {
+				m.resultScroll--
+			}
+		case "down", "j":
+			m.resultScroll++
+		}
+		return m, nil
 	default:
 		return m, nil
 	}
@@ -985,14 +1462,20 @@ func (m appModel) updateSettingsModal(key string) (tea.Model, tea.Cmd) {
 	case settingsStageThemeLocalList:
 		switch key {
 		case "esc":
+			if s.previewActive {
+				m.theme = s.previewBase
+				s.previewActive = false
+			}
 			s.stage = settingsStageThemeHome
 		case "up", "k":
 			if s.cursor > 0 {
 				s.cursor--
+				m.previewLocalTheme(&s)
 			}
 		case "down", "j":
 			if s.cursor < len(s.localThemes)-1 {
 				s.cursor++
+				m.previewLocalTheme(&s)
 			}
 		case "enter":
 			if len(s.localThemes) == 0 {
@@ -1069,6 +1552,10 @@ func (m appModel) updateSettingsModal(key string) (tea.Model, tea.Cmd) {
 		case "enter":
 			switch actions[s.updateHomeCursor] {
 			case "Check now":
+				if m.callbacks.UpdateCheck == nil {
+					s.updateStatus = "Update checks are disabled (update.enabled=false or GH_MANAGER_NO_UPDATE_CHECK)"
+					break
+				}
 				m.settings = s
 				return m, m.updateCheckCmd()
 			case "Update now":
@@ -1111,28 +1598,66 @@ func (m appModel) View() string {
 	if m.height <= 0 {
 		m.height = 36
 	}
-	status := fmt.Sprintf("Mode: %s | Focus: %s | Sort: %s | Filter: %s | Selected: %d | Visible: %d/%d", modeLabel(m.activeMode), paneLabel(m.activePane), sortLabel(m.table.sortBy, m.table.sortDir), m.table.filter, len(m.table.selected), len(m.table.filtered), len(m.table.repos))
+	status := fmt.Sprintf("Mode: %s | Focus: %s | Sort: %s | Filter: %s | Selected: %d | Visible: %d/%d | Est: %s", modeLabel(m.activeMode), paneLabel(m.activePane), sortLabel(m.table.sortBy, m.table.sortDir), m.table.filterLabel(), len(m.table.selected), len(m.table.filtered), len(m.table.repos), formatDiskUsage(m.table.selectedDiskUsage()))
 
 	help := globalHelp()
 	if m.activeMode == modeCommands {
-		help = help + " | " + commandHelp()
+		help = help + " | " + commandHelp(m.keys)
 	} else {
-		help = help + " | " + browseHelp()
+		help = help + " | " + browseHelp(m.keys)
 	}
 
 	topBanner := m.renderTopBanner(m.width)
 	help = lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.HelpText)).Render(clampLine(help, m.width))
 	status = lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.StatusText)).Render(clampLine(status, m.width))
 
-	chromeHeight := len(topBanner) + 3
-	bodyHeight := m.height - chromeHeight
+	lay := m.layout()
+	m.table.setHeight(lay.bodyHeight)
+
+	left := m.table.renderTableWithTheme(lay.leftWidth, m.activePane == paneTable, 0, m.theme)
+	body := left
+	if lay.rightWidth > 0 {
+		right := m.renderRightColumn(lay.rightWidth, lay.bodyHeight)
+		body = lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	}
+
+	out := make([]string, 0, len(topBanner)+3)
+	out = append(out, topBanner...)
+	out = append(out, "")
+	out = append(out, help, status, body)
+	view := strings.Join(out, "\n")
+	if m.modalActive {
+		backdrop := applyBackdrop(view, m.width, m.height)
+		view = overlayCentered(backdrop, m.renderModalOverlay(), m.width, m.height)
+	}
+	return view + "\n"
+}
+
+type appLayout struct {
+	tableTop   int
+	bodyHeight int
+	leftWidth  int
+	rightWidth int
+}
+
+// layout computes where View places the repo table: it starts below the
+// banner, a blank line, and the help and status lines.
+func (m appModel) layout() appLayout {
+	width, height := m.width, m.height
+	if width <= 0 {
+		width = 140
+	}
+	if height <= 0 {
+		height = 36
+	}
+	chromeHeight := len(m.renderTopBanner(width)) + 3
+	bodyHeight := height - chromeHeight
 	if bodyHeight < 4 {
 		bodyHeight = 4
 	}
-	m.table.setHeight(bodyHeight)
 
 	gap := 1
-	availableWidth := m.width
+	availableWidth := width
 	if availableWidth < 70 {
 		availableWidth = 70
 	}
@@ -1157,24 +1682,46 @@ func (m appModel) View() string {
 	if rightWidth < 0 {
 		rightWidth = 0
 	}
+	return appLayout{tableTop: chromeHeight, bodyHeight: bodyHeight, leftWidth: leftWidth, rightWidth: rightWidth}
+}
 
-	left := m.table.renderTableWithTheme(leftWidth, m.activePane == paneTable, 0, m.theme)
-	body := left
-	if rightWidth > 0 {
-		right := m.renderRightColumn(rightWidth, bodyHeight)
-		body = lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+// updateMouse scrolls the table on wheel events and moves the cursor to a
+// clicked row; clicking the highlighted row toggles its selection.
+func (m appModel) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	lay := m.layout()
+	if msg.X < 0 || msg.X >= lay.leftWidth || msg.Y < lay.tableTop || msg.Y >= lay.tableTop+lay.bodyHeight {
+		return m, nil
 	}
-
-	out := make([]string, 0, len(topBanner)+3)
-	out = append(out, topBanner...)
-	out = append(out, "")
-	out = append(out, help, status, body)
-	view := strings.Join(out, "\n")
-	if m.modalActive {
-		backdrop := applyBackdrop(view, m.width, m.height)
-		view = overlayCentered(backdrop, m.renderModalOverlay(), m.width, m.height)
+	m.table.setHeight(lay.bodyHeight)
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.table.scrollBy(-3, 0)
+		return m, nil
+	case tea.MouseButtonWheelDown:
+		m.table.scrollBy(3, 0)
+		return m, nil
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+	default:
+		return m, nil
 	}
-	return view + "\n"
+	// Body rows start below the table's top border, header, and separator.
+	idx, ok := m.table.rowAt(msg.Y-lay.tableTop-3, 0)
+	if !ok {
+		return m, nil
+	}
+	if m.activeMode == modeCommands {
+		m.activeMode = modeBrowse
+	}
+	m.activePane = paneTable
+	if idx == m.table.cursor {
+		m.table.toggleCurrent()
+		return m, nil
+	}
+	m.table.jumpTo(idx, 0)
+	return m, nil
 }
 
 func (m appModel) renderRightColumn(width, height int) string {
@@ -1248,11 +1795,15 @@ func (m appModel) renderDetailPanel(width, height int) string {
 	lines := []string{
 		"Repo Details",
 		fmt.Sprintf("fullName: %s", repo.FullName),
+		fmt.Sprintf("url: %s", repoWebURL(m.callbacks.Host, repo.FullName)),
 		fmt.Sprintf("owner: %s", repo.Owner),
 		fmt.Sprintf("name: %s", repo.Name),
 		fmt.Sprintf("visibility: %s", visibilityLabel(repo)),
 		fmt.Sprintf("fork: %t | archived: %t", repo.IsFork, repo.IsArchived),
 		fmt.Sprintf("updatedAt: %s", repo.UpdatedAt),
+		fmt.Sprintf("size: %s", formatDiskUsage(repo.DiskUsage)),
+		fmt.Sprintf("language: %s", orDash(repo.Language)),
+		fmt.Sprintf("topics: %s", orDash(strings.Join(repo.Topics, ", "))),
 		fmt.Sprintf("description: %s", repo.Description),
 	}
 	if height < 10 {
@@ -1355,7 +1906,7 @@ func (m appModel) renderModalOverlay() string {
 		useRawFit = true
 	case modalRestoreSelectRepo:
 		title = "Restore: Select Repository"
-		lines = append(lines, truncateRaw("Archive: "+m.restoreState.archiveRoot, panelInnerWidth(width)), "Enter choose source repo, h/l scroll, Esc back.", "")
+		lines = append(lines, truncateRaw("Archive: "+m.restoreState.archiveRoot, panelInnerWidth(width)), "Target visibility: "+m.restoreState.visibility()+" (v to change)", "Enter choose source repo, h/l scroll, Esc back.", "")
 		itemWidth := panelInnerWidth(width) - 2
 		if itemWidth < 1 {
 			itemWidth = 1
@@ -1393,14 +1944,31 @@ func (m appModel) renderModalOverlay() string {
 		}
 		lines = append(lines, "", fmt.Sprintf("[j/k: %d/%d | h/l: %d/%d]", pos, len(m.restoreState.repos), clampInt(scroll, 0, maxScroll), maxScroll))
 		useRawFit = true
+	case modalRestoreOwner:
+		title = "Restore Target Owner"
+		lines = append(lines,
+			"Restore "+m.restoreState.selected.fullName+" into which user or organization?",
+			"Edit the owner and press Enter (Esc back).",
+			"",
+			"owner: "+renderInputLineWithCursor(m.restoreState.promptInput, m.cursorVisible),
+		)
 	case modalRestoreYesNo:
 		title = "Restore Confirmation"
 		lines = append(lines,
-			"Use original name for "+m.restoreState.selected.fullName+"?",
+			"Use original name for "+m.restoreState.selected.fullName+" under "+m.restoreState.targetOwner+"?",
 			"Type yes/no and press Enter.",
 			"",
 			"input: "+renderInputLineWithCursor(m.restoreState.promptInput, m.cursorVisible),
 		)
+	case modalRestoreVisibility:
+		title = "Restore Visibility"
+		source := m.restoreState.selected.sourceVisibility
+		lines = append(lines,
+			m.restoreState.selected.fullName+" was "+source+"; restore it as "+m.restoreState.visibility()+"?",
+			"This exposes the repository more widely. Type yes/no and press Enter.",
+			"",
+			"input: "+renderInputLineWithCursor(m.restoreState.promptInput, m.cursorVisible),
+		)
 	case modalRestoreRename:
 		title = "Restore Rename"
 		lines = append(lines,
@@ -1418,9 +1986,22 @@ func (m appModel) renderModalOverlay() string {
 		if desc == "" {
 			desc = "(none)"
 		}
+		backupLine := dangerBold.Render("WARNING:") + dangerStyle.Render(" No backup has been selected.")
+		if m.callbacks.FindBackup != nil {
+			switch b := m.deleteBackup; {
+			case !b.checked:
+				backupLine = "Checking for local backup..."
+			case b.err != nil:
+				backupLine = dangerBold.Render("WARNING:") + dangerStyle.Render(" Backup check failed: "+b.err.Error())
+			case b.found:
+				backupLine = "Backup found at " + b.path
+			default:
+				backupLine = dangerBold.Render("WARNING:") + dangerStyle.Render(" No backup found")
+			}
+		}
 		lines = append(lines,
 			dangerBold.Render("DANGER:")+dangerStyle.Render(" Delete Repository"),
-			dangerBold.Render("WARNING:")+dangerStyle.Render(" No backup has been selected."),
+			backupLine,
 			dangerStyle.Render("This action permanently deletes the GitHub repository."),
 			"",
 			fmt.Sprintf("fullName: %s", m.deleteRepo.FullName),
@@ -1434,6 +2015,60 @@ func (m appModel) renderModalOverlay() string {
 			"Type repo name to confirm: "+bold.Render(m.deleteRepo.Name),
 			renderInputLineWithCursor(m.deleteInput, m.cursorVisible),
 		)
+	case modalDeleteManyConfirm:
+		title = ""
+		popupBorderColor = lipgloss.Color(m.theme.Danger)
+		dangerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.DangerText))
+		dangerBold := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.DangerText)).Bold(true)
+		bold := lipgloss.NewStyle().Bold(true)
+		lines = append(lines,
+			dangerBold.Render("DANGER:")+dangerStyle.Render(fmt.Sprintf(" Delete %d Repositories", len(m.deleteRepos))),
+			dangerBold.Render("WARNING:")+dangerStyle.Render(" No backup has been selected."),
+			dangerStyle.Render("This action permanently deletes every listed GitHub repository."),
+			"",
+		)
+		const maxListed = 10
+		for i, repo := range m.deleteRepos {
+			if i == maxListed {
+				lines = append(lines, fmt.Sprintf("... and %d more", len(m.deleteRepos)-maxListed))
+				break
+			}
+			lines = append(lines, "- "+repo.FullName)
+		}
+		phrase := deleteManyPhrase(len(m.deleteRepos))
+		lines = append(lines,
+			"",
+			"Type to confirm: "+bold.Render(phrase),
+			renderInputLineWithCursor(m.deleteInput, m.cursorVisible),
+		)
+	case modalExecutePreview:
+		title = ""
+		dangerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.DangerText))
+		dangerBold := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.DangerText)).Bold(true)
+		if m.executeDryRun {
+			lines = append(lines, fmt.Sprintf("Execute (dry run): %d repositories", len(m.executePreview)), "")
+		} else {
+			popupBorderColor = lipgloss.Color(m.theme.Danger)
+			lines = append(lines,
+				dangerBold.Render("DANGER:")+dangerStyle.Render(fmt.Sprintf(" Execute will delete %d repositories", len(m.executePreview))),
+				"",
+			)
+		}
+		switch {
+		case m.executePreviewErr != nil:
+			lines = append(lines, dangerBold.Render("WARNING:")+dangerStyle.Render(" Could not read plan: "+m.executePreviewErr.Error()))
+		case len(m.executePreview) == 0:
+			lines = append(lines, "(no repositories selected)")
+		}
+		const maxListed = 12
+		for i, repo := range m.executePreview {
+			if i == maxListed {
+				lines = append(lines, fmt.Sprintf("... and %d more", len(m.executePreview)-maxListed))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("- %s  [%s, fork: %t, archived: %t]", repo.FullName, visibilityLabel(repo), repo.IsFork, repo.IsArchived))
+		}
+		lines = append(lines, "", "Enter to run, Esc to go back to the form.")
 	case modalSettings:
 		title = "Settings"
 		switch m.settings.stage {
@@ -1541,9 +2176,12 @@ func (m appModel) renderModalOverlay() string {
 		}
 	case modalResult:
 		title = "Result"
-		lines = append(lines, m.renderResultModalLines(panelInnerWidth(width), maxLines)...)
+		lines = append(lines, m.renderResultModalLines(panelInnerWidth(width), maxLines, "Enter/Esc close")...)
+	case modalHelp:
+		title = "Help"
+		lines = append(lines, m.renderResultModalLines(panelInnerWidth(width), maxLines, "?/Esc close")...)
 	}
-	if m.modalKind != modalResult {
+	if m.modalKind != modalResult && m.modalKind != modalHelp {
 		if useRawFit {
 			lines = fitLines(lines, maxLines)
 		} else {
@@ -1576,7 +2214,7 @@ func (m appModel) modalMaxLines() int {
 	return h
 }
 
-func (m appModel) renderResultModalLines(maxWidth, maxLines int) []string {
+func (m appModel) renderResultModalLines(maxWidth, maxLines int, closeHint string) []string {
 	lines := wrapLines(strings.Split(m.resultText, "\n"), maxWidth)
 	if len(lines) == 0 {
 		lines = []string{"(no output)"}
@@ -1592,9 +2230,9 @@ func (m appModel) renderResultModalLines(maxWidth, maxLines int) []string {
 		end = len(lines)
 	}
 	visible := append([]string(nil), lines[m.resultScroll:end]...)
-	footer := "Enter/Esc close"
+	footer := closeHint
 	if len(lines) > maxLines-2 {
-		footer = fmt.Sprintf("Up/Down scroll | Enter/Esc close (%d/%d)", m.resultScroll+1, len(lines))
+		footer = fmt.Sprintf("Up/Down scroll | %s (%d/%d)", closeHint, m.resultScroll+1, len(lines))
 	}
 	visible = append(visible, "", footer)
 	return fitLines(visible, maxLines)
@@ -1812,7 +2450,8 @@ func (m appModel) renderTopBanner(maxWidth int) []string {
 	updateAvailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.Success)).Bold(true)
 	updateFailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.TextMuted))
 	updateIndicator := ""
-	if m.settings.updateInfo.UpdateAvailable {
+	checking := m.callbacks.UpdateCheck != nil
+	if checking && m.settings.updateInfo.UpdateAvailable {
 		cur := formatVersionLabel(m.settings.updateInfo.CurrentVersion)
 		if cur == "vdev" {
 			cur = version
@@ -1822,7 +2461,7 @@ func (m appModel) renderTopBanner(maxWidth int) []string {
 			latestVersionStyle.Render(formatVersionLabel(m.settings.updateInfo.LatestVersion)) +
 			versionStyle.Render(" - ") +
 			updateAvailStyle.Render("Update available!")
-	} else if strings.TrimSpace(m.settings.updateInfo.Error) != "" {
+	} else if checking && strings.TrimSpace(m.settings.updateInfo.Error) != "" {
 		updateIndicator = updateFailStyle.Render("update check failed")
 	}
 	for i := range logo {
@@ -1,12 +1,15 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"gh-manager/internal/config"
 	"gh-manager/internal/planfile"
 )
 
@@ -130,6 +133,34 @@ func TestSubmitBackupAllowsBlankPlan(t *testing.T) {
 	}
 }
 
+func TestSubmitExportSelectionPassesSelectedRepos(t *testing.T) {
+	var gotPath string
+	var gotNames []string
+	m := newAppModel([]planfile.RepoRecord{{FullName: "alice/a"}, {FullName: "alice/b"}}, AppCallbacks{
+		ExportSelection: func(selected []planfile.RepoRecord, outPath string) (string, error) {
+			gotPath = outPath
+			for _, r := range selected {
+				gotNames = append(gotNames, r.FullName)
+			}
+			return "selection saved", nil
+		},
+	})
+	m.formCommand = "Export Selection"
+	m.formFields = []formField{{key: "out", value: " sel.txt "}}
+	if _, err := m.submitCommandForm(); err == nil || err.Error() != "no repositories selected" {
+		t.Fatalf("expected empty selection error, got %v", err)
+	}
+	m.table.selected["alice/b"] = true
+	cmd, err := m.submitCommandForm()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := cmd().(commandResultMsg)
+	if msg.err != nil || msg.output != "selection saved" || gotPath != "sel.txt" || len(gotNames) != 1 || gotNames[0] != "alice/b" {
+		t.Fatalf("unexpected export: msg=%+v path=%q names=%v", msg, gotPath, gotNames)
+	}
+}
+
 func TestSubmitExecuteAllowsBlankPlan(t *testing.T) {
 	m := newAppModel(nil, AppCallbacks{})
 	var def commandDef
@@ -277,6 +308,401 @@ func TestDeleteModalRequiresExactRepoName(t *testing.T) {
 	}
 }
 
+func TestDeleteModalShowsBackupLookup(t *testing.T) {
+	repo := planfile.RepoRecord{Owner: "alice", Name: "demo", FullName: "alice/demo"}
+	m := newAppModel([]planfile.RepoRecord{repo}, AppCallbacks{
+		FindBackup: func(_ planfile.RepoRecord) (string, bool, error) {
+			return "/archives/bundles/alice__demo.bundle", true, nil
+		},
+	})
+	m.width = 120
+	m.height = 40
+	cmd := m.openDeleteConfirmModal(repo)
+	if cmd == nil {
+		t.Fatal("expected backup lookup command")
+	}
+	if !strings.Contains(m.View(), "Checking for local backup") {
+		t.Fatal("expected pending backup check in modal")
+	}
+	updated, _ := m.Update(backupCheckedMsg{fullName: repo.FullName, lookup: backupLookup{checked: true, found: true, path: "/archives/bundles/alice__demo.bundle"}})
+	m2 := updated.(appModel)
+	if !strings.Contains(m2.View(), "Backup found at /archives/bundles/alice__demo.bundle") {
+		t.Fatal("expected backup path in modal")
+	}
+	updated, _ = m2.Update(backupCheckedMsg{fullName: repo.FullName, lookup: backupLookup{checked: true}})
+	if !strings.Contains(updated.(appModel).View(), "No backup found") {
+		t.Fatal("expected missing backup warning in modal")
+	}
+}
+
+func TestDeleteSelectedContinuesPastFailures(t *testing.T) {
+	repos := []planfile.RepoRecord{{FullName: "alice/a"}, {FullName: "alice/b"}, {FullName: "alice/c"}}
+	var calls []string
+	m := newAppModel(repos, AppCallbacks{
+		Delete: func(repo planfile.RepoRecord) (string, error) {
+			calls = append(calls, repo.FullName)
+			if repo.FullName == "alice/b" {
+				return "", errors.New("forbidden")
+			}
+			return "ok", nil
+		},
+	})
+	m.table.selected["alice/a"] = true
+	m.table.selected["alice/b"] = true
+	m.table.selected["alice/c"] = true
+	m.activeMode = modeCommands
+	m.activePane = paneCommands
+	for i, c := range m.commands {
+		if c.name == "Delete Selected" {
+			m.cmdCursor = i
+		}
+	}
+	_ = m.openFormForCurrentCommand()
+	if !m.modalActive || m.modalKind != modalDeleteManyConfirm || len(m.deleteRepos) != 3 {
+		t.Fatalf("expected multi-delete modal with 3 repos, got kind=%v repos=%d", m.modalKind, len(m.deleteRepos))
+	}
+	m.deleteInput = "DELETE 2"
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m2 := updated.(appModel)
+	if cmd != nil || !strings.HasPrefix(m2.status, "Error:") {
+		t.Fatalf("expected phrase mismatch error, got status=%q", m2.status)
+	}
+
+	m2.deleteInput = "DELETE 3"
+	_, cmd = m2.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected delete command after correct phrase")
+	}
+	msg, ok := cmd().(commandResultMsg)
+	if !ok {
+		t.Fatal("expected commandResultMsg")
+	}
+	if len(calls) != 3 {
+		t.Fatalf("expected all repos attempted, got %v", calls)
+	}
+	if msg.err != nil || !msg.refreshRepos {
+		t.Fatalf("expected success result with refresh, got %+v", msg)
+	}
+	for _, want := range []string{"Deleted 2 of 3", "deleted alice/a", "FAILED  alice/b: forbidden", "deleted alice/c"} {
+		if !strings.Contains(msg.output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, msg.output)
+		}
+	}
+}
+
+func TestExecutePreviewRequiresEnterAndEscBacksOut(t *testing.T) {
+	repos := []planfile.RepoRecord{{FullName: "alice/a", IsPrivate: true}, {FullName: "alice/b", IsFork: true}}
+	var ran []string
+	m := newAppModel(repos, AppCallbacks{
+		Execute: func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord, _ func(string)) (string, error) {
+			for _, r := range selected {
+				ran = append(ran, r.FullName)
+			}
+			return "done", nil
+		},
+	})
+	m.table.selected["alice/a"] = true
+	m.table.selected["alice/b"] = true
+	m.activeMode = modeCommands
+	m.activePane = paneCommands
+	for i, c := range m.commands {
+		if c.name == "Execute" {
+			m.cmdCursor = i
+		}
+	}
+	_ = m.openFormForCurrentCommand()
+	for i := range m.formFields {
+		if m.formFields[i].key == "confirm" {
+			m.formFields[i].value = "CONFIRM"
+		}
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m2 := updated.(appModel)
+	if cmd != nil || m2.modalKind != modalExecutePreview || len(m2.executePreview) != 2 {
+		t.Fatalf("expected preview modal with 2 repos, got kind=%v repos=%d", m2.modalKind, len(m2.executePreview))
+	}
+	view := m2.renderModalOverlay()
+	if !strings.Contains(view, "alice/a") || !strings.Contains(view, "fork: true") {
+		t.Fatalf("expected repos listed in preview:\n%s", view)
+	}
+
+	updated, _ = m2.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m3 := updated.(appModel)
+	if m3.modalKind != modalCommandForm || !m3.formOpen || m3.formCommand != "Execute" {
+		t.Fatalf("expected esc to return to the execute form, got kind=%v", m3.modalKind)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("execute should not run before final enter, ran %v", ran)
+	}
+
+	updated, _ = m3.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, cmd = updated.(appModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || updated.(appModel).modalActive {
+		t.Fatal("expected execute command after final enter")
+	}
+	var result commandResultMsg
+	for _, msg := range runCmd(cmd) {
+		if r, ok := msg.(commandResultMsg); ok {
+			result = r
+		}
+	}
+	if result.output != "done" || len(ran) != 2 {
+		t.Fatalf("expected execute to run for both repos, ran %v", ran)
+	}
+}
+
+// runCmd runs cmd and any batched commands concurrently, as the bubbletea
+// runtime does, and returns their messages in arrival order.
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		msgs []tea.Msg
+		run  func(tea.Cmd)
+	)
+	run = func(c tea.Cmd) {
+		defer wg.Done()
+		msg := c()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, inner := range batch {
+				if inner != nil {
+					wg.Add(1)
+					go run(inner)
+				}
+			}
+			return
+		}
+		if msg != nil {
+			mu.Lock()
+			msgs = append(msgs, msg)
+			mu.Unlock()
+		}
+		if p, ok := msg.(progressMsg); ok {
+			wg.Add(1)
+			go run(waitForProgress(p.ch))
+		}
+	}
+	wg.Add(1)
+	run(cmd)
+	wg.Wait()
+	return msgs
+}
+
+func TestBackupStreamsProgressIntoResultModal(t *testing.T) {
+	m := newAppModel([]planfile.RepoRecord{{FullName: "alice/a"}}, AppCallbacks{
+		Backup: func(planPath, backupLocation string, dryRun bool, confirmation string, selected []planfile.RepoRecord, progress func(string)) (string, error) {
+			progress("[1/2] backup alice/a")
+			progress("[2/2] backup alice/b")
+			return "backup complete", nil
+		},
+	})
+	m.width, m.height = 120, 40
+	m.table.selected["alice/a"] = true
+	m.formCommand = "Backup"
+	m.formFields = []formField{{key: "confirm", value: "CONFIRM"}}
+	m.busy = true
+	m.status = "Running backup..."
+	cmd, err := m.submitCommandForm()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var progress []progressMsg
+	var result commandResultMsg
+	for _, msg := range runCmd(cmd) {
+		switch v := msg.(type) {
+		case progressMsg:
+			progress = append(progress, v)
+		case commandResultMsg:
+			result = v
+		}
+	}
+	if len(progress) != 2 || result.output != "backup complete" {
+		t.Fatalf("expected 2 progress lines then the summary, got %v / %+v", progress, result)
+	}
+
+	for _, p := range progress {
+		updated, _ := m.Update(p)
+		m = updated.(appModel)
+	}
+	if !m.busy || m.modalKind != modalResult || !strings.Contains(m.resultText, "[2/2] backup alice/b") {
+		t.Fatalf("expected live progress in result modal, busy=%v kind=%v text=%q", m.busy, m.modalKind, m.resultText)
+	}
+	updated, _ := m.Update(result)
+	m = updated.(appModel)
+	if m.busy || m.resultText != "backup complete" || m.progressLines != nil {
+		t.Fatalf("expected final summary to replace progress, busy=%v text=%q", m.busy, m.resultText)
+	}
+}
+
+func TestCustomKeybindingsRemapBrowseKeys(t *testing.T) {
+	repos := []planfile.RepoRecord{{FullName: "alice/a"}, {FullName: "alice/b"}, {FullName: "alice/c"}}
+	bindings := config.DefaultKeybindings()
+	bindings["move_top"] = "g"
+	bindings["move_bottom"] = "G"
+	bindings["select_filtered"] = "A"
+	m := newAppModel(repos, AppCallbacks{Keybindings: bindings})
+
+	updated, _ := m.Update(key("G"))
+	m = updated.(appModel)
+	if m.table.cursor != 2 {
+		t.Fatalf("expected G to jump to bottom, cursor=%d", m.table.cursor)
+	}
+	updated, _ = m.Update(key("g"))
+	m = updated.(appModel)
+	if m.table.cursor != 0 {
+		t.Fatalf("expected g to jump to top, cursor=%d", m.table.cursor)
+	}
+	updated, _ = m.Update(key("A"))
+	m = updated.(appModel)
+	if len(m.table.selected) != 3 {
+		t.Fatalf("expected remapped select_filtered, selected=%d", len(m.table.selected))
+	}
+	updated, _ = m.Update(key("a"))
+	m = updated.(appModel)
+	if m.table.filter != "" {
+		t.Fatalf("expected keys outside / filter input to leave the filter alone, got %q", m.table.filter)
+	}
+	if !strings.Contains(browseHelp(m.keys), "g/G top/bottom") {
+		t.Fatalf("expected help to reflect bindings: %s", browseHelp(m.keys))
+	}
+}
+
+func TestSlashFilterInputTakesBoundKeys(t *testing.T) {
+	repos := []planfile.RepoRecord{{FullName: "alice/hello"}, {FullName: "alice/world"}}
+	m := newAppModel(repos, AppCallbacks{})
+	for _, k := range []string{"/", "h", "e", "l", "l", "q"} {
+		updated, cmd := m.Update(key(k))
+		if cmd != nil {
+			t.Fatalf("key %q must not run a command while filtering", k)
+		}
+		m = updated.(appModel)
+	}
+	if m.table.filter != "hellq" || m.table.hScroll != 0 || m.quitting {
+		t.Fatalf("expected bound keys typed into filter, filter=%q hScroll=%d quitting=%v", m.table.filter, m.table.hScroll, m.quitting)
+	}
+	updated, _ := m.Update(key("backspace"))
+	m = updated.(appModel)
+	updated, _ = m.Update(key("enter"))
+	m = updated.(appModel)
+	if m.table.filtering || m.table.filter != "hell" || len(m.table.filtered) != 1 {
+		t.Fatalf("expected enter to keep filter, filtering=%v filter=%q visible=%d", m.table.filtering, m.table.filter, len(m.table.filtered))
+	}
+	updated, _ = m.Update(key("l"))
+	m = updated.(appModel)
+	if m.table.filter != "hell" {
+		t.Fatalf("expected l to scroll after leaving filter input, filter=%q", m.table.filter)
+	}
+	for _, k := range []string{"/", "x", "esc"} {
+		updated, _ = m.Update(key(k))
+		m = updated.(appModel)
+	}
+	if m.table.filtering || m.table.filter != "" || len(m.table.filtered) != 2 {
+		t.Fatalf("expected esc to clear filter, filtering=%v filter=%q visible=%d", m.table.filtering, m.table.filter, len(m.table.filtered))
+	}
+
+	p := planModel{table: newRepoTable(repos), keys: newKeyMap(nil)}
+	for _, k := range []string{"/", "s", "q", "enter"} {
+		updated, _ := p.Update(key(k))
+		p = updated.(planModel)
+	}
+	if p.saved || p.quitting || p.table.filter != "sq" {
+		t.Fatalf("expected plan picker filter input, saved=%v quitting=%v filter=%q", p.saved, p.quitting, p.table.filter)
+	}
+}
+
+func TestPlanPickerUsesCustomKeybindings(t *testing.T) {
+	repos := []planfile.RepoRecord{{FullName: "alice/a"}, {FullName: "alice/b"}}
+	bindings := config.DefaultKeybindings()
+	bindings["select_filtered"] = "A"
+	bindings["clear_filtered"] = "X"
+	m := planModel{table: newRepoTable(repos), keys: newKeyMap(bindings)}
+
+	updated, _ := m.Update(key("A"))
+	m = updated.(planModel)
+	if len(m.table.selected) != 2 {
+		t.Fatalf("expected remapped select_filtered in the plan picker, selected=%d", len(m.table.selected))
+	}
+	if help := planHelp(m.keys); !strings.Contains(help, "A select filtered, X clear filtered") {
+		t.Fatalf("expected plan help to reflect bindings: %s", help)
+	}
+}
+
+func TestHelpOverlayOpensAndCloses(t *testing.T) {
+	m := newAppModel([]planfile.RepoRecord{{FullName: "alice/a"}}, AppCallbacks{})
+	m.width = 120
+	m.height = 42
+	updated, _ := m.Update(key("?"))
+	m2 := updated.(appModel)
+	if !m2.modalActive || m2.modalKind != modalHelp {
+		t.Fatalf("expected help modal, got kind=%v", m2.modalKind)
+	}
+	if m2.table.filter != "" {
+		t.Fatalf("? must not be typed into the filter, got %q", m2.table.filter)
+	}
+	view := m2.View()
+	for _, want := range []string{"Browse/Select", "Commands", "ctrl+d / ctrl+u", "?/Esc close"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in help overlay", want)
+		}
+	}
+	updated, _ = m2.Update(key("j"))
+	m3 := updated.(appModel)
+	if m3.resultScroll != 1 || m3.table.cursor != 0 {
+		t.Fatalf("expected j to scroll help, scroll=%d cursor=%d", m3.resultScroll, m3.table.cursor)
+	}
+	updated, _ = m3.Update(key("?"))
+	if updated.(appModel).modalActive {
+		t.Fatal("expected ? to close help")
+	}
+	updated, _ = m2.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(appModel).modalActive {
+		t.Fatal("expected esc to close help")
+	}
+}
+
+func TestMouseClickAndWheelOnRepoTable(t *testing.T) {
+	repos := make([]planfile.RepoRecord, 0, 40)
+	for i := 0; i < 40; i++ {
+		repos = append(repos, planfile.RepoRecord{FullName: fmt.Sprintf("alice/repo-%02d", i)})
+	}
+	m := newAppModel(repos, AppCallbacks{})
+	m.width = 120
+	m.height = 30
+	firstRow := m.layout().tableTop + 3
+
+	click := func(m appModel, x, y int) appModel {
+		updated, _ := m.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+		return updated.(appModel)
+	}
+	m = click(m, 5, firstRow+2)
+	if m.table.cursor != 2 || len(m.table.selected) != 0 {
+		t.Fatalf("expected click to move cursor to row 2, cursor=%d selected=%d", m.table.cursor, len(m.table.selected))
+	}
+	m = click(m, 5, firstRow+2)
+	if !m.table.selected["alice/repo-02"] {
+		t.Fatal("expected second click on highlighted row to toggle selection")
+	}
+
+	updated, _ := m.Update(tea.MouseMsg{X: 5, Y: firstRow, Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m = updated.(appModel)
+	if m.table.scroll != 3 || m.table.cursor != 3 {
+		t.Fatalf("expected wheel to scroll viewport by 3, scroll=%d cursor=%d", m.table.scroll, m.table.cursor)
+	}
+	m = click(m, 5, firstRow)
+	if m.table.cursor != 3 {
+		t.Fatalf("expected click to honour scroll offset, cursor=%d", m.table.cursor)
+	}
+
+	before := m.table.cursor
+	m = click(m, 5, 0)
+	m = click(m, m.layout().leftWidth+2, firstRow+1)
+	m = click(m, 5, firstRow-2)
+	if m.table.cursor != before {
+		t.Fatalf("expected clicks outside the table body to be ignored, cursor=%d", m.table.cursor)
+	}
+}
+
 func TestSortToggleBySameKey(t *testing.T) {
 	repos := []planfile.RepoRecord{{FullName: "b/repo"}, {FullName: "a/repo"}}
 	tb := newRepoTable(repos)
@@ -392,6 +818,18 @@ func TestInitDispatchesUpdateCheck(t *testing.T) {
 	}
 }
 
+func TestInitSkipsUpdateCheckWhenDisabled(t *testing.T) {
+	m := newAppModel(nil, AppCallbacks{Version: "v0.1.0"})
+	if cmd := m.Init(); cmd != nil {
+		t.Fatalf("expected no init command with update checks disabled")
+	}
+	m.width = 120
+	m.settings.updateInfo.Error = "dial tcp: no route to host"
+	if banner := strings.Join(m.renderTopBanner(120), "\n"); strings.Contains(stripANSI(banner), "update check failed") {
+		t.Fatalf("expected no update indicator when disabled:\n%s", banner)
+	}
+}
+
 func TestDetailPanelBorderIsAlwaysInactive(t *testing.T) {
 	repos := []planfile.RepoRecord{{FullName: "alice/repo", Name: "repo", Owner: "alice"}}
 	m := newAppModel(repos, AppCallbacks{
@@ -437,7 +875,7 @@ func TestSettingsConfigHomeContainsThemeAndUpdate(t *testing.T) {
 }
 
 func TestBannerShowsUpdateIndicator(t *testing.T) {
-	m := newAppModel(nil, AppCallbacks{Version: "0.1.0"})
+	m := newAppModel(nil, AppCallbacks{Version: "0.1.0", UpdateCheck: func() (UpdateInfo, error) { return UpdateInfo{}, nil }})
 	m.settings.updateInfo = UpdateInfo{
 		CurrentVersion:  "v0.1.0",
 		LatestVersion:   "v0.1.1",
@@ -480,3 +918,93 @@ func key(v string) tea.KeyMsg {
 	r := []rune(v)
 	return tea.KeyMsg{Type: tea.KeyRunes, Runes: r}
 }
+
+func TestSelectMatchingCommandReportsCount(t *testing.T) {
+	m := newAppModel([]planfile.RepoRecord{{FullName: "alice/a1"}, {FullName: "alice/a2"}, {FullName: "alice/b"}}, AppCallbacks{})
+	m.activeMode = modeCommands
+	m.activePane = paneCommands
+	for i, c := range m.commands {
+		if c.name == "Select Matching" {
+			m.cmdCursor = i
+		}
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m2 := updated.(appModel)
+	for _, r := range "alice/a*" {
+		updated, _ = m2.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m2 = updated.(appModel)
+	}
+	updated, _ = m2.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m3 := updated.(appModel)
+	if m3.modalActive {
+		t.Fatal("expected modal to close after selecting")
+	}
+	if len(m3.table.selected) != 2 || !strings.Contains(m3.status, "Selected 2") {
+		t.Fatalf("unexpected result: selected=%v status=%q", m3.table.selected, m3.status)
+	}
+}
+
+func TestOpenInBrowserUsesHighlightedRepo(t *testing.T) {
+	var opened string
+	m := newAppModel([]planfile.RepoRecord{{FullName: "alice/demo"}}, AppCallbacks{
+		OpenInBrowser: func(fullName string) error {
+			opened = fullName
+			return nil
+		},
+	})
+	updated, cmd := m.Update(key("o"))
+	if cmd == nil {
+		t.Fatal("expected open command")
+	}
+	updated, _ = updated.(appModel).Update(cmd())
+	m2 := updated.(appModel)
+	if opened != "alice/demo" || !strings.Contains(m2.status, "Opened alice/demo") {
+		t.Fatalf("unexpected open result: opened=%q status=%q", opened, m2.status)
+	}
+	if !strings.Contains(m2.renderDetailPanel(80, 20), "https://github.com/alice/demo") {
+		t.Fatal("expected repo URL in detail panel")
+	}
+}
+
+func TestOpenInBrowserGuardsEmptySelection(t *testing.T) {
+	called := false
+	m := newAppModel(nil, AppCallbacks{OpenInBrowser: func(string) error { called = true; return nil }})
+	updated, cmd := m.Update(key("o"))
+	if cmd != nil || called {
+		t.Fatal("expected no open command without a repo")
+	}
+	if updated.(appModel).status != "No repository selected" {
+		t.Fatalf("unexpected status: %q", updated.(appModel).status)
+	}
+}
+
+func TestSettingsThemePreviewRevertsOnEsc(t *testing.T) {
+	m := newAppModel(nil, AppCallbacks{
+		Theme: UITheme{PaneBorderActive: "#111111"},
+		ThemePreview: func(id string) (UITheme, error) {
+			return UITheme{PaneBorderActive: "#" + id}, nil
+		},
+	})
+	m.modalActive = true
+	m.modalKind = modalSettings
+	m.settings = settingsState{stage: settingsStageThemeLocalList, mode: settingsModeApply}
+
+	updated, _ := m.Update(settingsLocalMsg{themes: []string{"aaaaaa", "bbbbbb"}, active: "default"})
+	m2 := updated.(appModel)
+	if m2.theme.PaneBorderActive != "#aaaaaa" {
+		t.Fatalf("expected preview of first theme, got %q", m2.theme.PaneBorderActive)
+	}
+	updated, _ = m2.updateSettingsModal("down")
+	m3 := updated.(appModel)
+	if m3.theme.PaneBorderActive != "#bbbbbb" {
+		t.Fatalf("expected preview to follow cursor, got %q", m3.theme.PaneBorderActive)
+	}
+	updated, _ = m3.updateSettingsModal("esc")
+	m4 := updated.(appModel)
+	if m4.theme.PaneBorderActive != "#111111" {
+		t.Fatalf("expected esc to restore original theme, got %q", m4.theme.PaneBorderActive)
+	}
+	if m4.settings.previewActive {
+		t.Fatal("expected preview state cleared")
+	}
+}
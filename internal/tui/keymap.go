@@ -1,5 +1,12 @@
 package tui
 
+import (
+	"fmt"
+	"strings"
+
+	"gh-manager/internal/config"
+)
+
 type activeMode int
 
 type activePane int
@@ -34,13 +41,171 @@ func paneLabel(p activePane) string {
 }
 
 func globalHelp() string {
-	return "Global: 1 browse, 2 commands, 3 details, tab switch pane, q quit"
+	return "Global: 1 browse, 2 commands, 3 details, tab switch pane, ? help, q quit"
+}
+
+// helpText lists every key grouped by mode for the help overlay.
+func helpText(k keyMap) string {
+	type row struct{ key, desc string }
+	sections := []struct {
+		title string
+		rows  []row
+	}{
+		{"Global", []row{
+			{"1 / 2 / 3", "browse / commands / details mode"},
+			{"tab", "switch pane focus"},
+			{"?", "open or close this help"},
+			{"q, ctrl+c", "quit"},
+		}},
+		{"Browse/Select", []row{
+			{k.keyFor("move_down") + " / " + k.keyFor("move_up") + ", down / up", "move cursor"},
+			{k.keyFor("move_top") + " / " + k.keyFor("move_bottom") + ", home / end", "jump to first / last repo"},
+			{k.keyFor("half_page_down") + " / " + k.keyFor("half_page_up"), "half page down / up"},
+			{k.keyFor("page_up") + " / " + k.keyFor("page_down"), "page up / down"},
+			{k.keyFor("scroll_left") + " / " + k.keyFor("scroll_right") + ", left / right", "scroll long names / descriptions"},
+			{k.keyFor("toggle"), "toggle selected repo"},
+			{k.keyFor("select_filtered"), "select all filtered repos"},
+			{k.keyFor("clear_filtered"), "clear all filtered repos"},
+			{k.keyFor("invert_filtered"), "invert selection within filtered repos"},
+			{"/ type, backspace", "edit filter text (enter keeps, esc clears)"},
+			{k.keyFor("regex_filter") + " / " + k.keyFor("backup_filter"), "toggle regex filter / cycle backed-up filter"},
+			{k.keyFor("sort_name"), "sort by name (again to toggle direction)"},
+			{k.keyFor("sort_updated"), "sort by updatedAt"},
+			{k.keyFor("sort_visibility"), "sort by visibility"},
+			{k.keyFor("sort_description"), "sort by description"},
+			{k.keyFor("sort_fork"), "sort forks first"},
+			{k.keyFor("sort_archived"), "sort archived first"},
+			{k.keyFor("sort_size"), "sort by size (largest first)"},
+			{k.keyFor("open_browser"), "open highlighted repo in browser"},
+		}},
+		{"Commands", []row{
+			{k.keyFor("move_down") + " / " + k.keyFor("move_up") + ", down / up", "move command cursor"},
+			{k.keyFor("move_top") + " / " + k.keyFor("move_bottom"), "first / last command"},
+			{"enter", "open form / run command"},
+		}},
+		{"Popups", []row{
+			{"tab", "next form field"},
+			{"space", "toggle boolean field"},
+			{"enter", "submit"},
+			{"esc", "cancel / close"},
+			{"up / down", "scroll results"},
+		}},
+	}
+	var b strings.Builder
+	for i, s := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(s.title + "\n")
+		for _, r := range s.rows {
+			fmt.Fprintf(&b, "  %-22s %s\n", r.key, r.desc)
+		}
+	}
+	return b.String()
+}
+
+func browseHelp(k keyMap) string {
+	return fmt.Sprintf("Browse: %s/%s move, %s/%s top/bottom, %s/%s half page, %s/%s page, %s/%s scroll, %s toggle, %s select filtered, %s clear filtered, %s invert filtered, / filter (enter keep, esc clear), backspace delete, %s regex filter, %s backup filter, %s/%s/%s/%s/%s/%s/%s sort+toggle dir, %s open in browser",
+		k.keyFor("move_down"), k.keyFor("move_up"), k.keyFor("move_top"), k.keyFor("move_bottom"),
+		k.keyFor("half_page_down"), k.keyFor("half_page_up"), k.keyFor("page_up"), k.keyFor("page_down"),
+		k.keyFor("scroll_left"), k.keyFor("scroll_right"),
+		k.keyFor("toggle"), k.keyFor("select_filtered"), k.keyFor("clear_filtered"), k.keyFor("invert_filtered"), k.keyFor("regex_filter"), k.keyFor("backup_filter"),
+		k.keyFor("sort_name"), k.keyFor("sort_updated"), k.keyFor("sort_visibility"), k.keyFor("sort_description"), k.keyFor("sort_fork"), k.keyFor("sort_archived"), k.keyFor("sort_size"),
+		k.keyFor("open_browser"))
+}
+
+func commandHelp(k keyMap) string {
+	return fmt.Sprintf("Commands: %s/%s move, enter open/run. Popup forms suspend shortcuts until Enter/Esc.", k.keyFor("move_down"), k.keyFor("move_up"))
 }
 
-func browseHelp() string {
-	return "Browse: j/k move, pgup/pgdown page, space toggle, a select filtered, x clear filtered, type filter, backspace delete, n/u/v sort+toggle dir"
+// keyMap resolves pressed keys to keybinding actions. The arrow, home, and
+// end keys always move the cursor (or scroll, for left/right) regardless of
+// configuration.
+type keyMap struct {
+	actions map[string]string
+	keys    map[string]string
 }
 
-func commandHelp() string {
-	return "Commands: j/k move, enter open/run. Popup forms suspend shortcuts until Enter/Esc."
+// newKeyMap builds a keyMap from resolved action->key bindings, falling back
+// to the defaults when bindings is nil.
+func newKeyMap(bindings map[string]string) keyMap {
+	if bindings == nil {
+		bindings = config.DefaultKeybindings()
+	}
+	k := keyMap{
+		actions: map[string]string{"up": "move_up", "down": "move_down", "home": "move_top", "end": "move_bottom", "left": "scroll_left", "right": "scroll_right"},
+		keys:    map[string]string{},
+	}
+	for action, key := range bindings {
+		k.keys[action] = key
+		if key == "space" {
+			key = " "
+		}
+		k.actions[key] = action
+	}
+	return k
+}
+
+func (k keyMap) action(key string) string {
+	return k.actions[key]
+}
+
+func (k keyMap) keyFor(action string) string {
+	return k.keys[action]
+}
+
+// applyTableAction runs a table navigation/selection/sort action and reports
+// whether action was one.
+func applyTableAction(t *repoTable, action string, detailsHeight int) bool {
+	switch action {
+	case "move_up":
+		t.moveCursor(-1, detailsHeight)
+	case "move_down":
+		t.moveCursor(1, detailsHeight)
+	case "move_top":
+		t.jumpTo(0, detailsHeight)
+	case "move_bottom":
+		t.jumpToEnd(detailsHeight)
+	case "page_up":
+		t.pageMove(-1, detailsHeight)
+	case "page_down":
+		t.pageMove(1, detailsHeight)
+	case "half_page_up":
+		t.halfPageMove(-1, detailsHeight)
+	case "half_page_down":
+		t.halfPageMove(1, detailsHeight)
+	case "scroll_left":
+		t.scrollHorizontal(-1, detailsHeight)
+	case "scroll_right":
+		t.scrollHorizontal(1, detailsHeight)
+	case "toggle":
+		t.toggleCurrent()
+	case "select_filtered":
+		t.selectAllFiltered()
+	case "clear_filtered":
+		t.clearAllFiltered()
+	case "invert_filtered":
+		t.invertFiltered()
+	case "regex_filter":
+		t.toggleFilterRegex()
+	case "backup_filter":
+		t.cycleBackupFilter()
+	case "sort_name":
+		t.setSortField(sortFieldName)
+	case "sort_updated":
+		t.setSortField(sortFieldUpdated)
+	case "sort_visibility":
+		t.setSortField(sortFieldVisibility)
+	case "sort_description":
+		t.setSortField(sortFieldDescription)
+	case "sort_fork":
+		t.setSortField(sortFieldFork)
+	case "sort_archived":
+		t.setSortField(sortFieldArchived)
+	case "sort_size":
+		t.setSortField(sortFieldSize)
+	default:
+		return false
+	}
+	return true
 }
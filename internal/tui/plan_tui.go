@@ -18,6 +18,7 @@ type planModel struct {
 	quitting   bool
 	saved      bool
 	theme      UITheme
+	keys       keyMap
 }
 
 func SelectRepos(repos []planfile.RepoRecord) ([]planfile.RepoRecord, error) {
@@ -25,7 +26,14 @@ func SelectRepos(repos []planfile.RepoRecord) ([]planfile.RepoRecord, error) {
 }
 
 func SelectReposWithTheme(repos []planfile.RepoRecord, theme UITheme) ([]planfile.RepoRecord, error) {
-	m := planModel{table: newRepoTable(repos), theme: theme.withDefaults()}
+	return SelectReposPreselected(repos, theme, nil, nil)
+}
+
+// SelectReposPreselected runs the plan picker with keybindings (nil uses the
+// defaults) and the repos named in preselected already selected.
+func SelectReposPreselected(repos []planfile.RepoRecord, theme UITheme, keybindings map[string]string, preselected []string) ([]planfile.RepoRecord, error) {
+	m := planModel{table: newRepoTable(repos), theme: theme.withDefaults(), keys: newKeyMap(keybindings)}
+	m.table.preselect(preselected)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
@@ -53,40 +61,26 @@ func (m planModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case tea.KeyMsg:
 		s := msg.String()
+		if m.table.filtering && s != "ctrl+c" {
+			m.table.updateFilterInput(s)
+			return m, nil
+		}
 		switch s {
 		case "ctrl+c", "q":
 			m.quitting = true
 			return m, tea.Quit
-		case "up", "k":
-			m.table.moveCursor(-1, m.detailsHeight())
-		case "down", "j":
-			m.table.moveCursor(1, m.detailsHeight())
-		case "pgup":
-			m.table.pageMove(-1, m.detailsHeight())
-		case "pgdown":
-			m.table.pageMove(1, m.detailsHeight())
-		case " ":
-			m.table.toggleCurrent()
-		case "a":
-			m.table.selectAllFiltered()
-		case "x":
-			m.table.clearAllFiltered()
-		case "n":
-			m.table.setSortField(sortFieldName)
-		case "u":
-			m.table.setSortField(sortFieldUpdated)
-		case "v":
-			m.table.setSortField(sortFieldVisibility)
 		case "enter":
 			m.showDetail = !m.showDetail
 			m.table.ensureVisible(m.detailsHeight())
+		case "/":
+			m.table.filtering = true
 		case "backspace":
 			m.table.backspaceFilter()
 		case "s":
 			m.saved = true
 			return m, tea.Quit
 		default:
-			m.table.appendFilterChar(s)
+			applyTableAction(&m.table, m.keys.action(s), m.detailsHeight())
 		}
 	}
 	return m, nil
@@ -105,8 +99,8 @@ func (m planModel) View() string {
 	m.table.setHeight(m.height)
 
 	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.theme.HeaderText)).Render("gh-manager plan")
-	help := "Keys: j/k move, pgup/pgdown page, space toggle, a select filtered, x clear filtered, n/u/v sort+toggle dir, enter details, s save, q quit"
-	status := fmt.Sprintf("Filter: %s | Sort: %s | Selected: %d | Visible: %d/%d", m.table.filter, sortLabel(m.table.sortBy, m.table.sortDir), len(m.table.selected), len(m.table.filtered), len(m.table.repos))
+	help := planHelp(m.keys)
+	status := fmt.Sprintf("Filter: %s | Sort: %s | Selected: %d | Visible: %d/%d | Est: %s", m.table.filterLabel(), sortLabel(m.table.sortBy, m.table.sortDir), len(m.table.selected), len(m.table.filtered), len(m.table.repos), formatDiskUsage(m.table.selectedDiskUsage()))
 	help = lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.HelpText)).Render(help)
 	status = lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.StatusText)).Render(status)
 
@@ -146,6 +140,9 @@ func (m planModel) renderDetail(totalWidth int) string {
 		colorizeDetailLine(fmt.Sprintf("visibility: %s", visibilityLabel(repo)), m.theme),
 		colorizeDetailLine(fmt.Sprintf("fork: %t | archived: %t", repo.IsFork, repo.IsArchived), m.theme),
 		colorizeDetailLine(fmt.Sprintf("updatedAt: %s", repo.UpdatedAt), m.theme),
+		colorizeDetailLine(fmt.Sprintf("size: %s", formatDiskUsage(repo.DiskUsage)), m.theme),
+		colorizeDetailLine(fmt.Sprintf("language: %s", orDash(repo.Language)), m.theme),
+		colorizeDetailLine(fmt.Sprintf("topics: %s", orDash(strings.Join(repo.Topics, ", "))), m.theme),
 		colorizeDetailLine(fmt.Sprintf("description: %s", repo.Description), m.theme),
 	}
 	if m.height < 18 {
@@ -162,3 +159,13 @@ func (m planModel) renderDetail(totalWidth int) string {
 		Width(max(40, totalWidth-2)).
 		Render(strings.Join(lines, "\n"))
 }
+
+func planHelp(k keyMap) string {
+	return fmt.Sprintf("Keys: %s/%s move, %s/%s top/bottom, %s/%s half page, %s/%s page, %s/%s scroll, %s toggle, %s select filtered, %s clear filtered, %s invert filtered, %s/%s/%s/%s/%s/%s/%s sort+toggle dir, / filter (enter keep, esc clear), %s regex filter, enter details, s save, q quit",
+		k.keyFor("move_down"), k.keyFor("move_up"), k.keyFor("move_top"), k.keyFor("move_bottom"),
+		k.keyFor("half_page_down"), k.keyFor("half_page_up"), k.keyFor("page_up"), k.keyFor("page_down"),
+		k.keyFor("scroll_left"), k.keyFor("scroll_right"),
+		k.keyFor("toggle"), k.keyFor("select_filtered"), k.keyFor("clear_filtered"), k.keyFor("invert_filtered"),
+		k.keyFor("sort_name"), k.keyFor("sort_updated"), k.keyFor("sort_visibility"), k.keyFor("sort_description"), k.keyFor("sort_fork"), k.keyFor("sort_archived"), k.keyFor("sort_size"),
+		k.keyFor("regex_filter"))
+}
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -18,6 +20,10 @@ const (
 	sortFieldName sortField = iota
 	sortFieldUpdated
 	sortFieldVisibility
+	sortFieldDescription
+	sortFieldFork
+	sortFieldArchived
+	sortFieldSize
 )
 
 const (
@@ -25,11 +31,25 @@ const (
 	sortDesc
 )
 
+// backupFilter narrows the table by whether a repo has a local archive copy.
+type backupFilter int
+
+const (
+	backupFilterAll backupFilter = iota
+	backupFilterPresent
+	backupFilterMissing
+)
+
 type columnSpec struct {
 	title  string
 	min    int
 	max    int
 	weight int
+	// center and right align body cells (headers of right-aligned columns
+	// stay left-aligned); color picks the body foreground from the theme.
+	center bool
+	right  bool
+	color  func(UITheme) string
 }
 
 type repoTable struct {
@@ -42,6 +62,20 @@ type repoTable struct {
 	sortBy   sortField
 	sortDir  sortDirection
 	height   int
+	// hScroll shifts the Name and Description cells left so long values can
+	// be read in place.
+	hScroll int
+
+	filterIsRegex bool
+	filterErr     string
+	// filtering is set after "/" so every printable key edits the filter
+	// instead of triggering its binding.
+	filtering bool
+
+	// backedUp holds repos found in a local archive; nil means the archive
+	// scan is disabled or has not finished, and hides the Bak column.
+	backedUp     map[string]bool
+	backupFilter backupFilter
 }
 
 func newRepoTable(repos []planfile.RepoRecord) repoTable {
@@ -68,6 +102,21 @@ func (t *repoTable) replaceRepos(repos []planfile.RepoRecord) {
 	t.recompute()
 }
 
+func (t *repoTable) preselect(fullNames []string) int {
+	known := make(map[string]bool, len(t.repos))
+	for _, r := range t.repos {
+		known[r.FullName] = true
+	}
+	n := 0
+	for _, name := range fullNames {
+		if known[name] && !t.selected[name] {
+			t.selected[name] = true
+			n++
+		}
+	}
+	return n
+}
+
 func (t *repoTable) setHeight(h int) {
 	t.height = h
 	t.ensureVisible(0)
@@ -92,6 +141,76 @@ func (t *repoTable) pageMove(delta int, detailsHeight int) {
 	t.moveCursor(delta*rows, detailsHeight)
 }
 
+// jumpTo moves the cursor to filtered row idx, clamped to the visible rows.
+func (t *repoTable) jumpTo(idx int, detailsHeight int) {
+	t.moveCursor(idx-t.cursor, detailsHeight)
+}
+
+func (t *repoTable) jumpToEnd(detailsHeight int) {
+	t.jumpTo(len(t.filtered)-1, detailsHeight)
+}
+
+func (t *repoTable) halfPageMove(delta int, detailsHeight int) {
+	rows := t.tableBodyRows(detailsHeight) / 2
+	if rows < 1 {
+		rows = 1
+	}
+	t.moveCursor(delta*rows, detailsHeight)
+}
+
+// scrollBy moves the viewport without paging the cursor, keeping the cursor
+// on a visible row.
+func (t *repoTable) scrollBy(delta int, detailsHeight int) {
+	if len(t.filtered) == 0 {
+		return
+	}
+	rows := t.tableBodyRows(detailsHeight)
+	maxScroll := len(t.filtered) - rows
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	t.scroll = clampInt(t.scroll+delta, 0, maxScroll)
+	t.cursor = clampInt(t.cursor, t.scroll, t.scroll+rows-1)
+	if t.cursor > len(t.filtered)-1 {
+		t.cursor = len(t.filtered) - 1
+	}
+}
+
+// scrollHorizontal moves the Name/Description offset, clamped to the longest
+// of those cells among the visible rows.
+func (t *repoTable) scrollHorizontal(delta int, detailsHeight int) {
+	t.hScroll = clampInt(t.hScroll+delta, 0, t.maxHScroll(detailsHeight))
+}
+
+func (t repoTable) maxHScroll(detailsHeight int) int {
+	end := t.scroll + t.tableBodyRows(detailsHeight)
+	if end > len(t.filtered) {
+		end = len(t.filtered)
+	}
+	longest := 0
+	for i := t.scroll; i < end; i++ {
+		repo := t.repos[t.filtered[i]]
+		for _, s := range []string{repo.FullName, repo.Description} {
+			if n := len([]rune(s)); n > longest {
+				longest = n
+			}
+		}
+	}
+	return max(longest-1, 0)
+}
+
+// rowAt maps a visible body row offset to a filtered row index.
+func (t repoTable) rowAt(offset int, detailsHeight int) (int, bool) {
+	if offset < 0 || offset >= t.tableBodyRows(detailsHeight) {
+		return 0, false
+	}
+	idx := t.scroll + offset
+	if idx >= len(t.filtered) {
+		return 0, false
+	}
+	return idx, true
+}
+
 func (t *repoTable) toggleCurrent() {
 	if len(t.filtered) == 0 || t.cursor < 0 || t.cursor >= len(t.filtered) {
 		return
@@ -116,6 +235,42 @@ func (t *repoTable) clearAllFiltered() {
 	}
 }
 
+// invertFiltered flips the selection of every filtered repo, leaving repos
+// hidden by the filter untouched.
+func (t *repoTable) invertFiltered() {
+	for _, idx := range t.filtered {
+		name := t.repos[idx].FullName
+		if t.selected[name] {
+			delete(t.selected, name)
+		} else {
+			t.selected[name] = true
+		}
+	}
+}
+
+// selectMatching selects every repo whose FullName matches pattern,
+// ignoring the active filter. Patterns follow selection files (see
+// planfile.SelectionMatcher): an exact name, a glob, or /expr/ or re:expr.
+func (t *repoTable) selectMatching(pattern string) (int, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return 0, fmt.Errorf("pattern is required")
+	}
+	match, err := planfile.SelectionMatcher(pattern)
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for _, r := range t.repos {
+		if !match(r.FullName) || t.selected[r.FullName] {
+			continue
+		}
+		t.selected[r.FullName] = true
+		added++
+	}
+	return added, nil
+}
+
 func (t *repoTable) backspaceFilter() {
 	if len(t.filter) == 0 {
 		return
@@ -135,6 +290,67 @@ func (t *repoTable) appendFilterChar(ch string) {
 	t.recompute()
 }
 
+// updateFilterInput handles a key while filter input is active: enter keeps
+// the filter, esc clears it, and anything else edits the text.
+func (t *repoTable) updateFilterInput(key string) {
+	switch key {
+	case "enter":
+		t.filtering = false
+	case "esc":
+		t.filtering = false
+		t.filter = ""
+		t.recompute()
+	case "backspace":
+		t.backspaceFilter()
+	default:
+		t.appendFilterChar(key)
+	}
+}
+
+func (t *repoTable) toggleFilterRegex() {
+	t.filterIsRegex = !t.filterIsRegex
+	t.recompute()
+}
+
+func (t repoTable) filterLabel() string {
+	label := t.filter
+	if t.filterIsRegex {
+		label = "/" + t.filter + "/"
+		if t.filterErr != "" {
+			label += " (" + t.filterErr + ")"
+		}
+	}
+	if t.filtering {
+		label += "_"
+	}
+	switch t.backupFilter {
+	case backupFilterPresent:
+		label += " [backed up]"
+	case backupFilterMissing:
+		label += " [not backed up]"
+	}
+	return label
+}
+
+func (t *repoTable) setBackedUp(fullNames map[string]bool) {
+	if fullNames == nil {
+		fullNames = map[string]bool{}
+	}
+	t.backedUp = fullNames
+	t.recompute()
+}
+
+// cycleBackupFilter steps through all -> backed up -> not backed up. It
+// does nothing until an archive index has been loaded.
+func (t *repoTable) cycleBackupFilter() bool {
+	if t.backedUp == nil {
+		return false
+	}
+	t.backupFilter = (t.backupFilter + 1) % 3
+	t.recompute()
+	return true
+}
+
 func (t *repoTable) setSortField(field sortField) {
 	if t.sortBy == field {
 		if t.sortDir == sortAsc {
@@ -145,7 +361,7 @@ func (t *repoTable) setSortField(field sortField) {
 	} else {
 		t.sortBy = field
 		t.sortDir = sortAsc
-		if field == sortFieldUpdated {
+		if field == sortFieldUpdated || field == sortFieldSize {
 			t.sortDir = sortDesc
 		}
 	}
@@ -163,6 +379,18 @@ func (t *repoTable) selectedReposSorted() []planfile.RepoRecord {
 	return out
 }
 
+// selectedDiskUsage sums diskUsage (KiB) over the selected repos, as a rough
+// estimate of how much a backup of the selection will pull.
+func (t *repoTable) selectedDiskUsage() int64 {
+	var total int64
+	for _, r := range t.repos {
+		if t.selected[r.FullName] {
+			total += r.DiskUsage
+		}
+	}
+	return total
+}
+
 func (t *repoTable) currentRepo() (planfile.RepoRecord, bool) {
 	if len(t.filtered) == 0 || t.cursor < 0 || t.cursor >= len(t.filtered) {
 		return planfile.RepoRecord{}, false
@@ -173,8 +401,28 @@ func (t *repoTable) currentRepo() (planfile.RepoRecord, bool) {
 func (t *repoTable) recompute() {
 	indexes := make([]int, 0, len(t.repos))
 	needle := strings.ToLower(strings.TrimSpace(t.filter))
+	t.filterErr = ""
+	var re *regexp.Regexp
+	if t.filterIsRegex && needle != "" {
+		compiled, err := regexp.Compile("(?i)" + strings.TrimSpace(t.filter))
+		if err != nil {
+			t.filterErr = "invalid regex, using substring"
+		} else {
+			re = compiled
+		}
+	}
 	for i, r := range t.repos {
-		hay := strings.ToLower(strings.Join([]string{r.FullName, r.Name, r.Description, visibilitySortValue(r), visibilityLabel(r), r.UpdatedAt}, " "))
+		if t.backupFilter == backupFilterPresent && !t.backedUp[r.FullName] ||
+			t.backupFilter == backupFilterMissing && t.backedUp[r.FullName] {
+			continue
+		}
+		if re != nil {
+			if re.MatchString(r.FullName) || re.MatchString(r.Description) || re.MatchString(r.Language) || re.MatchString(strings.Join(r.Topics, " ")) {
+				indexes = append(indexes, i)
+			}
+			continue
+		}
+		hay := strings.ToLower(strings.Join([]string{r.FullName, r.Name, r.Description, visibilitySortValue(r), visibilityLabel(r), r.UpdatedAt, r.Language, strings.Join(r.Topics, " ")}, " "))
 		if needle == "" || strings.Contains(hay, needle) {
 			indexes = append(indexes, i)
 		}
@@ -229,20 +477,67 @@ func compareRepos(a, b planfile.RepoRecord, field sortField) int {
 			return strings.Compare(a.FullName, b.FullName)
 		}
 		return strings.Compare(av, bv)
+	case sortFieldDescription:
+		ad := strings.ToLower(strings.TrimSpace(a.Description))
+		bd := strings.ToLower(strings.TrimSpace(b.Description))
+		if ad == bd {
+			return strings.Compare(a.FullName, b.FullName)
+		}
+		if ad == "" {
+			return 1
+		}
+		if bd == "" {
+			return -1
+		}
+		return strings.Compare(ad, bd)
+	case sortFieldFork:
+		return compareFlag(a.IsFork, b.IsFork, a, b)
+	case sortFieldArchived:
+		return compareFlag(a.IsArchived, b.IsArchived, a, b)
+	case sortFieldSize:
+		if a.DiskUsage == b.DiskUsage {
+			return strings.Compare(a.FullName, b.FullName)
+		}
+		if a.DiskUsage < b.DiskUsage {
+			return -1
+		}
+		return 1
 	default:
 		return strings.Compare(a.FullName, b.FullName)
 	}
 }
 
-func parseUpdatedAt(v string) (time.Time, bool) {
-	if v == "" {
-		return time.Time{}, false
+// formatDiskUsage renders a GitHub diskUsage value (KiB) for the Size column.
+func formatDiskUsage(kib int64) string {
+	return planfile.FormatDiskUsage(kib)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
 	}
-	t, err := time.Parse(time.RFC3339, v)
-	if err != nil {
-		return time.Time{}, false
+	return s
+}
+
+func padLeft(s string, width int) string {
+	if n := lipgloss.Width(s); n < width {
+		return strings.Repeat(" ", width-n) + s
 	}
-	return t, true
+	return s
+}
+
+func compareFlag(av, bv bool, a, b planfile.RepoRecord) int {
+	if av == bv {
+		return strings.Compare(a.FullName, b.FullName)
+	}
+	if av {
+		return -1
+	}
+	return 1
+}
+
+func parseUpdatedAt(v string) (time.Time, bool) {
+	return planfile.ParseUpdatedAt(v)
 }
 
 func (t *repoTable) ensureVisible(detailsHeight int) {
@@ -290,13 +585,19 @@ func (t repoTable) tableBodyRows(detailsHeight int) int {
 
 func (t repoTable) renderTableWithTheme(totalWidth int, focused bool, detailsHeight int, theme UITheme) string {
 	cols := []columnSpec{
-		{title: "Sel", min: 3, max: 3, weight: 0},
-		{title: "Name", min: 16, max: 34, weight: 2},
-		{title: "Vis", min: 7, max: 8, weight: 1},
-		{title: "Fork", min: 4, max: 5, weight: 1},
-		{title: "Arch", min: 4, max: 5, weight: 1},
-		{title: "Updated", min: 10, max: 20, weight: 2},
-		{title: "Description", min: 16, max: 48, weight: 5},
+		{title: "Sel", min: 3, max: 3, weight: 0, center: true, color: func(th UITheme) string { return th.ColSel }},
+		{title: "Name", min: 16, max: 34, weight: 2, color: func(th UITheme) string { return th.ColName }},
+		{title: "Vis", min: 7, max: 8, weight: 1, center: true, color: func(th UITheme) string { return th.ColVisibility }},
+		{title: "Fork", min: 4, max: 5, weight: 1, center: true, color: func(th UITheme) string { return th.ColFork }},
+		{title: "Arch", min: 4, max: 5, weight: 1, center: true, color: func(th UITheme) string { return th.ColArchived }},
+		{title: "Updated", min: 10, max: 20, weight: 2, color: func(th UITheme) string { return th.ColUpdated }},
+		{title: "Size", min: 7, max: 9, weight: 1, right: true, color: func(th UITheme) string { return th.ColUpdated }},
+		{title: "Description", min: 16, max: 48, weight: 5, color: func(th UITheme) string { return th.ColDescription }},
+	}
+	showBackup := t.backedUp != nil
+	if showBackup {
+		bak := columnSpec{title: "Bak", min: 3, max: 4, weight: 0, center: true, color: func(th UITheme) string { return th.ColArchived }}
+		cols = append(cols[:5], append([]columnSpec{bak}, cols[5:]...)...)
 	}
 	widths := allocateColumnWidths(totalWidth-2, cols)
 	rowLimit := t.tableBodyRows(detailsHeight)
@@ -311,9 +612,14 @@ func (t repoTable) renderTableWithTheme(totalWidth int, focused bool, detailsHei
 
 	lines := make([]string, 0, rowLimit+6)
 	lines = append(lines, drawBorder("┌", "┬", "┐", widths))
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.title
+	}
 	lines = append(lines, drawRow(
-		[]string{"Sel", "Name", "Vis", "Fork", "Arch", "Updated", "Description"},
+		header,
 		widths,
+		cols,
 		false,
 		theme,
 		true,
@@ -325,19 +631,28 @@ func (t repoTable) renderTableWithTheme(totalWidth int, focused bool, detailsHei
 		if t.selected[repo.FullName] {
 			mark = "[x]"
 		}
-		line := drawRow([]string{
+		name, desc := repo.FullName, repo.Description
+		if t.hScroll > 0 {
+			name, _, _ = windowedText(name, t.hScroll, widths[1])
+			desc, _, _ = windowedText(desc, t.hScroll, widths[len(widths)-1])
+		}
+		values := []string{
 			mark,
-			repo.FullName,
+			name,
 			visibilityGlyph(repo),
 			forkGlyph(repo.IsFork),
 			archiveGlyph(repo.IsArchived),
 			repo.UpdatedAt,
-			repo.Description,
-		}, widths, i == t.cursor, theme, false)
-		lines = append(lines, line)
+			formatDiskUsage(repo.DiskUsage),
+			desc,
+		}
+		if showBackup {
+			values = append(values[:5], append([]string{backupGlyph(t.backedUp[repo.FullName])}, values[5:]...)...)
+		}
+		lines = append(lines, drawRow(values, widths, cols, i == t.cursor, theme, false))
 	}
 	for i := end; i < start+rowLimit; i++ {
-		lines = append(lines, drawRow([]string{"", "", "", "", "", "", ""}, widths, false, theme, false))
+		lines = append(lines, drawRow(make([]string, len(cols)), widths, cols, false, theme, false))
 	}
 	lines = append(lines, drawBorder("└", "┴", "┘", widths))
 
@@ -380,6 +695,13 @@ func archiveGlyph(v bool) string {
 	return ""
 }
 
+func backupGlyph(v bool) string {
+	if v {
+		return ""
+	}
+	return ""
+}
+
 func sortLabel(field sortField, dir sortDirection) string {
 	name := "name"
 	switch field {
@@ -387,6 +709,14 @@ func sortLabel(field sortField, dir sortDirection) string {
 		name = "updatedAt"
 	case sortFieldVisibility:
 		name = "visibility"
+	case sortFieldDescription:
+		name = "description"
+	case sortFieldFork:
+		name = "fork"
+	case sortFieldArchived:
+		name = "archived"
+	case sortFieldSize:
+		name = "size"
 	}
 	direction := "asc"
 	if dir == sortDesc {
@@ -408,18 +738,9 @@ func drawBorder(left, mid, right string, widths []int) string {
 	return strings.Join(parts, "")
 }
 
-func drawRow(values []string, widths []int, selected bool, theme UITheme, isHeader bool) string {
+func drawRow(values []string, widths []int, cols []columnSpec, selected bool, theme UITheme, isHeader bool) string {
 	parts := make([]string, 0, len(widths)+2)
 	parts = append(parts, "│")
-	columnColors := []string{
-		theme.ColSel,
-		theme.ColName,
-		theme.ColVisibility,
-		theme.ColFork,
-		theme.ColArchived,
-		theme.ColUpdated,
-		theme.ColDescription,
-	}
 	for i := range widths {
 		v := ""
 		if i < len(values) {
@@ -427,10 +748,13 @@ func drawRow(values []string, widths []int, selected bool, theme UITheme, isHead
 		}
 		cellText := truncate(v, widths[i])
 		cell := pad(cellText, widths[i])
-		if i == 0 || i == 2 || i == 3 || i == 4 {
+		if cols[i].center {
 			cell = center(cellText, widths[i])
 		}
-		cellStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(columnColors[i]))
+		if cols[i].right && !isHeader {
+			cell = padLeft(cellText, widths[i])
+		}
+		cellStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(cols[i].color(theme)))
 		if isHeader {
 			cellStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.TableHeader)).Bold(true)
 		}
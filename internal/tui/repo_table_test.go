@@ -0,0 +1,337 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gh-manager/internal/planfile"
+)
+
+func filteredNames(tb repoTable) []string {
+	out := make([]string, 0, len(tb.filtered))
+	for _, idx := range tb.filtered {
+		out = append(out, tb.repos[idx].FullName)
+	}
+	return out
+}
+
+func TestRecomputeSubstringAndRegexModes(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{
+		{FullName: "alice/api-server", Description: "backend"},
+		{FullName: "alice/web", Description: "frontend for api"},
+		{FullName: "alice/tools-v2", Description: "misc"},
+	})
+
+	tb.filter = "api"
+	tb.recompute()
+	if got := filteredNames(tb); len(got) != 2 {
+		t.Fatalf("substring mode: expected 2 matches, got %v", got)
+	}
+
+	tb.filter = "^alice/(web|tools)"
+	tb.recompute()
+	if got := filteredNames(tb); len(got) != 0 {
+		t.Fatalf("substring mode should not interpret regex, got %v", got)
+	}
+	tb.toggleFilterRegex()
+	if got := filteredNames(tb); len(got) != 2 || got[0] != "alice/tools-v2" || got[1] != "alice/web" {
+		t.Fatalf("regex mode: unexpected matches %v", got)
+	}
+
+	tb.filter = "v\\d$"
+	tb.recompute()
+	if got := filteredNames(tb); len(got) != 1 || got[0] != "alice/tools-v2" {
+		t.Fatalf("regex mode: unexpected matches %v", got)
+	}
+}
+
+func TestRecomputeMatchesLanguageAndTopics(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{
+		{FullName: "alice/svc", Language: "Go"},
+		{FullName: "alice/site", Language: "TypeScript", Topics: []string{"deprecated", "web"}},
+		{FullName: "alice/notes"},
+	})
+	tb.filter = "deprecated"
+	tb.recompute()
+	if got := filteredNames(tb); len(got) != 1 || got[0] != "alice/site" {
+		t.Fatalf("topic filter: unexpected matches %v", got)
+	}
+	tb.filter = "^go$"
+	tb.toggleFilterRegex()
+	if got := filteredNames(tb); len(got) != 1 || got[0] != "alice/svc" {
+		t.Fatalf("language regex: unexpected matches %v", got)
+	}
+}
+
+func TestRecomputeInvalidRegexFallsBack(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{{FullName: "alice/a(b"}, {FullName: "alice/c"}})
+	tb.filterIsRegex = true
+	tb.filter = "a(b"
+	tb.recompute()
+	if tb.filterErr == "" {
+		t.Fatal("expected filter error for invalid pattern")
+	}
+	if got := filteredNames(tb); len(got) != 1 || got[0] != "alice/a(b" {
+		t.Fatalf("expected substring fallback, got %v", got)
+	}
+	tb.filter = "a"
+	tb.recompute()
+	if tb.filterErr != "" {
+		t.Fatalf("expected error cleared, got %q", tb.filterErr)
+	}
+}
+
+func TestSelectMatchingIgnoresFilter(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{
+		{FullName: "alice/tmp-one"},
+		{FullName: "alice/tmp-two"},
+		{FullName: "alice/keep"},
+	})
+	tb.filter = "keep"
+	tb.recompute()
+	tb.selected["alice/tmp-one"] = true
+
+	added, err := tb.selectMatching("alice/tmp-*")
+	if err != nil {
+		t.Fatalf("select matching: %v", err)
+	}
+	if added != 1 || !tb.selected["alice/tmp-two"] || tb.selected["alice/keep"] {
+		t.Fatalf("unexpected selection: added=%d selected=%v", added, tb.selected)
+	}
+	if len(tb.filtered) != 1 {
+		t.Fatalf("expected filter untouched, visible=%d", len(tb.filtered))
+	}
+
+	added, err = tb.selectMatching("/^alice/k/")
+	if err != nil || added != 1 || !tb.selected["alice/keep"] {
+		t.Fatalf("regex select: added=%d err=%v", added, err)
+	}
+	tb.selected = map[string]bool{}
+	if added, err = tb.selectMatching("ALICE/TMP-*"); err != nil || added != 2 {
+		t.Fatalf("globs should match case-insensitively like selection files: added=%d err=%v", added, err)
+	}
+	if _, err := tb.selectMatching("re:("); err == nil {
+		t.Fatal("expected invalid regex error")
+	}
+	if _, err := tb.selectMatching("[a-"); err == nil {
+		t.Fatal("expected invalid glob error")
+	}
+}
+
+func TestCompareReposNewSortFields(t *testing.T) {
+	fork := planfile.RepoRecord{FullName: "a/fork", IsFork: true, Description: "beta"}
+	archived := planfile.RepoRecord{FullName: "a/old", IsArchived: true, Description: "Alpha"}
+	plain := planfile.RepoRecord{FullName: "a/plain"}
+
+	cases := []struct {
+		name  string
+		a, b  planfile.RepoRecord
+		field sortField
+		want  int
+	}{
+		{"fork first", fork, plain, sortFieldFork, -1},
+		{"non-fork after", plain, fork, sortFieldFork, 1},
+		{"archived first", archived, plain, sortFieldArchived, -1},
+		{"equal flags fall back to name", fork, plain, sortFieldArchived, -1},
+		{"description case-insensitive", archived, fork, sortFieldDescription, -1},
+		{"empty description last", plain, fork, sortFieldDescription, 1},
+	}
+	for _, tc := range cases {
+		if got := compareRepos(tc.a, tc.b, tc.field); got != tc.want {
+			t.Fatalf("%s: got %d want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSetSortFieldNewFieldsToggleDirection(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{{FullName: "a/x"}, {FullName: "a/y", IsFork: true}})
+	tb.setSortField(sortFieldFork)
+	if tb.sortBy != sortFieldFork || tb.sortDir != sortAsc {
+		t.Fatalf("unexpected sort: %v %v", tb.sortBy, tb.sortDir)
+	}
+	if tb.repos[tb.filtered[0]].FullName != "a/y" {
+		t.Fatalf("expected fork first, got %v", filteredNames(tb))
+	}
+	tb.setSortField(sortFieldFork)
+	if tb.sortDir != sortDesc || tb.repos[tb.filtered[0]].FullName != "a/x" {
+		t.Fatalf("expected toggled direction, got %v %v", tb.sortDir, filteredNames(tb))
+	}
+	if got := sortLabel(sortFieldArchived, sortAsc); got != "archived asc" {
+		t.Fatalf("unexpected label: %s", got)
+	}
+}
+
+func TestSizeColumnSortAndFormat(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{
+		{FullName: "a/small", DiskUsage: 12},
+		{FullName: "a/huge", DiskUsage: 3 * 1024 * 1024},
+		{FullName: "a/mid", DiskUsage: 1536},
+		{FullName: "a/unknown"},
+	})
+	tb.setSortField(sortFieldSize)
+	if got := filteredNames(tb); tb.sortDir != sortDesc || got[0] != "a/huge" || got[1] != "a/mid" || got[3] != "a/unknown" {
+		t.Fatalf("expected largest first, got %v (%v)", got, tb.sortDir)
+	}
+	if got := sortLabel(sortFieldSize, sortDesc); got != "size desc" {
+		t.Fatalf("unexpected label: %s", got)
+	}
+	for kib, want := range map[int64]string{0: "-", 12: "12 KiB", 1536: "1.5 MiB", 3 * 1024 * 1024: "3.0 GiB"} {
+		if got := formatDiskUsage(kib); got != want {
+			t.Fatalf("formatDiskUsage(%d)=%q want %q", kib, got, want)
+		}
+	}
+	tb.setHeight(20)
+	out := tb.renderTableWithTheme(140, true, 0, defaultUITheme())
+	if !strings.Contains(out, "Size") || !strings.Contains(out, " 1.5 MiB│") {
+		t.Fatalf("expected right-aligned size cell:\n%s", out)
+	}
+}
+
+func TestPreselectOnlyKeepsExistingRepos(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{{FullName: "alice/a"}, {FullName: "alice/b"}})
+	if n := tb.preselect([]string{"alice/a", "alice/gone"}); n != 1 {
+		t.Fatalf("expected 1 preselected, got %d", n)
+	}
+	if !tb.selected["alice/a"] || tb.selected["alice/gone"] || len(tb.selected) != 1 {
+		t.Fatalf("unexpected selection: %v", tb.selected)
+	}
+}
+
+func TestJumpAndHalfPageKeepCursorVisible(t *testing.T) {
+	repos := make([]planfile.RepoRecord, 100)
+	for i := range repos {
+		repos[i] = planfile.RepoRecord{FullName: fmt.Sprintf("alice/r%03d", i)}
+	}
+	tb := newRepoTable(repos)
+	tb.height = 24 // 20 body rows
+
+	tb.jumpToEnd(0)
+	if tb.cursor != 99 || tb.scroll != 80 {
+		t.Fatalf("jumpToEnd: cursor=%d scroll=%d", tb.cursor, tb.scroll)
+	}
+	tb.halfPageMove(-1, 0)
+	if tb.cursor != 89 || tb.scroll != 80 {
+		t.Fatalf("half page up: cursor=%d scroll=%d", tb.cursor, tb.scroll)
+	}
+	tb.jumpTo(0, 0)
+	if tb.cursor != 0 || tb.scroll != 0 {
+		t.Fatalf("jumpTo(0): cursor=%d scroll=%d", tb.cursor, tb.scroll)
+	}
+	tb.halfPageMove(1, 0)
+	tb.halfPageMove(1, 0)
+	tb.halfPageMove(1, 0)
+	if tb.cursor != 30 || tb.cursor < tb.scroll || tb.cursor >= tb.scroll+20 {
+		t.Fatalf("half page down: cursor=%d scroll=%d", tb.cursor, tb.scroll)
+	}
+
+	tb.filter = "r00"
+	tb.recompute()
+	tb.jumpToEnd(0)
+	if got := tb.repos[tb.filtered[tb.cursor]].FullName; got != "alice/r009" {
+		t.Fatalf("jumpToEnd should use filtered rows, got %s", got)
+	}
+}
+
+func TestSelectedDiskUsageSumsSelection(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{
+		{FullName: "alice/a", DiskUsage: 1024},
+		{FullName: "alice/b", DiskUsage: 512},
+		{FullName: "alice/c", DiskUsage: 2048},
+	})
+	if got := tb.selectedDiskUsage(); got != 0 {
+		t.Fatalf("expected 0 with nothing selected, got %d", got)
+	}
+	tb.toggleCurrent()
+	tb.moveCursor(1, 0)
+	tb.toggleCurrent()
+	if got := tb.selectedDiskUsage(); got != 1536 {
+		t.Fatalf("expected 1536 KiB, got %d", got)
+	}
+	if got := formatDiskUsage(tb.selectedDiskUsage()); got != "1.5 MiB" {
+		t.Fatalf("unexpected estimate %q", got)
+	}
+}
+
+func TestBackupFilterCyclesAndMarksRows(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{
+		{FullName: "alice/a"},
+		{FullName: "alice/b"},
+		{FullName: "alice/c"},
+	})
+	if tb.cycleBackupFilter() {
+		t.Fatal("backup filter should be inert before the archive index loads")
+	}
+	if strings.Contains(tb.renderTableWithTheme(140, true, 0, defaultUITheme()), "Bak") {
+		t.Fatal("Bak column should be hidden without an archive index")
+	}
+
+	tb.setBackedUp(map[string]bool{"alice/b": true})
+	if !strings.Contains(tb.renderTableWithTheme(140, true, 0, defaultUITheme()), "Bak") {
+		t.Fatal("expected Bak column once the archive index is loaded")
+	}
+	tb.cycleBackupFilter()
+	if got := filteredNames(tb); len(got) != 1 || got[0] != "alice/b" {
+		t.Fatalf("backed-up filter: unexpected rows %v", got)
+	}
+	tb.cycleBackupFilter()
+	if got := filteredNames(tb); len(got) != 2 || got[0] != "alice/a" || got[1] != "alice/c" {
+		t.Fatalf("not-backed-up filter: unexpected rows %v", got)
+	}
+	if !strings.HasSuffix(tb.filterLabel(), "[not backed up]") {
+		t.Fatalf("unexpected filter label %q", tb.filterLabel())
+	}
+	tb.cycleBackupFilter()
+	if got := filteredNames(tb); len(got) != 3 {
+		t.Fatalf("expected all rows after cycling back, got %v", got)
+	}
+}
+
+func TestHorizontalScrollRevealsLongCellsAndClamps(t *testing.T) {
+	desc := strings.Repeat("x", 60) + "TAILEND"
+	tb := newRepoTable([]planfile.RepoRecord{
+		{FullName: "alice/a", Description: desc},
+		{FullName: "alice/b", Description: "short"},
+	})
+	tb.setHeight(20)
+	if out := tb.renderTableWithTheme(140, true, 0, defaultUITheme()); strings.Contains(out, "TAILEND") {
+		t.Fatalf("expected long description to be truncated before scrolling:\n%s", out)
+	}
+	for i := 0; i < 200; i++ {
+		if !applyTableAction(&tb, "scroll_right", 0) {
+			t.Fatal("scroll_right not handled")
+		}
+	}
+	if tb.hScroll != len(desc)-1 {
+		t.Fatalf("expected offset clamped to %d, got %d", len(desc)-1, tb.hScroll)
+	}
+	if out := tb.renderTableWithTheme(140, true, 0, defaultUITheme()); !strings.Contains(out, "TAILEN") {
+		t.Fatalf("expected scrolled description tail:\n%s", out)
+	}
+	if tb.cursor != 0 {
+		t.Fatalf("horizontal scroll moved the cursor to %d", tb.cursor)
+	}
+	applyTableAction(&tb, "scroll_left", 0)
+	if tb.hScroll != len(desc)-2 {
+		t.Fatalf("expected scroll_left to step back, got %d", tb.hScroll)
+	}
+}
+
+func TestInvertFilteredTogglesOnlyFilteredRows(t *testing.T) {
+	tb := newRepoTable([]planfile.RepoRecord{
+		{FullName: "alice/keep-a"},
+		{FullName: "alice/keep-b"},
+		{FullName: "alice/other"},
+	})
+	tb.selected["alice/keep-a"] = true
+	tb.selected["alice/other"] = true
+	for _, ch := range "keep" {
+		tb.appendFilterChar(string(ch))
+	}
+	if !applyTableAction(&tb, "invert_filtered", 0) {
+		t.Fatal("invert_filtered not handled")
+	}
+	if tb.selected["alice/keep-a"] || !tb.selected["alice/keep-b"] || !tb.selected["alice/other"] || len(tb.selected) != 2 {
+		t.Fatalf("unexpected selection after invert: %v", tb.selected)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -19,8 +20,10 @@ const (
 	restoreStageNone restoreStage = iota
 	restoreStageBrowseArchive
 	restoreStageSelectRepo
+	restoreStageInputOwner
 	restoreStageAskUseOriginal
 	restoreStageInputNewName
+	restoreStageConfirmVisibility
 )
 
 type restoreState struct {
@@ -37,10 +40,29 @@ type restoreState struct {
 	repos       []restoreRepoItem
 	selected    restoreRepoItem
 	repoHScroll int
+	// targetOwner is the user or org to restore into, prefilled from
+	// RestoreDefaultOwner.
+	targetOwner string
+	// targetVisibility is the restored repo's visibility; empty means private.
+	targetVisibility string
+	// targetName and allowVisibilityChange let a confirmed visibility change
+	// resubmit the same restore.
+	targetName            string
+	allowVisibilityChange bool
 
 	promptInput string
 }
 
+// restoreVisibilities is the order v cycles the target visibility through.
+var restoreVisibilities = []string{"private", "internal", "public"}
+
+func (s restoreState) visibility() string {
+	if s.targetVisibility == "" {
+		return restoreVisibilities[0]
+	}
+	return s.targetVisibility
+}
+
 type browserItem struct {
 	label      string
 	path       string
@@ -52,6 +74,9 @@ type restoreRepoItem struct {
 	fullName   string
 	sourceKind string
 	sourcePath string
+	sourceSHA  string
+	// sourceVisibility is the visibility recorded in the archive, if any.
+	sourceVisibility string
 }
 
 func (m *appModel) startRestoreFlow() tea.Cmd {
@@ -162,7 +187,7 @@ func (m appModel) updateRestoreFlow(key string) (tea.Model, tea.Cmd) {
 					if !ok {
 						continue
 					}
-					repos = append(repos, restoreRepoItem{fullName: e.FullName, sourceKind: src.Kind, sourcePath: src.Path})
+					repos = append(repos, restoreRepoItem{fullName: e.FullName, sourceKind: src.Kind, sourcePath: src.Path, sourceSHA: src.SHA256, sourceVisibility: e.Visibility})
 				}
 				if len(repos) == 0 {
 					m.status = "No restorable repos found in archive"
@@ -207,24 +232,30 @@ func (m appModel) updateRestoreFlow(key string) (tea.Model, tea.Cmd) {
 			}
 		case "right", "l":
 			s.repoHScroll++
+		case "v":
+			i := slices.Index(restoreVisibilities, s.visibility())
+			s.targetVisibility = restoreVisibilities[(i+1)%len(restoreVisibilities)]
+			m.status = "Restore: target visibility " + s.targetVisibility
 		case "enter":
 			if len(s.repos) == 0 {
 				break
 			}
 			s.selected = s.repos[s.repoCursor]
-			s.promptInput = ""
-			s.stage = restoreStageAskUseOriginal
-			m.status = "Use original name? (yes/no)"
+			if s.targetOwner == "" {
+				s.targetOwner = strings.TrimSpace(m.callbacks.RestoreDefaultOwner)
+			}
+			s.stage = restoreStageInputOwner
+			m.status = "Restore: choose target owner"
 			m.restoreState = s
-			return m, m.openRestoreYesNoModal()
+			return m, m.openRestoreOwnerModal(s.targetOwner)
 		}
 		m.restoreState = s
 		return m, nil
-	case restoreStageAskUseOriginal:
+	case restoreStageInputOwner, restoreStageAskUseOriginal:
 		// Input is handled in modal key routing.
 		m.restoreState = s
 		return m, nil
-	case restoreStageInputNewName:
+	case restoreStageInputNewName, restoreStageConfirmVisibility:
 		// Input is handled in modal key routing.
 		m.restoreState = s
 		return m, nil
@@ -249,6 +280,16 @@ func parseYesNo(v string) (bool, bool) {
 	}
 }
 
+func validateRestoreOwner(owner string) error {
+	if owner == "" {
+		return fmt.Errorf("target owner is required")
+	}
+	if strings.ContainsAny(owner, "/ \t") {
+		return fmt.Errorf("invalid target owner %q", owner)
+	}
+	return nil
+}
+
 func originalRepoName(fullName string) string {
 	parts := strings.SplitN(fullName, "/", 2)
 	if len(parts) == 2 {
@@ -257,12 +298,13 @@ func originalRepoName(fullName string) string {
 	return fullName
 }
 
-func (m appModel) submitRestore(targetName string) (tea.Cmd, error) {
+func (m *appModel) submitRestore(targetName string) (tea.Cmd, error) {
 	if m.callbacks.Restore == nil {
 		return nil, fmt.Errorf("restore callback unavailable")
 	}
+	m.restoreState.targetName = targetName
 	s := m.restoreState
-	owner := strings.TrimSpace(m.callbacks.RestoreDefaultOwner)
+	owner := s.targetOwner
 	if owner == "" {
 		return nil, fmt.Errorf("restore owner is empty")
 	}
@@ -271,12 +313,17 @@ func (m appModel) submitRestore(targetName string) (tea.Cmd, error) {
 		RepoFullName:     s.selected.fullName,
 		SourceKind:       s.selected.sourceKind,
 		SourcePath:       s.selected.sourcePath,
+		SourceSHA256:     s.selected.sourceSHA,
 		TargetOwner:      owner,
 		TargetName:       targetName,
-		TargetVisibility: "private",
+		TargetVisibility: s.visibility(),
+		SourceVisibility: s.selected.sourceVisibility,
+		// Set only after the user confirmed a VisibilityDowngradeError.
+		AllowVisibilityChange: s.allowVisibilityChange,
 	}
+	restoreFn := m.callbacks.Restore
 	return func() tea.Msg {
-		out, err := m.callbacks.Restore(req)
+		out, err := restoreFn(req)
 		return commandResultMsg{output: out, err: err, refreshRepos: err == nil}
 	}, nil
 }
@@ -312,11 +359,13 @@ func (m appModel) renderRestorePanel(width, height int) string {
 			lines = append(lines, prefix+label)
 		}
 		lines = append(lines, "", "Keys: j/k move, enter choose, esc back")
-	case restoreStageAskUseOriginal, restoreStageInputNewName:
+	case restoreStageInputOwner, restoreStageAskUseOriginal, restoreStageInputNewName, restoreStageConfirmVisibility:
 		lines = append(lines,
 			"Archive: "+s.archiveRoot,
 			"Repo: "+s.selected.fullName,
 			"Source: "+s.selected.sourceKind,
+			"Owner: "+s.targetOwner,
+			"Visibility: "+s.visibility(),
 			"",
 			"Popup input is active.",
 		)
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	restorepkg "gh-manager/internal/restore"
 )
 
@@ -61,3 +63,101 @@ func TestStartRestoreFlowUsesConfiguredPath(t *testing.T) {
 		t.Fatalf("expected restore browser modal to be active")
 	}
 }
+
+func TestRestoreFlowOwnerPromptThreadsTargetOwner(t *testing.T) {
+	var got RestoreRequest
+	m := newAppModel(nil, AppCallbacks{
+		RestoreDefaultOwner: "alice",
+		Restore: func(req RestoreRequest) (string, error) {
+			got = req
+			return "ok", nil
+		},
+	})
+	m.modalActive = true
+	m.modalKind = modalRestoreSelectRepo
+	m.restoreState = restoreState{
+		active:      true,
+		stage:       restoreStageSelectRepo,
+		archiveRoot: "/tmp/archive",
+		repos:       []restoreRepoItem{{fullName: "alice/demo", sourceKind: "bundle"}},
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(appModel)
+	if m.modalKind != modalRestoreOwner || m.restoreState.promptInput != "alice" {
+		t.Fatalf("expected owner prompt prefilled with default, got kind=%v input=%q", m.modalKind, m.restoreState.promptInput)
+	}
+	for range "alice" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+		m = updated.(appModel)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(appModel)
+	if m.modalKind != modalRestoreOwner || m.status != "Error: target owner is required" {
+		t.Fatalf("expected empty owner to be rejected, got kind=%v status=%q", m.modalKind, m.status)
+	}
+	for _, k := range []string{"m", "y", "-", "o", "r", "g"} {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = updated.(appModel)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(appModel)
+	if m.modalKind != modalRestoreYesNo || m.restoreState.targetOwner != "my-org" {
+		t.Fatalf("expected yes/no prompt for my-org, got kind=%v owner=%q", m.modalKind, m.restoreState.targetOwner)
+	}
+	m.restoreState.promptInput = "yes"
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected restore command, status=%q", updated.(appModel).status)
+	}
+	cmd()
+	if got.TargetOwner != "my-org" || got.TargetName != "demo" {
+		t.Fatalf("unexpected restore request: %+v", got)
+	}
+}
+
+func TestRestoreFlowConfirmsVisibilityChange(t *testing.T) {
+	var reqs []RestoreRequest
+	m := newAppModel(nil, AppCallbacks{
+		Restore: func(req RestoreRequest) (string, error) {
+			reqs = append(reqs, req)
+			if !req.AllowVisibilityChange {
+				return "", restorepkg.VisibilityDowngradeError{Source: req.SourceVisibility, Target: req.TargetVisibility}
+			}
+			return "ok", nil
+		},
+	})
+	m.modalActive = true
+	m.modalKind = modalRestoreSelectRepo
+	m.restoreState = restoreState{
+		active:      true,
+		stage:       restoreStageSelectRepo,
+		targetOwner: "alice",
+		repos:       []restoreRepoItem{{fullName: "alice/demo", sourceKind: "bundle", sourceVisibility: "private"}},
+	}
+	for _, k := range []string{"v", "v"} {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = updated.(appModel)
+	}
+	if m.restoreState.visibility() != "public" {
+		t.Fatalf("expected v to cycle to public, got %q", m.restoreState.visibility())
+	}
+	m.restoreState.selected = m.restoreState.repos[0]
+	cmd, err := m.submitRestore("demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(appModel)
+	if m.modalKind != modalRestoreVisibility || m.restoreState.stage != restoreStageConfirmVisibility {
+		t.Fatalf("expected visibility confirmation, got kind=%v stage=%v", m.modalKind, m.restoreState.stage)
+	}
+	m.restoreState.promptInput = "yes"
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected the restore to be resubmitted, status=%q", updated.(appModel).status)
+	}
+	cmd()
+	if len(reqs) != 2 || !reqs[1].AllowVisibilityChange || reqs[1].TargetName != "demo" || reqs[1].SourceVisibility != "private" {
+		t.Fatalf("unexpected restore requests: %+v", reqs)
+	}
+}
@@ -1,3 +1,33 @@
 package version
 
+import "runtime"
+
 var Value = "0.1.2-dev"
+
+// Commit and BuildDate are set by the release build via
+// -ldflags "-X gh-manager/internal/version.Commit=... -X ...BuildDate=...".
+var (
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// Info is the build metadata printed by `version --output json`.
+type Info struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+}
+
+func Current() Info {
+	return Info{
+		Version:   Value,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+	}
+}
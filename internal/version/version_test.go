@@ -0,0 +1,18 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestCurrentReportsBuildMetadata(t *testing.T) {
+	oldCommit, oldDate := Commit, BuildDate
+	t.Cleanup(func() { Commit, BuildDate = oldCommit, oldDate })
+	Commit, BuildDate = "abc1234", "2026-10-16T00:00:00Z"
+
+	got := Current()
+	want := Info{Version: Value, GoVersion: runtime.Version(), OS: runtime.GOOS, Arch: runtime.GOARCH, Commit: "abc1234", BuildDate: "2026-10-16T00:00:00Z"}
+	if got != want {
+		t.Fatalf("got %+v want %+v", got, want)
+	}
+}